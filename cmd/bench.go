@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"wtfsiw/internal/ai"
+	"wtfsiw/internal/cli"
+	"wtfsiw/internal/config"
+	"wtfsiw/internal/netutil"
+)
+
+var (
+	benchCount int
+	benchPlain bool
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench <query>",
+	Short: "Compare Claude and OpenAI on the same query",
+	Long: `Run one query through every configured AI provider and compare them.
+
+Each provider extracts search parameters from the query and generates
+recommendations for it; the timings and results are shown side by side.
+Providers without an API key are skipped, whichever one is the default.
+
+Examples:
+  wtfsiw bench "cozy mysteries set in England"
+  wtfsiw bench "90s action movies" -n 3`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBench,
+}
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+	benchCmd.Flags().IntVarP(&benchCount, "number", "n", 5, "number of recommendations per provider (1-10)")
+	benchCmd.Flags().BoolVarP(&benchPlain, "plain", "p", false, "disable colors (for scripting)")
+}
+
+// benchProviders returns a provider for each AI with an API key, and notes for
+// any that were skipped
+func benchProviders() (map[string]ai.Provider, []string) {
+	cfg := config.Get()
+	providers := make(map[string]ai.Provider)
+	var skipped []string
+
+	add := func(name, key string, build func() ai.Provider) {
+		if key == "" {
+			skipped = append(skipped, fmt.Sprintf("%s: no API key configured", name))
+			return
+		}
+		if t := cfg.AI.Temperature; t != nil && *t > ai.MaxTemperature(name) {
+			skipped = append(skipped, fmt.Sprintf("%s: ai.temperature %g is above its maximum of %g", name, *t, ai.MaxTemperature(name)))
+			return
+		}
+		providers[name] = build()
+	}
+	add("claude", cfg.AI.ClaudeAPIKey, func() ai.Provider { return ai.NewClaudeProvider(cfg.AI.ClaudeAPIKey) })
+	add("openai", cfg.AI.OpenAIAPIKey, func() ai.Provider { return ai.NewOpenAIProvider(cfg.AI.OpenAIAPIKey) })
+	return providers, skipped
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	query := args[0]
+	if benchCount < 1 {
+		benchCount = 1
+	} else if benchCount > 10 {
+		benchCount = 10
+	}
+
+	providers, skipped := benchProviders()
+	for _, note := range skipped {
+		if benchPlain {
+			fmt.Println("Skipping " + note)
+		} else {
+			cli.PrintNote("Skipping " + note)
+		}
+	}
+	if len(providers) == 0 {
+		return fmt.Errorf("no AI provider is configured\n\nRun 'wtfsiw config' for setup instructions")
+	}
+
+	var results []cli.BenchResult
+	_ = runStep(benchPlain, fmt.Sprintf("Running %q on %d provider(s)", query, len(providers)), func() error {
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for _, name := range []string{"claude", "openai"} {
+			provider, ok := providers[name]
+			if !ok {
+				continue
+			}
+			wg.Add(1)
+			go func(name string, provider ai.Provider) {
+				defer wg.Done()
+				result := benchProvider(name, provider, query, benchCount)
+				mu.Lock()
+				results = append(results, result)
+				mu.Unlock()
+			}(name, provider)
+		}
+		wg.Wait()
+		return nil
+	})
+
+	// Keep a stable column order regardless of which finished first
+	if len(results) == 2 && results[0].Provider != "claude" {
+		results[0], results[1] = results[1], results[0]
+	}
+
+	fmt.Println()
+	cli.PrintBenchResults(results, !benchPlain)
+	return nil
+}
+
+// benchProvider times extraction and recommendation for one provider. Errors
+// are recorded in the result rather than stopping the comparison.
+func benchProvider(name string, provider ai.Provider, query string, count int) cli.BenchResult {
+	result := cli.BenchResult{Provider: name}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	start := time.Now()
+	params, err := provider.ExtractSearchParams(ctx, query)
+	result.ExtractTime = time.Since(start)
+	if err != nil {
+		result.ExtractErr = netutil.Friendly(err)
+	} else {
+		data, _ := json.Marshal(params)
+		result.Params = string(data)
+	}
+
+	start = time.Now()
+	resp, err := provider.GetRecommendations(ctx, query, count)
+	result.RecommendTime = time.Since(start)
+	if err != nil {
+		result.RecommendErr = netutil.Friendly(err)
+	} else {
+		result.Recommendations = resp.Recommendations
+	}
+	return result
+}
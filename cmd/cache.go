@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"wtfsiw/internal/cache"
+	"wtfsiw/internal/config"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the local TMDb cache",
+	Long: `Manage the local cache of TMDb lookups.
+
+Streaming provider availability is cached for 24 hours per title and
+region, so repeated searches don't re-fetch it. Identical search and
+discover queries are reused for 10 minutes unless
+preferences.cache_responses is false. A scan of preferences.library_path
+is kept for 6 hours, OMDb ratings and TMDb's image configuration for 7
+days, and the embeddings 'trakt pick' uses to rank your watchlist for
+30 days.
+
+Cache location: ~/.config/wtfsiw/cache
+
+A history of how each title could be watched (streaming or only rent/buy)
+is kept separately in ~/.config/wtfsiw/availability, and isn't cleared.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Cache directory:", config.GetCacheDir())
+		fmt.Println()
+		fmt.Println("Use 'wtfsiw cache clear' to remove all cached data")
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all cached data",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		removed, err := cache.Clear(config.GetCacheDir())
+		if err != nil {
+			return fmt.Errorf("failed to clear cache: %w", err)
+		}
+		fmt.Printf("Cleared %d cached entries\n", removed)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+}
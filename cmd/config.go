@@ -15,17 +15,27 @@ var configCmd = &cobra.Command{
 
 Configuration file location: ~/.config/wtfsiw/config.yaml
 
+Profiles: settings in ~/.config/wtfsiw/profiles/<name>.yaml override the
+base config when selected with --profile <name> or WTFSIW_PROFILE, so people
+sharing a machine can keep their own providers, region, and tastes. With a
+profile selected, 'config set' writes to the profile.
+
 Required API keys:
   - TMDb API key (free): https://developer.themoviedb.org/
   - Claude API key: https://console.anthropic.com/
   - OpenAI API key (optional): https://platform.openai.com/
+  - OMDb API key (optional, for IMDb and Rotten Tomatoes ratings): https://www.omdbapi.com/apikey.aspx
 
 You can also set these via environment variables:
   - TMDB_API_KEY
   - ANTHROPIC_API_KEY
-  - OPENAI_API_KEY`,
+  - OPENAI_API_KEY
+  - OMDB_API_KEY`,
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("Configuration file:", config.GetConfigPath())
+		if name := config.ActiveProfile(); name != "" {
+			fmt.Printf("Profile: %s (%s)\n", name, config.GetProfilePath(name))
+		}
 		fmt.Println()
 		fmt.Println("Current settings:")
 		cfg := config.Get()
@@ -33,10 +43,20 @@ You can also set these via environment variables:
 		fmt.Printf("  Claude API Key: %s\n", maskKey(cfg.AI.ClaudeAPIKey))
 		fmt.Printf("  OpenAI API Key: %s\n", maskKey(cfg.AI.OpenAIAPIKey))
 		fmt.Printf("  TMDb API Key: %s\n", maskKey(cfg.TMDB.APIKey))
+		fmt.Printf("  OMDb API Key: %s\n", maskKey(cfg.OMDB.APIKey))
 		fmt.Printf("  Trakt Client ID: %s\n", maskKey(cfg.Trakt.ClientID))
 		fmt.Printf("  Trakt Access Token: %s\n", maskKey(cfg.Trakt.AccessToken))
 		fmt.Printf("  Region: %s\n", cfg.Preferences.Region)
 		fmt.Printf("  Language: %s\n", cfg.Preferences.Language)
+		fmt.Printf("  Theme: %s\n", cfg.Preferences.Theme)
+		fmt.Printf("  Kids Mode: %t\n", cfg.Preferences.KidsMode)
+		fmt.Printf("  Include Adult: %t\n", cfg.Preferences.IncludeAdult)
+		if len(cfg.Preferences.Providers) > 0 {
+			fmt.Printf("  Providers: %s\n", joinStrings(cfg.Preferences.Providers, ", "))
+		}
+		if cfg.Preferences.PrimaryProvider != "" {
+			fmt.Printf("  Primary Provider: %s\n", cfg.Preferences.PrimaryProvider)
+		}
 		fmt.Println()
 		fmt.Println("Use 'wtfsiw config set <key> <value>' to update settings")
 	},
@@ -51,14 +71,41 @@ Available keys:
   ai.provider          - AI provider to use (claude or openai)
   ai.claude_api_key    - Anthropic Claude API key
   ai.openai_api_key    - OpenAI API key
+  ai.temperature       - Recommendation creativity (0-1 for Claude, 0-2 for OpenAI; unset = provider default)
+  ai.disabled_tools    - Chat tools to turn off, comma-separated (e.g., "generate_recommendations,get_trakt_history")
   tmdb.api_key         - TMDb API key
+  omdb.api_key         - OMDb API key (adds IMDb and Rotten Tomatoes ratings)
   trakt.client_id      - Trakt API client ID
   trakt.client_secret  - Trakt API client secret
   trakt.access_token   - Trakt access token (use 'wtfsiw trakt auth' instead)
   preferences.region   - Region for streaming providers (e.g., US, GB)
   preferences.language - Language code (e.g., en, es)
   preferences.min_rating - Minimum rating filter (0-10)
-  preferences.max_results - Maximum results to show
+  preferences.theme    - Color theme (mocha, latte, dracula, none)
+  preferences.kids_mode - Always use kids mode (true/false)
+  preferences.include_adult - Include adult titles in TMDb results (true/false, ignored in kids mode)
+  preferences.collapse_duplicates - Merge same-titled movie/TV cards in chat (true/false)
+  preferences.ai_fallback - Use AI suggestions when a TMDb search finds nothing (true/false, --no-fallback)
+  preferences.auto_title - Let the AI name chat sessions after a couple of exchanges (true/false)
+  preferences.ai_count - Default number of AI recommendations (default 5)
+  preferences.search_count - Default number of TMDb search results (default 10)
+  preferences.cache_responses - Reuse identical TMDb searches for 10 minutes (true/false)
+  preferences.foreign_audio - Foreign-language titles: original, subbed, dubbed, or empty for no preference (--subbed, --dubbed)
+  preferences.library_path - Local media folder; results you already have are marked "In your library"
+  preferences.min_popularity - Hide titles below this TMDb popularity score (e.g., 5; 0 = off)
+  preferences.exclude_genres - Genres never shown, comma-separated (e.g., "horror,reality")
+  preferences.blocked_titles - Titles or franchises never shown, comma-separated (e.g., "Fast & Furious,Transformers")
+  preferences.primary_provider - Service to favor when titles are on several (e.g., "Netflix"), or "rotate" to spread picks across services
+  preferences.title_suggestions - Suggest matching titles as you type in the TUI, Tab to complete (true/false; needs TMDb)
+  preferences.show_search_filters - Show the filters each chat search used above its results (true/false)
+  preferences.chat_max_messages - Summarize the oldest chat messages once a conversation has more than this many (default 60, 0 = never)
+  preferences.never_recommend - Things you never want recommended, comma-separated (e.g., "musicals,jump-scare horror")
+  preferences.diverse - Always limit results sharing a franchise, director, or decade (true/false, --diverse)
+  preferences.max_per_collection - With diverse results, at most this many from one collection (default 1, 0 = no cap)
+  preferences.max_per_director - With diverse results, at most this many by one director or creator (default 2, 0 = no cap)
+  preferences.max_per_decade - With diverse results, at most this many from one decade (default 0 = no cap)
+  preferences.binge_time - Show how long a TV show takes to watch in full, e.g. "≈ 22 hours total" (true/false)
+  preferences.providers - Your streaming services, comma-separated (e.g., "Netflix,Max"); searches use them unless a query names others
 
 Examples:
   wtfsiw config set tmdb.api_key abc123
@@ -73,6 +120,10 @@ Examples:
 			return fmt.Errorf("failed to set config: %w", err)
 		}
 
+		if name := config.ActiveProfile(); name != "" {
+			fmt.Printf("Set %s = %s in profile %s\n", key, maskKey(value), name)
+			return nil
+		}
 		fmt.Printf("Set %s = %s\n", key, maskKey(value))
 		return nil
 	},
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"wtfsiw/internal/ai"
+	"wtfsiw/internal/cli"
+	"wtfsiw/internal/config"
+	"wtfsiw/internal/tmdb"
+)
+
+var (
+	coverageCount int
+	coveragePlain bool
+)
+
+var coverageCmd = &cobra.Command{
+	Use:   "coverage <query>",
+	Short: "Show which streaming services carry the most matches for a query",
+	Long: `Count how many of a query's matches each streaming service carries.
+
+The query is searched on TMDb across every service (your
+preferences.providers don't narrow it), each match's providers are looked
+up for your region, and the services are ranked by how many matches they
+stream. Only subscription and free streaming count; "none" is matches you
+could only rent or buy. When preferences.providers is set, your services
+are marked and their combined share is shown, to help decide which
+subscriptions are worth keeping.
+
+Examples:
+  wtfsiw coverage "acclaimed prestige TV dramas"
+  wtfsiw coverage "80s horror" -n 40`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCoverage,
+}
+
+func init() {
+	rootCmd.AddCommand(coverageCmd)
+	coverageCmd.Flags().IntVarP(&coverageCount, "number", "n", 20, "number of matches to count (1-40)")
+	coverageCmd.Flags().BoolVarP(&coveragePlain, "plain", "p", false, "disable animations and colors (for scripting)")
+}
+
+func runCoverage(cmd *cobra.Command, args []string) error {
+	query := args[0]
+	if coverageCount < 1 {
+		coverageCount = 1
+	} else if coverageCount > 40 {
+		coverageCount = 40
+	}
+
+	aiProvider, err := ai.NewProvider()
+	if err != nil {
+		return fmt.Errorf("failed to initialize AI: %w\n\nRun 'wtfsiw config' for setup instructions", err)
+	}
+	tmdbClient, err := tmdb.NewClient()
+	if err != nil {
+		return err
+	}
+
+	if coveragePlain {
+		fmt.Printf("Checking coverage for: %s\n\n", query)
+	} else {
+		cli.PrintHeader(query)
+	}
+
+	var params *ai.SearchParams
+	var clarification *ai.ClarificationError
+	err = runStep(coveragePlain, "Analyzing with AI", func() error {
+		var err error
+		params, err = aiProvider.ExtractSearchParams(context.Background(), query)
+		if errors.As(err, &clarification) {
+			return nil // reported below
+		}
+		return err
+	})
+	if err != nil {
+		return nil
+	}
+	if clarification != nil {
+		printNote(coveragePlain, clarification.Error())
+		return nil
+	}
+	if !params.HasFilters() {
+		printNote(coveragePlain, "No searchable filters found in the query; try naming a genre, year, or similar title")
+		return nil
+	}
+	params.Limit = coverageCount
+	params.AllProviders = true
+
+	var results []tmdb.Media
+	err = runStep(coveragePlain, "Searching TMDb", func() error {
+		resp, err := tmdbClient.Discover(params)
+		if err != nil {
+			return err
+		}
+		results = resp.Results
+		return nil
+	})
+	if err != nil {
+		return nil
+	}
+
+	// One request per match, in parallel
+	_ = runStep(coveragePlain, "Fetching providers", func() error {
+		tmdbClient.EnrichWithProviders(results)
+		return nil
+	})
+
+	region := config.Get().Preferences.Region
+	if region == "" {
+		region = "US"
+	}
+	fmt.Println()
+	cli.PrintCoverage(tmdbClient.Coverage(results), region, !coveragePlain)
+	return nil
+}
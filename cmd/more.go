@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"wtfsiw/internal/ai"
+	"wtfsiw/internal/cli"
+	"wtfsiw/internal/netutil"
+	"wtfsiw/internal/tmdb"
+	"wtfsiw/internal/trakt"
+)
+
+var (
+	moreSources int
+	moreResults int
+	morePlain   bool
+)
+
+// historyScanLimit is how many history entries are fetched to find
+// source titles and to filter out things that were already watched
+const historyScanLimit = 100
+
+var moreCmd = &cobra.Command{
+	Use:   "more",
+	Short: "Recommend titles similar to what you recently watched",
+	Long: `Recommend titles similar to what you recently watched on Trakt.
+
+Pulls your most recently watched movies and shows from Trakt, finds
+similar titles on TMDb for each, skips anything you've already watched,
+and ranks the rest by how many of your recent watches they relate to.
+
+Requires both Trakt (wtfsiw trakt auth) and TMDb to be configured.
+
+Examples:
+  wtfsiw more               # based on your last 5 watched titles
+  wtfsiw more -s 2 -n 5     # based on your last 2, show 5 results`,
+	Args: cobra.NoArgs,
+	RunE: runMore,
+}
+
+func init() {
+	rootCmd.AddCommand(moreCmd)
+	moreCmd.Flags().IntVarP(&moreSources, "sources", "s", 5, "number of recently watched titles to draw from")
+	moreCmd.Flags().IntVarP(&moreResults, "number", "n", 10, "number of recommendations (1-20)")
+	moreCmd.Flags().BoolVarP(&morePlain, "plain", "p", false, "disable animations and colors (for scripting)")
+}
+
+// similarCandidate is a similar title along with the watched titles that led to it
+type similarCandidate struct {
+	media   tmdb.Media
+	because []string
+}
+
+func runMore(cmd *cobra.Command, args []string) error {
+	traktClient, err := trakt.NewClient()
+	if err != nil {
+		return err
+	}
+	tmdbClient, err := tmdb.NewClient()
+	if err != nil {
+		return err
+	}
+
+	if moreSources < 1 {
+		moreSources = 1
+	}
+	if moreResults < 1 {
+		moreResults = 1
+	} else if moreResults > 20 {
+		moreResults = 20
+	}
+
+	var history []trakt.HistoryItem
+	err = runStep(morePlain, "Fetching your Trakt history", func() error {
+		var err error
+		history, err = traktClient.GetHistory("", historyScanLimit)
+		return err
+	})
+	if err != nil {
+		// The spinner reports the error; --plain has none, so say it here
+		if morePlain {
+			fmt.Fprintln(os.Stderr, netutil.Friendly(err))
+		}
+		return nil
+	}
+
+	// Collect everything already watched, and the most recent distinct titles as sources
+	watched := make(map[string]bool)
+	var sources []trakt.HistoryItem
+	for _, item := range history {
+		if item.GetTMDBID() == 0 {
+			continue
+		}
+		key := fmt.Sprintf("%s-%d", item.GetTMDBMediaType(), item.GetTMDBID())
+		if watched[key] {
+			continue
+		}
+		watched[key] = true
+		if len(sources) < moreSources {
+			sources = append(sources, item)
+		}
+	}
+
+	if len(sources) == 0 {
+		fmt.Println("No watch history found on Trakt.")
+		return nil
+	}
+
+	sourceTitles := make([]string, len(sources))
+	for i, s := range sources {
+		sourceTitles[i] = s.GetDisplayTitle()
+	}
+	query := "because you watched " + strings.Join(sourceTitles, ", ")
+	if morePlain {
+		fmt.Printf("Finding titles %s\n\n", query)
+	} else {
+		cli.PrintHeader(query)
+	}
+
+	var candidates []*similarCandidate
+	_ = runStep(morePlain, "Finding similar titles", func() error {
+		candidates = collectSimilar(tmdbClient, sources, watched)
+		return nil
+	})
+
+	if len(candidates) > moreResults {
+		candidates = candidates[:moreResults]
+	}
+
+	results := make([]tmdb.Media, len(candidates))
+	for i, c := range candidates {
+		results[i] = c.media
+	}
+	_ = runStep(morePlain, "Fetching providers", func() error {
+		tmdbClient.FillMissingOverviews(results)
+		tmdbClient.EnrichWithProviders(results)
+		return nil
+	})
+
+	recommendations := make([]ai.Recommendation, len(results))
+	for i, media := range results {
+		recommendations[i] = ai.RecommendationFromMedia(media)
+		recommendations[i].WhyWatch = "Because you watched " + strings.Join(candidates[i].because, ", ")
+	}
+
+	addOMDbRatings(morePlain, recommendations)
+
+	fmt.Println()
+	printRecommendations(morePlain, fmt.Sprintf("Found %d titles similar to your recent watches", len(recommendations)), recommendations)
+
+	return nil
+}
+
+// collectSimilar fetches similar titles for each source, skipping watched titles,
+// and ranks them by how many sources they relate to, then by rating and popularity
+func collectSimilar(client *tmdb.Client, sources []trakt.HistoryItem, watched map[string]bool) []*similarCandidate {
+	byKey := make(map[string]*similarCandidate)
+	var candidates []*similarCandidate
+
+	for _, source := range sources {
+		resp, err := client.GetSimilar(source.GetTMDBMediaType(), source.GetTMDBID())
+		if err != nil {
+			continue
+		}
+		for _, media := range resp.Results {
+			key := fmt.Sprintf("%s-%d", media.MediaType, media.ID)
+			if watched[key] {
+				continue
+			}
+			if c, ok := byKey[key]; ok {
+				c.because = append(c.because, source.GetDisplayTitle())
+				continue
+			}
+			c := &similarCandidate{media: media, because: []string{source.GetDisplayTitle()}}
+			byKey[key] = c
+			candidates = append(candidates, c)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if len(candidates[i].because) != len(candidates[j].because) {
+			return len(candidates[i].because) > len(candidates[j].because)
+		}
+		scoreI := candidates[i].media.VoteAverage * (1 + candidates[i].media.Popularity/100)
+		scoreJ := candidates[j].media.VoteAverage * (1 + candidates[j].media.Popularity/100)
+		return scoreI > scoreJ
+	})
+
+	return candidates
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"wtfsiw/internal/ai"
+	"wtfsiw/internal/cli"
+	"wtfsiw/internal/textutil"
+	"wtfsiw/internal/trakt"
+)
+
+var (
+	pickCount int
+	pickPlain bool
+)
+
+// pickCandidates is how many of the best-matching watchlist items the AI chooses from
+const pickCandidates = 10
+
+var traktPickCmd = &cobra.Command{
+	Use:   "pick <mood>",
+	Short: "Pick something from your Trakt watchlist for a mood",
+	Long: `Pick what to watch from your Trakt watchlist based on a mood.
+
+Your watchlist is ranked by similarity to the mood, and the AI makes the
+final pick from the closest matches. With OpenAI, ranking compares
+embeddings (cached per title, so each is computed once); with Claude,
+which has no embeddings API, it matches keywords in titles, genres, and
+overviews.
+
+Examples:
+  wtfsiw trakt pick "something light and funny"
+  wtfsiw trakt pick "slow-burn mystery" -n 1`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPick,
+}
+
+func init() {
+	traktCmd.AddCommand(traktPickCmd)
+	traktPickCmd.Flags().IntVarP(&pickCount, "number", "n", 3, "number of picks (1-10)")
+	traktPickCmd.Flags().BoolVarP(&pickPlain, "plain", "p", false, "disable animations and colors (for scripting)")
+}
+
+func runPick(cmd *cobra.Command, args []string) error {
+	mood := args[0]
+	if pickCount < 1 {
+		pickCount = 1
+	} else if pickCount > pickCandidates {
+		pickCount = pickCandidates
+	}
+
+	traktClient, err := trakt.NewClient()
+	if err != nil {
+		return err
+	}
+	aiProvider, err := ai.NewProvider()
+	if err != nil {
+		return fmt.Errorf("failed to initialize AI: %w\n\nRun 'wtfsiw config' for setup instructions", err)
+	}
+
+	if pickPlain {
+		fmt.Printf("Picking from your watchlist: %s\n\n", mood)
+	} else {
+		cli.PrintHeader(mood)
+	}
+
+	var items []trakt.WatchlistItem
+	err = runStep(pickPlain, "Fetching your watchlist", func() error {
+		var err error
+		items, err = traktClient.GetWatchlist("")
+		return err
+	})
+	if err != nil {
+		return nil
+	}
+	if len(items) == 0 {
+		fmt.Println("Your watchlist is empty.")
+		return nil
+	}
+
+	ctx := context.Background()
+
+	var candidates []trakt.WatchlistItem
+	_ = runStep(pickPlain, "Ranking your watchlist", func() error {
+		docs := make([]ai.RankDoc, len(items))
+		for i := range items {
+			docs[i] = watchlistDoc(&items[i])
+		}
+		for _, i := range ai.RankByMood(ctx, aiProvider, mood, docs) {
+			if len(candidates) == pickCandidates {
+				break
+			}
+			candidates = append(candidates, items[i])
+		}
+		return nil
+	})
+
+	var picks []ai.Recommendation
+	_ = runStep(pickPlain, "Picking", func() error {
+		picks = pickFromCandidates(ctx, aiProvider, mood, candidates, pickCount)
+		return nil
+	})
+
+	addOMDbRatings(pickPlain, picks)
+
+	fmt.Println()
+	printRecommendations(pickPlain, fmt.Sprintf("Picked from your watchlist for: %s", mood), picks)
+	return nil
+}
+
+// watchlistDoc describes a watchlist item for ranking
+func watchlistDoc(item *trakt.WatchlistItem) ai.RankDoc {
+	// Not every Trakt item is linked to TMDb; don't let those share a "movie-0" cache entry
+	var key string
+	if id := item.GetTMDBID(); id != 0 {
+		key = item.GetTMDBMediaType() + "-" + strconv.Itoa(id)
+	} else if id := item.GetTraktID(); id != 0 {
+		key = "trakt-" + item.GetTMDBMediaType() + "-" + strconv.Itoa(id)
+	}
+	text := item.GetDisplayTitle()
+	if genres := item.GetGenres(); len(genres) > 0 {
+		text += ". Genres: " + strings.Join(genres, ", ")
+	}
+	if overview := item.GetOverview(); overview != "" {
+		text += ". " + overview
+	}
+	return ai.RankDoc{Key: key, Text: text}
+}
+
+// pickFromCandidates asks the AI to choose count titles from candidates only.
+// Anything the AI suggests from outside the list is dropped; if nothing
+// usable comes back, the top-ranked candidates are returned instead.
+func pickFromCandidates(ctx context.Context, provider ai.Provider, mood string, candidates []trakt.WatchlistItem, count int) []ai.Recommendation {
+	byTitle := make(map[string]trakt.WatchlistItem)
+	var list strings.Builder
+	for _, item := range candidates {
+		byTitle[strings.ToLower(item.GetDisplayTitle())] = item
+		fmt.Fprintf(&list, "- %s (%d): %s\n", item.GetDisplayTitle(), item.GetDisplayYear(), textutil.Truncate(item.GetOverview(), 150))
+	}
+
+	query := fmt.Sprintf("Choose from ONLY these titles on my watchlist (no others), the ones that best fit this mood: %s\n\n%s", mood, list.String())
+	var picks []ai.Recommendation
+	if resp, err := provider.GetRecommendations(ctx, query, count); err == nil {
+		for _, rec := range resp.Recommendations {
+			item, ok := byTitle[strings.ToLower(rec.Title)]
+			if !ok {
+				continue
+			}
+			pick := watchlistRecommendation(item)
+			pick.WhyWatch = rec.WhyWatch
+			picks = append(picks, pick)
+			delete(byTitle, strings.ToLower(rec.Title))
+		}
+	}
+
+	if len(picks) == 0 {
+		for _, item := range candidates {
+			if len(picks) == count {
+				break
+			}
+			picks = append(picks, watchlistRecommendation(item))
+		}
+	}
+	return picks
+}
+
+// watchlistRecommendation converts a watchlist item into a Recommendation
+func watchlistRecommendation(item trakt.WatchlistItem) ai.Recommendation {
+	year := ""
+	if y := item.GetDisplayYear(); y > 0 {
+		year = strconv.Itoa(y)
+	}
+	return ai.Recommendation{
+		Title:     item.GetDisplayTitle(),
+		Year:      year,
+		MediaType: item.GetTMDBMediaType(),
+		Rating:    item.GetRating(),
+		Genres:    item.GetGenres(),
+		Overview:  item.GetOverview(),
+	}
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"wtfsiw/internal/picks"
+)
+
+var picksCmd = &cobra.Command{
+	Use:   "picks",
+	Short: "Manage your local list of saved titles",
+	Long: `Manage "my picks", a local list of titles saved for later.
+
+It's a lightweight watchlist that doesn't need Trakt: press p on a result
+in the TUI (or on a selected card in chat) to add it. Each pick keeps its
+title, year, TMDb ID, and the date it was added.
+
+Stored in ~/.config/wtfsiw/picks.json
+
+Examples:
+  wtfsiw picks list
+  wtfsiw picks remove "Past Lives"
+  wtfsiw picks remove 2`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPicksList()
+	},
+}
+
+var picksListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List your picks",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPicksList()
+	},
+}
+
+var picksRemoveCmd = &cobra.Command{
+	Use:   "remove <title or number>",
+	Short: "Remove a title from your picks",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list, err := picks.Load()
+		if err != nil {
+			return err
+		}
+		entry, ok := list.Remove(args[0])
+		if !ok {
+			return fmt.Errorf("%q isn't in your picks (see wtfsiw picks list)", args[0])
+		}
+		if err := list.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Removed %s (%s) from your picks\n", entry.Title, entry.Year)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(picksCmd)
+	picksCmd.AddCommand(picksListCmd)
+	picksCmd.AddCommand(picksRemoveCmd)
+}
+
+func runPicksList() error {
+	list, err := picks.Load()
+	if err != nil {
+		return err
+	}
+	if len(list.Entries) == 0 {
+		fmt.Println("No picks yet. Press p on a result in the TUI or chat to save one.")
+		return nil
+	}
+
+	fmt.Println("My picks:")
+	fmt.Println()
+	for i, e := range list.Entries {
+		mediaType := "MOVIE"
+		if e.MediaType == "tv" {
+			mediaType = "TV"
+		}
+		fmt.Printf("  %d. [%s] %s (%s) - added %s\n", i+1, mediaType, e.Title, e.Year, e.AddedAt.Format("2006-01-02"))
+	}
+	return nil
+}
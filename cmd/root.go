@@ -2,22 +2,46 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"wtfsiw/internal/ai"
 	"wtfsiw/internal/cli"
 	"wtfsiw/internal/config"
+	"wtfsiw/internal/library"
+	"wtfsiw/internal/netutil"
+	"wtfsiw/internal/omdb"
+	"wtfsiw/internal/theme"
 	"wtfsiw/internal/tmdb"
 	"wtfsiw/internal/trakt"
 	"wtfsiw/internal/tui"
 )
 
 var (
-	numResults int
-	plainMode  bool
+	numResults   int
+	plainMode    bool
+	kidsMode     bool
+	noProviders  bool
+	noFallback   bool
+	adventurous  bool
+	subbed       bool
+	dubbed       bool
+	tuiMode      bool
+	verbose      bool
+	diverse      bool
+	profileName  string
+	quality      string
+	outputFormat string
+	contextFile  string
+
+	// formatter prints the results when --format is set
+	formatter *cli.Formatter
 )
 
 var rootCmd = &cobra.Command{
@@ -32,35 +56,116 @@ Examples:
   wtfsiw "something dark and psychological like Breaking Bad"
   wtfsiw "feel-good comedy from the 90s"
   wtfsiw "Korean thriller, recent, highly rated" -n 5
-  wtfsiw  # launches interactive mode`,
+  wtfsiw --kids "funny animal movie"
+  wtfsiw "best sci-fi of the 80s" --no-providers
+  wtfsiw --adventurous "weird indie horror"
+  wtfsiw --tui "korean thrillers"  # browse results interactively
+  wtfsiw "heist movies" --format '{{.Title}} ({{.Year}}) {{.Rating}}'
+  wtfsiw  # launches interactive mode
+
+Set WTFSIW_TUI=1 to always use --tui.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runMain,
 }
 
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, netutil.Friendly(err))
 		os.Exit(1)
 	}
 }
 
 func init() {
 	cobra.OnInitialize(initConfig)
-	rootCmd.Flags().IntVarP(&numResults, "number", "n", 10, "number of recommendations (1-10)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "use the named config profile from ~/.config/wtfsiw/profiles (or $WTFSIW_PROFILE)")
+	rootCmd.Flags().IntVarP(&numResults, "number", "n", 0, "number of recommendations, 1-20 (default: preferences.ai_count or preferences.search_count)")
 	rootCmd.Flags().BoolVarP(&plainMode, "plain", "p", false, "disable animations and colors (for scripting)")
+	rootCmd.Flags().BoolVar(&noProviders, "no-providers", false, "skip streaming provider lookups (faster)")
+	rootCmd.Flags().BoolVar(&noFallback, "no-fallback", false, "don't fall back to AI suggestions when TMDb finds nothing")
+	rootCmd.Flags().BoolVar(&kidsMode, "kids", false, "family-friendly results only (max PG / TV-PG, no horror or thrillers)")
+	rootCmd.Flags().BoolVar(&adventurous, "adventurous", false, "raise the AI temperature for less obvious picks this run")
+	rootCmd.Flags().BoolVar(&subbed, "subbed", false, "foreign-language titles: original audio with English subtitles is fine")
+	rootCmd.Flags().BoolVar(&dubbed, "dubbed", false, "foreign-language titles: prefer ones with an English dub")
+	rootCmd.Flags().BoolVar(&tuiMode, "tui", false, "show results in the interactive results view instead of printing them (or $WTFSIW_TUI=1)")
+	rootCmd.Flags().StringVar(&quality, "quality", "", "result quality preset: mainstream, critic (rated 8+), or hidden-gem (well rated, little seen)")
+	rootCmd.Flags().BoolVar(&diverse, "diverse", false, "limit results from the same franchise, director, or decade (see preferences.max_per_*)")
+	rootCmd.Flags().BoolVar(&verbose, "verbose", false, "show diagnostics, such as chat calls to tools that don't exist or provider filters without a region")
+	rootCmd.Flags().StringVar(&contextFile, "context", "", "text file about your taste (e.g. a list of favorites) to tailor recommendations to")
+	rootCmd.Flags().StringVar(&outputFormat, "format", "", "print results as oneline, csv, tsv, or a Go template like '{{.Title}} ({{.Year}})' (implies --plain)")
+	rootCmd.MarkFlagsMutuallyExclusive("subbed", "dubbed")
+	rootCmd.MarkFlagsMutuallyExclusive("tui", "plain")
+	rootCmd.MarkFlagsMutuallyExclusive("tui", "format")
 }
 
 func initConfig() {
+	// --profile (or WTFSIW_PROFILE) loads a named profile over the base config
+	name := profileName
+	if name == "" {
+		name = os.Getenv("WTFSIW_PROFILE")
+	}
+	if err := config.SetProfile(name); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
 	if err := config.Init(); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 	}
+
+	palette := theme.Get(config.Get().Preferences.Theme)
+	tui.ApplyTheme(palette)
+	cli.ApplyTheme(palette)
 }
 
 func runMain(cmd *cobra.Command, args []string) error {
-	// Initialize AI provider (required for both modes)
-	aiProvider, err := ai.NewProvider()
-	if err != nil {
-		return fmt.Errorf("failed to initialize AI: %w\n\nRun 'wtfsiw config' for setup instructions", err)
+	// --kids turns on kids mode for this run (preferences.kids_mode makes it permanent)
+	if kidsMode {
+		config.Get().Preferences.KidsMode = true
+	}
+	if noFallback {
+		config.Get().Preferences.AIFallback = false
+	}
+	if diverse {
+		config.Get().Preferences.Diverse = true
+	}
+	// --subbed/--dubbed override preferences.foreign_audio for this run
+	if subbed {
+		config.Get().Preferences.ForeignAudio = "subbed"
+	}
+	if dubbed {
+		config.Get().Preferences.ForeignAudio = "dubbed"
+	}
+	if quality != "" {
+		if _, ok := tmdb.QualityPresets[strings.ToLower(quality)]; !ok {
+			return fmt.Errorf("unknown --quality %q: use %s", quality, strings.Join(tmdb.QualityNames, ", "))
+		}
+	}
+	// --format is for scripting: validate it before any API calls, and keep
+	// progress output out of the formatted results
+	if outputFormat != "" {
+		f, err := cli.NewFormatter(outputFormat)
+		if err != nil {
+			return err
+		}
+		formatter = f
+		plainMode = true
+	}
+	// --context sends a taste document with every prompt this run
+	if contextFile != "" {
+		text, truncated, err := ai.LoadContextFile(contextFile)
+		if err != nil {
+			return err
+		}
+		if truncated {
+			fmt.Fprintf(os.Stderr, "Warning: %s is over %d KB, only the start of it is used\n", contextFile, ai.MaxContextBytes/1024)
+		}
+		ai.SetUserContext(text)
+	}
+	// --adventurous raises ai.temperature for this run (never lowers it)
+	if adventurous {
+		aiCfg := &config.Get().AI
+		t := ai.AdventurousTemperature(aiCfg.Provider)
+		if aiCfg.Temperature == nil || *aiCfg.Temperature < t {
+			aiCfg.Temperature = &t
+		}
 	}
 
 	// Initialize TMDb client (optional - if not configured, use AI-only mode)
@@ -70,8 +175,32 @@ func runMain(cmd *cobra.Command, args []string) error {
 		tmdbClient = nil
 	}
 
+	// Initialize AI provider (required, except for a query with only TMDb
+	// configured: that's looked up as a title instead)
+	aiProvider, err := ai.NewProvider()
+	if err != nil {
+		titleLookup := tmdbClient != nil && len(args) > 0 && !useTUI()
+		if ai.APIKeyConfigured() || !titleLookup {
+			return setupError(err, tmdbClient != nil)
+		}
+		aiProvider = nil
+	}
+
+	// --tui opens the results view, skipping the input screen when a query is given
+	if useTUI() {
+		if len(args) > 0 {
+			return tui.Run(aiProvider, tmdbClient, args[0])
+		}
+		return tui.Run(aiProvider, tmdbClient, "")
+	}
+
 	// If query provided as argument, run non-interactive CLI mode
 	if len(args) > 0 {
+		// Daily check for titles recorded with 'wtfsiw watch-for'
+		checkWatchForDue(tmdbClient)
+		if verbose && tmdbClient != nil {
+			tmdbClient.SetDiagnostics(os.Stderr)
+		}
 		return runNonInteractive(aiProvider, tmdbClient, args[0], plainMode)
 	}
 
@@ -79,6 +208,43 @@ func runMain(cmd *cobra.Command, args []string) error {
 	return runChatMode(aiProvider, tmdbClient)
 }
 
+// setupError explains what to configure when the AI provider can't be
+// created. A missing API key gets the commands to set one, and the TMDb-only
+// title lookup is pointed out when it's available.
+func setupError(aiErr error, tmdbConfigured bool) error {
+	if ai.APIKeyConfigured() {
+		// Something other than the key, e.g. an out-of-range ai.temperature
+		return fmt.Errorf("failed to initialize AI: %w\n\nRun 'wtfsiw config' for setup instructions", aiErr)
+	}
+
+	if tmdbConfigured {
+		return fmt.Errorf("%w\n\nChat and the TUI need an AI provider. With just TMDb, "+
+			"a query is looked up as a title: wtfsiw \"The Matrix\"", aiErr)
+	}
+
+	keyHelp := "  wtfsiw config set ai.claude_api_key YOUR_KEY   (get one at https://console.anthropic.com/)"
+	if config.Get().AI.Provider == "openai" {
+		keyHelp = "  wtfsiw config set ai.openai_api_key YOUR_KEY   (get one at https://platform.openai.com/)"
+	}
+	return fmt.Errorf("no API keys configured. wtfsiw needs an AI provider to understand requests:\n%s\n\n"+
+		"and a free TMDb key for real search results and streaming providers:\n"+
+		"  wtfsiw config set tmdb.api_key YOUR_KEY   (get one at https://developer.themoviedb.org/)\n\n"+
+		"TMDb alone is enough to look up titles. Run 'wtfsiw config' for all options", keyHelp)
+}
+
+// useTUI reports whether results should open in the interactive results view,
+// via --tui or WTFSIW_TUI (handy for shell aliases)
+func useTUI() bool {
+	if tuiMode {
+		return true
+	}
+	if plainMode {
+		return false
+	}
+	enabled, _ := strconv.ParseBool(os.Getenv("WTFSIW_TUI"))
+	return enabled
+}
+
 func runChatMode(aiProvider ai.Provider, tmdbClient *tmdb.Client) error {
 	// Initialize chat provider
 	chatProvider, err := ai.NewChatProvider()
@@ -94,112 +260,268 @@ func runChatMode(aiProvider ai.Provider, tmdbClient *tmdb.Client) error {
 	}
 
 	// Launch chat TUI
+	tui.SetVerbose(verbose)
 	return tui.RunChat(chatProvider, tmdbClient, traktClient, aiProvider)
 }
 
+// maxMoreResults caps how many search results beyond -n are kept for the
+// "Show N more?" prompt
+const maxMoreResults = 20
+
 func runNonInteractive(aiProvider ai.Provider, tmdbClient *tmdb.Client, query string, plain bool) error {
 	ctx := context.Background()
 
-	// Validate and clamp numResults to 1-10
+	// Without -n, use the configured default for the mode; otherwise clamp to 1-20
+	prefs := config.Get().Preferences
+	if numResults == 0 {
+		if tmdbClient == nil {
+			numResults = prefs.GetAICount()
+		} else {
+			numResults = prefs.GetSearchCount()
+		}
+	}
 	if numResults < 1 {
 		numResults = 1
-	} else if numResults > 10 {
-		numResults = 10
+	} else if numResults > 20 {
+		numResults = 20
 	}
 
 	// Print header
 	if plain {
-		fmt.Printf("Searching for: %s\n\n", query)
+		fmt.Fprintf(statusOut(), "Searching for: %s\n\n", query)
 	} else {
 		cli.PrintHeader(query)
 	}
 
 	var recommendations []ai.Recommendation
 	var summary string
+	var more []tmdb.Media // search results past numResults, offered after the rest
+	var nowStreaming []tmdb.StreamingChange
 
 	// Helper to run with optional spinner
 	runWithSpinner := func(msg string, fn func() error) error {
-		if plain {
-			fmt.Println(msg + "...")
-			return fn()
-		}
-		spinner := cli.NewSpinner(msg + "...")
-		spinner.Start()
-		err := fn()
-		if err != nil {
-			spinner.Stop()
-			cli.PrintError(err)
-			return err
-		}
-		spinner.StopWithMessage(msg + " done")
-		return nil
+		return runStep(plain, msg, fn)
 	}
 
-	if tmdbClient == nil {
-		// AI-only mode
+	// askAI is the structured recommendation path. It's used directly in
+	// AI-only mode, and as the fallback when a TMDb search can't answer the query.
+	askAI := func() error {
 		var resp *ai.RecommendationResponse
+		var clarification *ai.ClarificationError
 		err := runWithSpinner("Asking AI for recommendations", func() error {
 			var err error
 			resp, err = aiProvider.GetRecommendations(ctx, query, numResults)
+			if errors.As(err, &clarification) {
+				return nil // reported below
+			}
 			return err
 		})
 		if err != nil {
-			return nil
+			return err
+		}
+		if clarification != nil {
+			// There's no chat loop to answer in, so pass the question on
+			printNote(plain, clarification.Error())
+			printNote(plain, "Try a more specific query, or run wtfsiw with no arguments to chat.")
+			return clarification
 		}
 		recommendations = resp.Recommendations
 		summary = resp.Summary
+		return nil
+	}
+
+	if tmdbClient == nil {
+		// AI-only mode
+		if err := askAI(); err != nil {
+			return nil
+		}
 	} else {
-		// TMDb mode
+		// TMDb mode. Falls back to askAI when the query is insufficient for a
+		// search: the model replied with a question instead of params, the
+		// params have no searchable filters (e.g. only a mood), or TMDb found nothing.
+		// Disabled with --no-fallback or preferences.ai_fallback.
+		// Without an AI provider, the query is looked up as a title instead.
+		allowFallback := config.Get().Preferences.AIFallback && aiProvider != nil
 		var params *ai.SearchParams
-		err := runWithSpinner("Analyzing with AI", func() error {
-			var err error
-			params, err = aiProvider.ExtractSearchParams(ctx, query)
-			return err
-		})
-		if err != nil {
-			return nil
+		var clarification *ai.ClarificationError
+		if aiProvider != nil {
+			err := runWithSpinner("Analyzing with AI", func() error {
+				var err error
+				params, err = aiProvider.ExtractSearchParams(ctx, query)
+				if errors.As(err, &clarification) {
+					return nil // handled by the fallback below
+				}
+				return err
+			})
+			if err != nil {
+				return nil
+			}
+		}
+		// --quality overrides any preset the model picked from the query
+		if params != nil && quality != "" {
+			params.Quality = quality
 		}
 
-		var resp *tmdb.SearchResponse
-		err = runWithSpinner("Searching TMDb", func() error {
-			var err error
-			resp, err = tmdbClient.Discover(params)
-			return err
-		})
-		if err != nil {
-			return nil
+		var results []tmdb.Media
+		fallbackNote := ""
+		switch {
+		case aiProvider == nil:
+			printNote(plain, "No AI provider configured, looking the query up as a title")
+			var resp *tmdb.SearchResponse
+			err := runWithSpinner("Searching TMDb", func() error {
+				var err error
+				resp, err = tmdbClient.Search(query)
+				return err
+			})
+			if err != nil {
+				return nil
+			}
+			results = resp.Results
+		case clarification != nil:
+			if !allowFallback {
+				printNote(plain, clarification.Error())
+				return nil
+			}
+			fallbackNote = "The query is too open-ended for a TMDb search, asking the AI directly"
+		case !params.HasFilters() && allowFallback:
+			fallbackNote = "No searchable filters found in the query, asking the AI directly"
+		default:
+			params.Limit = numResults + maxMoreResults
+
+			var resp *tmdb.SearchResponse
+			err := runWithSpinner("Searching TMDb", func() error {
+				var err error
+				resp, err = tmdbClient.Discover(params)
+				return err
+			})
+			if err != nil {
+				return nil
+			}
+			results = resp.Results
+			if len(results) == 0 && allowFallback {
+				fallbackNote = "No TMDb matches, asking the AI directly"
+			}
 		}
 
-		_ = runWithSpinner("Fetching providers", func() error {
-			tmdbClient.EnrichWithProviders(resp.Results)
-			return nil
-		})
+		if fallbackNote != "" {
+			printNote(plain, fallbackNote)
+			if err := askAI(); err != nil {
+				return nil
+			}
+			summary = "AI suggestions, not TMDb matches: " + summary
+		} else {
+			// Limit to requested number, keeping the rest to offer afterwards
+			if len(results) > numResults {
+				more = results[numResults:]
+				results = results[:numResults]
+			}
 
-		// Limit to requested number
-		results := resp.Results
-		if len(results) > numResults {
-			results = results[:numResults]
-		}
+			tmdbClient.FillMissingOverviews(results)
 
-		for _, media := range results {
-			providers := make([]string, len(media.Providers))
-			for j, p := range media.Providers {
-				providers[j] = p.Name
+			// One request per result, so this is the slowest step
+			if !noProviders {
+				_ = runWithSpinner("Fetching providers", func() error {
+					tmdbClient.EnrichWithProviders(results)
+					tmdbClient.RankByProviderPreference(results)
+					return nil
+				})
+				nowStreaming = tmdbClient.NewlyStreaming(results)
 			}
-			recommendations = append(recommendations, ai.Recommendation{
-				Title:     media.GetDisplayTitle(),
-				Year:      media.GetDisplayYear(),
-				MediaType: media.MediaType,
-				Rating:    media.VoteAverage,
-				Overview:  media.Overview,
-				Providers: providers,
-				VoteCount: media.VoteCount,
-			})
+			if config.Get().Preferences.BingeTime {
+				_ = runWithSpinner("Estimating binge times", func() error {
+					tmdbClient.EnrichWithBingeTime(results)
+					return nil
+				})
+			}
+
+			for _, media := range results {
+				recommendations = append(recommendations, ai.RecommendationFromMedia(media))
+			}
+			summary = fmt.Sprintf("Found %d matches", len(recommendations))
 		}
-		summary = fmt.Sprintf("Found %d matches", len(recommendations))
 	}
 
+	addOMDbRatings(plain, recommendations)
+
+	fmt.Fprintln(statusOut())
+	printRecommendations(plain, summary, recommendations)
+	printStreamingChanges(nowStreaming)
+
+	// Scripting mode stays non-interactive
+	if len(more) > 0 && !plain && cli.Confirm(fmt.Sprintf("Show %d more?", len(more))) {
+		printMoreResults(tmdbClient, more, len(recommendations))
+	}
+
+	return nil
+}
+
+// printStreamingChanges notes results that could only be rented or bought
+// when they last came up, and are now included with a service
+func printStreamingChanges(changes []tmdb.StreamingChange) {
+	for _, c := range changes {
+		fmt.Fprintf(statusOut(), "🔔 %s (%s) is now streaming on %s (it was rent or buy only before)\n",
+			c.Media.GetDisplayTitle(), c.Media.GetDisplayYear(), joinStrings(c.Providers, ", "))
+	}
+	if len(changes) > 0 {
+		fmt.Fprintln(statusOut())
+	}
+}
+
+// printMoreResults prints search results that were held back by -n,
+// numbered on from the ones already shown
+func printMoreResults(tmdbClient *tmdb.Client, more []tmdb.Media, shown int) {
+	var nowStreaming []tmdb.StreamingChange
+	tmdbClient.FillMissingOverviews(more)
+	if !noProviders {
+		_ = runStep(false, "Fetching providers", func() error {
+			tmdbClient.EnrichWithProviders(more)
+			tmdbClient.RankByProviderPreference(more)
+			return nil
+		})
+		nowStreaming = tmdbClient.NewlyStreaming(more)
+	}
+
+	recommendations := make([]ai.Recommendation, len(more))
+	for i, media := range more {
+		recommendations[i] = ai.RecommendationFromMedia(media)
+	}
+	addOMDbRatings(false, recommendations)
+
+	lib := library.Get()
 	fmt.Println()
+	for i, rec := range recommendations {
+		rec.InLibrary = lib.Has(rec.Title, rec.Year)
+		cli.PrintRecommendation(shown+i+1, rec, false)
+	}
+	printStreamingChanges(nowStreaming)
+}
+
+// addOMDbRatings looks up IMDb and Rotten Tomatoes ratings for the results
+// when an OMDb API key is configured, and does nothing otherwise
+func addOMDbRatings(plain bool, recommendations []ai.Recommendation) {
+	omdbClient, err := omdb.NewClient()
+	if err != nil || len(recommendations) == 0 {
+		return
+	}
+	_ = runStep(plain, "Fetching ratings", func() error {
+		ai.AddOMDbRatings(omdbClient, recommendations)
+		return nil
+	})
+}
+
+// printRecommendations prints results in either plain or styled format
+func printRecommendations(plain bool, summary string, recommendations []ai.Recommendation) {
+	lib := library.Get()
+	for i := range recommendations {
+		recommendations[i].InLibrary = lib.Has(recommendations[i].Title, recommendations[i].Year)
+	}
+
+	if formatter != nil {
+		if err := formatter.Print(os.Stdout, recommendations); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		return
+	}
 
 	if len(recommendations) == 0 {
 		if plain {
@@ -207,10 +529,9 @@ func runNonInteractive(aiProvider ai.Provider, tmdbClient *tmdb.Client, query st
 		} else {
 			cli.PrintNoResults()
 		}
-		return nil
+		return
 	}
 
-	// Print results
 	if plain {
 		fmt.Printf("%s\n\n", summary)
 		for i, rec := range recommendations {
@@ -218,10 +539,13 @@ func runNonInteractive(aiProvider ai.Provider, tmdbClient *tmdb.Client, query st
 			if rec.MediaType == "tv" {
 				mediaType = "TV"
 			}
-			fmt.Printf("%d. [%s] %s (%s) - %.1f/10\n", i+1, mediaType, rec.Title, rec.Year, rec.Rating)
+			fmt.Printf("%d. [%s] %s (%s) - %s\n", i+1, mediaType, rec.Title, rec.Year, rec.RatingSummary())
 			if len(rec.Providers) > 0 {
 				fmt.Printf("   Watch on: %s\n", joinStrings(rec.Providers, ", "))
 			}
+			if rec.InLibrary {
+				fmt.Println("   In your library")
+			}
 			if rec.WhyWatch != "" {
 				fmt.Printf("   Why: %s\n", rec.WhyWatch)
 			}
@@ -233,10 +557,44 @@ func runNonInteractive(aiProvider ai.Provider, tmdbClient *tmdb.Client, query st
 		fmt.Println()
 		cli.PrintResults(recommendations, true)
 	}
+}
 
+// printNote prints an informational line in either plain or styled format
+func printNote(plain bool, msg string) {
+	if plain {
+		fmt.Fprintf(statusOut(), "Note: %s\n", msg)
+		return
+	}
+	cli.PrintNote(msg)
+}
+
+// runStep runs fn, showing a spinner (or a plain progress line) with msg
+func runStep(plain bool, msg string, fn func() error) error {
+	if plain {
+		fmt.Fprintln(statusOut(), msg+"...")
+		return fn()
+	}
+	spinner := cli.NewSpinner(msg + "...")
+	spinner.Start()
+	err := fn()
+	if err != nil {
+		spinner.Stop()
+		cli.PrintError(netutil.Friendly(err))
+		return err
+	}
+	spinner.StopWithMessage(msg + " done")
 	return nil
 }
 
+// statusOut is where plain progress lines and notes go: stderr with --format,
+// so only the formatted results reach a pipe
+func statusOut() io.Writer {
+	if formatter != nil {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
 func joinStrings(strs []string, sep string) string {
 	if len(strs) == 0 {
 		return ""
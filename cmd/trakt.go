@@ -5,7 +5,9 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"wtfsiw/internal/cli"
 	"wtfsiw/internal/config"
+	"wtfsiw/internal/textutil"
 	"wtfsiw/internal/trakt"
 )
 
@@ -170,10 +172,7 @@ Usage:
 
 			// Overview (truncated)
 			if overview != "" {
-				if len(overview) > 150 {
-					overview = overview[:147] + "..."
-				}
-				fmt.Printf("   %s\n", overview)
+				fmt.Printf("   %s\n", textutil.Truncate(overview, 150))
 			}
 
 			// IDs for reference
@@ -190,8 +189,39 @@ Usage:
 	},
 }
 
+var traktStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show a summary of your Trakt watchlist",
+	Long: `Show aggregate stats for your Trakt watchlist: total runtime, the
+movie/show split, top genres, average rating, and the oldest and newest
+titles.
+
+Show runtime counts every aired episode.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := trakt.NewClient()
+		if err != nil {
+			return err
+		}
+
+		items, err := client.GetWatchlist("")
+		if err != nil {
+			return fmt.Errorf("failed to get watchlist: %w", err)
+		}
+
+		if len(items) == 0 {
+			fmt.Println("Your watchlist is empty.")
+			return nil
+		}
+
+		cli.PrintWatchlistStats(trakt.ComputeWatchlistStats(items))
+		return nil
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(traktCmd)
 	traktCmd.AddCommand(traktAuthCmd)
 	traktCmd.AddCommand(traktWatchlistCmd)
+	traktCmd.AddCommand(traktStatsCmd)
 }
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"wtfsiw/internal/tmdb"
+	"wtfsiw/internal/watchfor"
+)
+
+var (
+	watchForProviders []string
+	watchForList      bool
+	watchForRemove    bool
+	watchForCheck     bool
+)
+
+var watchForCmd = &cobra.Command{
+	Use:   "watch-for [title]",
+	Short: "Get notified when a title becomes available to stream",
+	Long: `Record a title and get notified once it can be watched.
+
+The title is looked up on TMDb and saved with its TMDb ID. On later runs
+of wtfsiw (at most once a day per title), its watch providers in your
+region are checked, and a notice is printed when it starts streaming
+(included with a subscription or free; rent and buy don't count). Only
+your preferences.providers count when that's set; use --on to pick
+specific services for a title instead.
+
+Examples:
+  wtfsiw watch-for "Dune Part Three"
+  wtfsiw watch-for "The Bear" --on Hulu --on "Disney Plus"
+  wtfsiw watch-for --list
+  wtfsiw watch-for --check              # check everything now
+  wtfsiw watch-for --remove "Dune Part Three"`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWatchFor,
+}
+
+func init() {
+	rootCmd.AddCommand(watchForCmd)
+	watchForCmd.Flags().StringArrayVar(&watchForProviders, "on", nil, "only notify when available on this provider (repeatable)")
+	watchForCmd.Flags().BoolVarP(&watchForList, "list", "l", false, "list titles being watched for")
+	watchForCmd.Flags().BoolVarP(&watchForRemove, "remove", "r", false, "stop watching for the given title")
+	watchForCmd.Flags().BoolVar(&watchForCheck, "check", false, "check all titles now, ignoring the daily interval")
+}
+
+func runWatchFor(cmd *cobra.Command, args []string) error {
+	list, err := watchfor.Load()
+	if err != nil {
+		return err
+	}
+
+	if watchForList {
+		printWatchForList(list)
+		return nil
+	}
+
+	if watchForRemove {
+		if len(args) == 0 {
+			return fmt.Errorf("title is required with --remove")
+		}
+		entry, ok := list.Remove(args[0])
+		if !ok {
+			return fmt.Errorf("not watching for %q (see wtfsiw watch-for --list)", args[0])
+		}
+		if err := list.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Stopped watching for %s (%s)\n", entry.Title, entry.Year)
+		return nil
+	}
+
+	tmdbClient, err := tmdb.NewClient()
+	if err != nil {
+		return err
+	}
+
+	if watchForCheck {
+		if len(list.Entries) == 0 {
+			fmt.Println("Not watching for any titles.")
+			return nil
+		}
+		notices := checkWatchFor(tmdbClient, list, true)
+		if len(notices) == 0 {
+			fmt.Println("Nothing new is available yet.")
+		}
+		return nil
+	}
+
+	if len(args) == 0 {
+		return cmd.Help()
+	}
+
+	resp, err := tmdbClient.Search(args[0])
+	if err != nil {
+		return fmt.Errorf("TMDb search failed: %w", err)
+	}
+	if len(resp.Results) == 0 {
+		return fmt.Errorf("no TMDb match for %q", args[0])
+	}
+	media := resp.Results[0]
+
+	// Store current names so "HBO Max" is listed (and matched) as "Max"
+	providers := make([]string, len(watchForProviders))
+	for i, p := range watchForProviders {
+		providers[i] = tmdb.CanonicalProviderName(p)
+	}
+
+	entry := watchfor.Entry{
+		TMDBID:    media.ID,
+		MediaType: media.MediaType,
+		Title:     media.GetDisplayTitle(),
+		Year:      media.GetDisplayYear(),
+		Providers: providers,
+		AddedAt:   time.Now(),
+	}
+	if !list.Add(entry) {
+		fmt.Printf("Already watching for %s (%s)\n", entry.Title, entry.Year)
+		return nil
+	}
+	if err := list.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("Watching for %s (%s)\n", entry.Title, entry.Year)
+
+	// It may already be available; check right away so the user knows
+	checkWatchFor(tmdbClient, list, true)
+	return nil
+}
+
+// checkWatchFor checks watched-for titles, prints a notice for each newly
+// available one, and saves the updated state. Errors are silent so this can
+// run at the start of other commands without getting in the way.
+func checkWatchFor(tmdbClient *tmdb.Client, list *watchfor.List, force bool) []watchfor.Notice {
+	notices, changed := list.Check(tmdbClient, force)
+	if changed {
+		_ = list.Save()
+	}
+
+	for _, n := range notices {
+		fmt.Printf("🔔 %s (%s) is now available on %s\n", n.Entry.Title, n.Entry.Year, joinStrings(n.Providers, ", "))
+		if n.Link != "" {
+			fmt.Printf("   %s\n", n.Link)
+		}
+	}
+	if len(notices) > 0 {
+		fmt.Println()
+	}
+	return notices
+}
+
+// checkWatchForDue runs the daily watch-for check if there is anything to check
+func checkWatchForDue(tmdbClient *tmdb.Client) {
+	if tmdbClient == nil {
+		return
+	}
+	list, err := watchfor.Load()
+	if err != nil || len(list.Entries) == 0 {
+		return
+	}
+	checkWatchFor(tmdbClient, list, false)
+}
+
+func printWatchForList(list *watchfor.List) {
+	if len(list.Entries) == 0 {
+		fmt.Println("Not watching for any titles.")
+		return
+	}
+
+	fmt.Println("Watching for:")
+	fmt.Println()
+	for _, e := range list.Entries {
+		mediaType := "MOVIE"
+		if e.MediaType == "tv" {
+			mediaType = "TV"
+		}
+		status := "waiting"
+		if e.NotifiedAt != nil {
+			status = "available since " + e.NotifiedAt.Format("2006-01-02")
+		} else if !e.LastChecked.IsZero() {
+			status = "last checked " + e.LastChecked.Format("2006-01-02")
+		}
+		fmt.Printf("  [%s] %s (%s) - %s\n", mediaType, e.Title, e.Year, status)
+		if len(e.Providers) > 0 {
+			fmt.Printf("         on: %s\n", joinStrings(e.Providers, ", "))
+		}
+	}
+}
@@ -3,6 +3,7 @@ package ai
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"wtfsiw/internal/ai/tools"
@@ -34,47 +35,197 @@ type ChatProvider interface {
 // NewChatProvider creates a new chat provider based on config
 func NewChatProvider() (ChatProvider, error) {
 	cfg := config.Get()
+	if err := tools.CheckNames(cfg.AI.DisabledTools); err != nil {
+		return nil, fmt.Errorf("ai.disabled_tools: %w", err)
+	}
 
 	switch cfg.AI.Provider {
 	case "claude":
 		if cfg.AI.ClaudeAPIKey == "" {
 			return nil, fmt.Errorf("Claude API key not configured. Set ANTHROPIC_API_KEY or run: wtfsiw config set ai.claude_api_key YOUR_KEY")
 		}
+		if err := validateTemperature(cfg.AI.Provider); err != nil {
+			return nil, err
+		}
 		return NewClaudeChatProvider(cfg.AI.ClaudeAPIKey), nil
 	case "openai":
 		if cfg.AI.OpenAIAPIKey == "" {
 			return nil, fmt.Errorf("OpenAI API key not configured. Set OPENAI_API_KEY or run: wtfsiw config set ai.openai_api_key YOUR_KEY")
 		}
+		if err := validateTemperature(cfg.AI.Provider); err != nil {
+			return nil, err
+		}
 		return NewOpenAIChatProvider(cfg.AI.OpenAIAPIKey), nil
 	default:
 		return nil, fmt.Errorf("unknown AI provider: %s", cfg.AI.Provider)
 	}
 }
 
-// Chat system prompt
-const chatSystemPrompt = `You are a helpful movie and TV show recommendation assistant called "wtfsiw" (What The Fuck Should I Watch).
-
-You have access to tools to help users find content to watch:
-- search_media: Search TMDb for movies/TV shows with filters (genre, year, rating, language, streaming service, actors, studios)
-- get_media_details: Get detailed info about a specific title
-- get_streaming_providers: Check where something is available to watch
-- get_similar: Find similar movies/shows to a given title
-- search_by_title: Find a specific title by name
-- get_trakt_watchlist: View the user's Trakt watchlist (if connected)
-- get_trakt_history: View the user's watch history (if connected)
-- generate_recommendations: Generate AI recommendations directly for complex/mood-based requests
-
-When helping users:
-1. Use search_media for discovery requests with specific criteria
-2. Use search_by_title first when users mention a specific title, then get_similar for recommendations
-3. Use get_streaming_providers to show where they can watch something
-4. Use generate_recommendations for subjective requests that don't map well to filters
+// Chat system prompt. The tool list and the rules for using them are added
+// by getChatSystemPrompt, covering only the tools offered to the model.
+const chatSystemPrompt = `You are a helpful movie and TV show recommendation assistant called "wtfsiw" (What The Fuck Should I Watch).`
+
+// chatPromptFormat follows the tool list and rules in the chat system prompt
+const chatPromptFormat = `
 
 Format your responses clearly:
 - Use numbered lists for multiple recommendations
 - Include ratings (out of 10) and where to watch
 - Explain why each recommendation matches their request
 - Keep descriptions concise but helpful
+- Mention when a result has in_library set: the user already has it in their local media library
+- For non-English titles, name the original language and say whether English subtitles or a dub are likely
 
 If you're unsure what the user wants, ask clarifying questions.
 Be conversational and helpful. You can remember context from earlier in the conversation.`
+
+// chatToolSummaries describes each tool in the chat system prompt's tool list
+var chatToolSummaries = map[string]string{
+	"search_media":                      "Search TMDb for movies/TV shows with filters (genre, year, rating, language, streaming service, actors, studios, TV networks)",
+	"blend_tastes":                      "Find titles that several people with different tastes would all enjoy",
+	"get_media_details":                 "Get detailed info about a specific title",
+	"get_streaming_providers":           "Check where something is available to watch",
+	"get_streaming_providers_batch":     "Check where several titles are available in one call",
+	"get_streaming_providers_by_region": "Compare a title's availability across countries and find where it's free or on a subscription",
+	"get_certifications":                "Get a title's age rating in each country (for \"is this OK for a 10-year-old?\")",
+	"get_season":                        "List a TV season's episodes with air dates and ratings",
+	"get_similar":                       "Find similar movies/shows to a given title",
+	"search_by_title":                   "Find a specific title by name",
+	"get_franchise":                     "List a movie franchise's films in release or chronological (story) order",
+	"get_director_films":                "List every film a director has directed, by release or rating",
+	"list_filters":                      "List the genre, provider, and studio names search_media supports",
+	"get_trakt_watchlist":               "View the user's Trakt watchlist (if connected), optionally filtered by genre, runtime, or unwatched",
+	"get_trakt_history":                 "View the user's watch history (if connected)",
+	"get_next_episode":                  "Find the next unwatched episode of a show the user is watching (if connected)",
+	"generate_recommendations":          "Generate AI recommendations directly for complex/mood-based requests",
+}
+
+// chatToolRules are the chat system prompt's rules for using the tools, in
+// order. A rule is only given when every tool it names is offered.
+var chatToolRules = []struct {
+	tools []string
+	rule  string
+}{
+	{[]string{"search_media"}, "Use search_media for discovery requests with specific criteria"},
+	{[]string{"search_by_title", "get_similar"}, "Use search_by_title first when users mention a specific title, then get_similar for recommendations"},
+	{[]string{"get_streaming_providers"}, "Use get_streaming_providers to show where they can watch something"},
+	{[]string{"get_streaming_providers_batch"}, "Use get_streaming_providers_batch to check where several titles are available at once"},
+	{[]string{"generate_recommendations"}, "Use generate_recommendations for subjective requests that don't map well to filters"},
+	{[]string{"blend_tastes"}, "Use blend_tastes when watching together with different tastes (\"my partner likes rom-coms, I like horror\"), and recommend the titles that best satisfy every group"},
+	{[]string{"list_filters", "search_media"}, "Use list_filters when unsure whether a genre, provider, or studio name is supported"},
+	{[]string{"get_franchise"}, "Use get_franchise for \"what order should I watch these\" questions, and always say whether the list is in release or chronological order"},
+	{[]string{"get_certifications"}, "Use get_certifications for age-suitability questions, and give the rating in the user's region alongside what it means"},
+	{[]string{"get_director_films"}, "Use get_director_films for a director's filmography (\"all of Villeneuve's films\", \"best Kubrick movies\") instead of search_media"},
+	{[]string{"get_media_details"}, "Use get_media_details to check whether a non-English title has an English localization, a sign that subtitles or a dub exist"},
+	{nil, "Results are shown to the user as numbered cards. When a message refers to one (\"#2\") it ends with a note giving that card's TMDb ID and media type; call the tools that take a TMDb ID with it directly instead of searching again"},
+}
+
+// getChatSystemPrompt returns the chat prompt for the offered tools, with the
+// current date so the assistant knows what "recent" or "this year" means
+func getChatSystemPrompt(toolDefs []tools.ToolDefinition) string {
+	offered := make(map[string]bool)
+	for _, def := range toolDefs {
+		offered[def.Name] = true
+	}
+
+	var sb strings.Builder
+	sb.WriteString(chatSystemPrompt)
+	if len(toolDefs) > 0 {
+		sb.WriteString("\n\nYou have access to tools to help users find content to watch:\n")
+		for _, def := range toolDefs {
+			summary := chatToolSummaries[def.Name]
+			if summary == "" {
+				summary = def.Description
+			}
+			fmt.Fprintf(&sb, "- %s: %s\n", def.Name, summary)
+		}
+
+		sb.WriteString("\nWhen helping users:\n")
+		n := 0
+	rules:
+		for _, r := range chatToolRules {
+			for _, name := range r.tools {
+				if !offered[name] {
+					continue rules
+				}
+			}
+			n++
+			fmt.Fprintf(&sb, "%d. %s\n", n, r.rule)
+		}
+	}
+
+	now := time.Now()
+	prompt := strings.TrimSuffix(sb.String(), "\n") + chatPromptFormat + fmt.Sprintf("\n\nToday's date: %s (current year: %d). Use it to judge what's recent, new, or upcoming.", now.Format("January 2, 2006"), now.Year())
+	return withPreferences(prompt)
+}
+
+// sessionTitlePrompt asks the model to name a conversation from the user's messages
+const sessionTitlePrompt = `Give this conversation a concise 3-5 word title describing what the user is looking for, like "Dark Psychological Thrillers" or "90s Feel-Good Comedies". Reply with only the title: no quotes, no punctuation, no tool calls.
+
+The user's messages:
+%s`
+
+// conversationSummaryPrompt asks the model to condense the older part of a
+// conversation so it can stand in for it
+const conversationSummaryPrompt = `Summarize this part of a conversation between a user and a movie/TV recommendation assistant, so it can replace the original in the assistant's memory. Keep what matters for future recommendations: what the user asked for, their tastes and dislikes, titles already recommended (with TMDb IDs where given), and titles they've seen or rejected. Write a compact paragraph, no more than 200 words. Reply with only the summary, no tool calls.
+%s
+The conversation:
+%s`
+
+// SummarizeConversation asks the model for a summary of messages that can
+// replace them in later requests. previous is the summary of anything before
+// messages ("" if none), which is folded into the new one.
+func SummarizeConversation(ctx context.Context, provider ChatProvider, previous string, messages []ChatMessage) (string, error) {
+	var sb strings.Builder
+	for _, msg := range messages {
+		switch {
+		case msg.Role == "tool":
+			continue // the assistant's replies already say what the results were
+		case msg.Content != "":
+			sb.WriteString(msg.Role + ": " + msg.Content + "\n")
+		}
+		for _, tc := range msg.ToolCalls {
+			sb.WriteString("assistant called " + tc.Name + "\n")
+		}
+	}
+
+	earlier := ""
+	if previous != "" {
+		earlier = "\nA summary of what came before it:\n" + previous + "\n"
+	}
+	prompt := []ChatMessage{{Role: "user", Content: fmt.Sprintf(conversationSummaryPrompt, earlier, sb.String())}}
+	resp, err := provider.SendMessage(ctx, prompt, nil)
+	if err != nil {
+		return "", err
+	}
+
+	summary := strings.TrimSpace(resp.Content)
+	if summary == "" {
+		return "", fmt.Errorf("empty summary from model")
+	}
+	return summary, nil
+}
+
+// GenerateSessionTitle asks the model for a short title summarizing a conversation
+func GenerateSessionTitle(ctx context.Context, provider ChatProvider, messages []ChatMessage) (string, error) {
+	var sb strings.Builder
+	for _, msg := range messages {
+		if msg.Role == "user" && msg.Content != "" {
+			sb.WriteString("- " + msg.Content + "\n")
+		}
+	}
+
+	prompt := []ChatMessage{{Role: "user", Content: fmt.Sprintf(sessionTitlePrompt, sb.String())}}
+	resp, err := provider.SendMessage(ctx, prompt, nil)
+	if err != nil {
+		return "", err
+	}
+
+	// Keep the first line and strip any quoting or markdown the model added
+	title, _, _ := strings.Cut(strings.TrimSpace(resp.Content), "\n")
+	title = strings.Trim(title, "\"'*#. ")
+	if title == "" {
+		return "", fmt.Errorf("empty title from model")
+	}
+	return title, nil
+}
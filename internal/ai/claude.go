@@ -2,11 +2,13 @@ package ai
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/anthropics/anthropic-sdk-go/packages/param"
+
+	"wtfsiw/internal/config"
 )
 
 type ClaudeProvider struct {
@@ -20,29 +22,30 @@ func NewClaudeProvider(apiKey string) *ClaudeProvider {
 }
 
 func (p *ClaudeProvider) ExtractSearchParams(ctx context.Context, query string) (*SearchParams, error) {
-	message, err := p.client.Messages.New(ctx, anthropic.MessageNewParams{
-		Model:     anthropic.ModelClaude3_5Haiku20241022,
-		MaxTokens: 1024,
-		System: []anthropic.TextBlockParam{
-			{Text: getSystemPromptExtract()},
-		},
-		Messages: []anthropic.MessageParam{
-			anthropic.NewUserMessage(anthropic.NewTextBlock(query)),
-		},
+	responseText, err := completeWithRetry(ctx, "Claude", query, func(ctx context.Context, prompt string) (string, error) {
+		message, err := p.client.Messages.New(ctx, anthropic.MessageNewParams{
+			Model:     anthropic.ModelClaude3_5Haiku20241022,
+			MaxTokens: 1024,
+			System: []anthropic.TextBlockParam{
+				{Text: withPreferences(getSystemPromptExtract())},
+			},
+			Messages: []anthropic.MessageParam{
+				anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+			},
+		})
+		if err != nil {
+			return "", fmt.Errorf("claude API error: %w", err)
+		}
+		return extractTextFromResponse(message), nil
 	})
 	if err != nil {
-		return nil, fmt.Errorf("claude API error: %w", err)
-	}
-
-	responseText := extractTextFromResponse(message)
-	if responseText == "" {
-		return nil, fmt.Errorf("empty response from Claude")
+		return nil, err
 	}
 
 	// Parse JSON response
 	var params SearchParams
-	if err := json.Unmarshal([]byte(responseText), &params); err != nil {
-		return nil, fmt.Errorf("failed to parse Claude response as JSON: %w\nResponse: %s", err, responseText)
+	if err := parseJSONResponse("Claude", responseText, &params); err != nil {
+		return nil, err
 	}
 
 	// Set defaults if not specified
@@ -54,31 +57,36 @@ func (p *ClaudeProvider) ExtractSearchParams(ctx context.Context, query string)
 }
 
 func (p *ClaudeProvider) GetRecommendations(ctx context.Context, query string, count int) (*RecommendationResponse, error) {
-	userPrompt := fmt.Sprintf("Please recommend %d movies or TV shows based on this request: %s", count, query)
-
-	message, err := p.client.Messages.New(ctx, anthropic.MessageNewParams{
-		Model:     anthropic.ModelClaude3_5Haiku20241022,
-		MaxTokens: 4096,
-		System: []anthropic.TextBlockParam{
-			{Text: systemPromptRecommend},
-		},
-		Messages: []anthropic.MessageParam{
-			anthropic.NewUserMessage(anthropic.NewTextBlock(userPrompt)),
-		},
+	return collectRecommendations(ctx, query, count, p.requestRecommendations)
+}
+
+// requestRecommendations sends one recommendation prompt to Claude
+func (p *ClaudeProvider) requestRecommendations(ctx context.Context, userPrompt string) (*RecommendationResponse, error) {
+	responseText, err := completeWithRetry(ctx, "Claude", userPrompt, func(ctx context.Context, prompt string) (string, error) {
+		message, err := p.client.Messages.New(ctx, anthropic.MessageNewParams{
+			Model:       anthropic.ModelClaude3_5Haiku20241022,
+			MaxTokens:   4096,
+			Temperature: claudeTemperature(),
+			System: []anthropic.TextBlockParam{
+				{Text: withPreferences(systemPromptRecommend)},
+			},
+			Messages: []anthropic.MessageParam{
+				anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+			},
+		})
+		if err != nil {
+			return "", fmt.Errorf("claude API error: %w", err)
+		}
+		return extractTextFromResponse(message), nil
 	})
 	if err != nil {
-		return nil, fmt.Errorf("claude API error: %w", err)
-	}
-
-	responseText := extractTextFromResponse(message)
-	if responseText == "" {
-		return nil, fmt.Errorf("empty response from Claude")
+		return nil, err
 	}
 
 	// Parse JSON response
 	var resp RecommendationResponse
-	if err := json.Unmarshal([]byte(responseText), &resp); err != nil {
-		return nil, fmt.Errorf("failed to parse Claude response as JSON: %w\nResponse: %s", err, responseText)
+	if err := parseJSONResponse("Claude", responseText, &resp); err != nil {
+		return nil, err
 	}
 
 	// Mark all recommendations as from AI
@@ -89,6 +97,11 @@ func (p *ClaudeProvider) GetRecommendations(ctx context.Context, query string, c
 	return &resp, nil
 }
 
+// Embed is unsupported: Anthropic has no embeddings API
+func (p *ClaudeProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return nil, ErrEmbeddingsUnsupported
+}
+
 func extractTextFromResponse(message *anthropic.Message) string {
 	if len(message.Content) == 0 {
 		return ""
@@ -100,3 +113,12 @@ func extractTextFromResponse(message *anthropic.Message) string {
 	}
 	return ""
 }
+
+// claudeTemperature returns ai.temperature as a request param, or an unset
+// param (the API default) when it isn't configured
+func claudeTemperature() param.Opt[float64] {
+	if t := config.Get().AI.Temperature; t != nil {
+		return anthropic.Float(*t)
+	}
+	return param.Opt[float64]{}
+}
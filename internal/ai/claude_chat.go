@@ -40,10 +40,11 @@ func (p *ClaudeChatProvider) SendMessage(ctx context.Context, messages []ChatMes
 
 	// Make API call
 	resp, err := p.client.Messages.New(ctx, anthropic.MessageNewParams{
-		Model:     anthropic.ModelClaude3_5Haiku20241022,
-		MaxTokens: 4096,
+		Model:       anthropic.ModelClaude3_5Haiku20241022,
+		MaxTokens:   4096,
+		Temperature: claudeTemperature(),
 		System: []anthropic.TextBlockParam{
-			{Text: chatSystemPrompt},
+			{Text: getChatSystemPrompt(toolDefs)},
 		},
 		Messages: claudeMessages,
 		Tools:    claudeTools,
@@ -159,6 +160,12 @@ func paramToAnthropicSchema(p tools.ToolParameter) map[string]interface{} {
 		schema["items"] = paramToAnthropicSchema(*p.Items)
 	}
 
+	if p.Type == "object" && len(p.Properties) > 0 {
+		for k, v := range tools.ToAnthropicInputSchema(p.Properties) {
+			schema[k] = v
+		}
+	}
+
 	return schema
 }
 
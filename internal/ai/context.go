@@ -0,0 +1,72 @@
+package ai
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxContextBytes is the size budget for a --context file. Longer files are
+// cut at the last full line that fits, so a huge list can't crowd out the
+// rest of the prompt (or the bill).
+const MaxContextBytes = 8 * 1024
+
+// userContext is the loaded --context (or /context) file, sent with every
+// system prompt. It's kept out of chat sessions, so saved sessions don't
+// carry a copy of it.
+var userContext string
+
+// userContextPrompt is appended to system prompts when a context file is loaded
+const userContextPrompt = `
+
+ABOUT THE USER'S TASTE: The user shared this document about what they like (for example, favorite titles). Tailor recommendations to it: favor titles in the same spirit and skip ones it lists, since they've seen them. The request itself always comes first.
+---
+%s
+---`
+
+// LoadContextFile reads a context file, trimmed to MaxContextBytes.
+// truncated reports whether it had to be cut.
+func LoadContextFile(path string) (text string, truncated bool, err error) {
+	// Chat's /context isn't run through a shell, so expand ~ here
+	if strings.HasPrefix(path, "~/") {
+		home, _ := os.UserHomeDir()
+		path = filepath.Join(home, path[2:])
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read context file: %w", err)
+	}
+	if !utf8.Valid(data) {
+		return "", false, fmt.Errorf("context file %s isn't a text file", path)
+	}
+
+	text = strings.TrimSpace(string(data))
+	if len(text) > MaxContextBytes {
+		text = text[:MaxContextBytes]
+		if i := strings.LastIndexByte(text, '\n'); i > 0 {
+			text = text[:i]
+		} else {
+			// One long line: back off to a whole UTF-8 character
+			for !utf8.ValidString(text) {
+				text = text[:len(text)-1]
+			}
+		}
+		truncated = true
+	}
+	if text == "" {
+		return "", false, fmt.Errorf("context file %s is empty", path)
+	}
+	return text, truncated, nil
+}
+
+// SetUserContext sets the context document sent with system prompts ("" for none)
+func SetUserContext(text string) {
+	userContext = text
+}
+
+// UserContext returns the loaded context document ("" if none)
+func UserContext() string {
+	return userContext
+}
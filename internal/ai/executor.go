@@ -4,9 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
+	"unicode/utf8"
 
 	"wtfsiw/internal/ai/tools"
+	"wtfsiw/internal/config"
+	"wtfsiw/internal/library"
+	"wtfsiw/internal/netutil"
+	"wtfsiw/internal/omdb"
+	"wtfsiw/internal/textutil"
 	"wtfsiw/internal/tmdb"
 	"wtfsiw/internal/trakt"
 )
@@ -32,27 +40,56 @@ func (e *ToolExecutor) Execute(ctx context.Context, call tools.ToolCall) tools.T
 	var content string
 	var err error
 
+	// The model isn't offered disabled tools, but may still name one from the prompt
+	if slices.Contains(config.Get().AI.DisabledTools, call.Name) {
+		return tools.ToolResult{
+			ToolCallID: call.ID,
+			Content:    fmt.Sprintf("The %s tool is turned off in the user's settings (ai.disabled_tools). Answer with the other tools instead.", call.Name),
+			IsError:    true,
+		}
+	}
+
 	switch call.Name {
 	case "search_media":
 		content, err = e.searchMedia(ctx, call)
+	case "blend_tastes":
+		content, err = e.blendTastes(ctx, call)
 	case "get_media_details":
 		content, err = e.getMediaDetails(ctx, call)
 	case "get_streaming_providers":
 		content, err = e.getStreamingProviders(ctx, call)
+	case "get_streaming_providers_batch":
+		content, err = e.getStreamingProvidersBatch(ctx, call)
+	case "get_streaming_providers_by_region":
+		content, err = e.getStreamingProvidersByRegion(ctx, call)
+	case "get_certifications":
+		content, err = e.getCertifications(ctx, call)
+	case "get_season":
+		content, err = e.getSeason(ctx, call)
 	case "get_similar":
 		content, err = e.getSimilar(ctx, call)
+	case "get_franchise":
+		content, err = e.getFranchise(ctx, call)
+	case "get_director_films":
+		content, err = e.getDirectorFilms(ctx, call)
 	case "search_by_title":
 		content, err = e.searchByTitle(ctx, call)
+	case "list_filters":
+		content, err = e.listFilters(ctx, call)
 	case "get_trakt_watchlist":
 		content, err = e.getTraktWatchlist(ctx, call)
 	case "get_trakt_history":
 		content, err = e.getTraktHistory(ctx, call)
+	case "get_next_episode":
+		content, err = e.getNextEpisode(ctx, call)
 	case "generate_recommendations":
 		content, err = e.generateRecommendations(ctx, call)
 	default:
+		// Name the real (enabled) tools so the model can correct itself on the next turn
+		enabled := tools.Names(tools.Without(config.Get().AI.DisabledTools))
 		return tools.ToolResult{
 			ToolCallID: call.ID,
-			Content:    fmt.Sprintf("Unknown tool: %s", call.Name),
+			Content:    fmt.Sprintf("Unknown tool: %s. The available tools are: %s. Call one of these instead.", call.Name, strings.Join(enabled, ", ")),
 			IsError:    true,
 		}
 	}
@@ -60,7 +97,7 @@ func (e *ToolExecutor) Execute(ctx context.Context, call tools.ToolCall) tools.T
 	if err != nil {
 		return tools.ToolResult{
 			ToolCallID: call.ID,
-			Content:    fmt.Sprintf("Error: %s", err.Error()),
+			Content:    fmt.Sprintf("Error: %s", netutil.Friendly(err).Error()),
 			IsError:    true,
 		}
 	}
@@ -79,16 +116,25 @@ func (e *ToolExecutor) searchMedia(ctx context.Context, call tools.ToolCall) (st
 
 	// Build search params from tool arguments
 	params := &SearchParams{
-		Keywords:       call.GetStringArray("keywords"),
-		Genres:         call.GetStringArray("genres"),
-		MediaType:      call.GetString("media_type"),
-		YearFrom:       call.GetInt("year_from"),
-		YearTo:         call.GetInt("year_to"),
-		MinRating:      call.GetFloat("min_rating"),
-		OriginalLang:   call.GetString("language"),
-		WatchProviders: call.GetStringArray("providers"),
-		Actors:         call.GetStringArray("actors"),
-		Studios:        call.GetStringArray("studios"),
+		Keywords:          call.GetStringArray("keywords"),
+		Genres:            call.GetStringArray("genres"),
+		ExcludeGenres:     call.GetStringArray("exclude_genres"),
+		MediaType:         call.GetString("media_type"),
+		Year:              call.GetInt("year"),
+		YearFrom:          call.GetInt("year_from"),
+		YearTo:            call.GetInt("year_to"),
+		MinRating:         call.GetFloat("min_rating"),
+		MinPopularity:     call.GetFloat("min_popularity"),
+		Quality:           call.GetString("quality"),
+		OriginalLang:      call.GetString("language"),
+		WatchProviders:    call.GetStringArray("providers"),
+		ProviderMatch:     call.GetString("provider_match"),
+		MonetizationTypes: call.GetStringArray("monetization_types"),
+		Actors:            call.GetStringArray("actors"),
+		Studios:           call.GetStringArray("studios"),
+		Networks:          call.GetStringArray("networks"),
+		SortBy:            call.GetString("sort_by"),
+		Diverse:           call.GetBool("diverse"),
 	}
 
 	if params.MediaType == "" {
@@ -100,11 +146,111 @@ func (e *ToolExecutor) searchMedia(ctx context.Context, call tools.ToolCall) (st
 		return "", err
 	}
 
-	// Enrich with providers
+	// Enrich with overviews and providers
+	e.tmdbClient.FillMissingOverviews(resp.Results)
 	e.tmdbClient.EnrichWithProviders(resp.Results)
+	e.tmdbClient.RankByProviderPreference(resp.Results)
+	e.tmdbClient.EnrichWithBingeTime(resp.Results)
 
 	// Format results
-	return formatMediaResults(resp.Results), nil
+	return e.formatMediaResults(resp.Results), nil
+}
+
+// maxBlendResults caps the number of titles returned by blend_tastes
+const maxBlendResults = 10
+
+// maxBlendCandidates is how many blended titles are reranked by the AI before
+// keeping the best maxBlendResults
+const maxBlendCandidates = 30
+
+func (e *ToolExecutor) blendTastes(ctx context.Context, call tools.ToolCall) (string, error) {
+	if e.tmdbClient == nil {
+		return "", fmt.Errorf("TMDb is not configured")
+	}
+
+	var groups []tmdb.TasteGroup
+	for i, g := range call.GetObjectArray("groups") {
+		label := g.GetString("label")
+		if label == "" {
+			label = fmt.Sprintf("group %d", i+1)
+		}
+		groups = append(groups, tmdb.TasteGroup{Label: label, Genres: g.GetStringArray("genres")})
+	}
+
+	base := tmdb.SearchParams{
+		MediaType:      call.GetString("media_type"),
+		MinRating:      call.GetFloat("min_rating"),
+		WatchProviders: call.GetStringArray("providers"),
+	}
+	if base.MediaType == "" {
+		base.MediaType = "all"
+	}
+
+	matches, err := e.tmdbClient.Blend(groups, base, maxBlendCandidates)
+	if err != nil {
+		return "", err
+	}
+	matches = e.rerankBlend(ctx, groups, matches)
+	if len(matches) > maxBlendResults {
+		matches = matches[:maxBlendResults]
+	}
+
+	media := make([]tmdb.Media, len(matches))
+	for i, m := range matches {
+		media[i] = m.Media
+	}
+	e.tmdbClient.FillMissingOverviews(media)
+	e.tmdbClient.EnrichWithProviders(media)
+
+	var results []map[string]interface{}
+	for i, m := range media {
+		results = append(results, map[string]interface{}{
+			"id":                m.ID,
+			"title":             m.GetDisplayTitle(),
+			"year":              m.GetDisplayYear(),
+			"media_type":        m.MediaType,
+			"rating":            m.VoteAverage,
+			"overview":          truncateStr(m.Overview, 200),
+			"providers":         formatProviders(m.Providers),
+			"original_language": tmdb.LanguageName(m.OriginalLang),
+			"matches":           matches[i].Matches,
+			"in_library":        library.Get().Has(m.GetDisplayTitle(), m.GetDisplayYear()),
+		})
+	}
+
+	jsonBytes, _ := json.MarshalIndent(results, "", "  ")
+	return string(jsonBytes), nil
+}
+
+// rerankBlend reorders blended titles by how well each fits every group's
+// tastes, so compromises that suit everyone come first. Without an AI
+// provider the blend's own ordering is kept.
+func (e *ToolExecutor) rerankBlend(ctx context.Context, groups []tmdb.TasteGroup, matches []tmdb.BlendMatch) []tmdb.BlendMatch {
+	if e.aiProvider == nil || len(matches) < 2 {
+		return matches
+	}
+
+	moods := make([]string, len(groups))
+	for i, g := range groups {
+		moods[i] = strings.Join(g.Genres, ", ")
+	}
+	docs := make([]RankDoc, len(matches))
+	for i, m := range matches {
+		text := m.Media.GetDisplayTitle()
+		if genres := m.Media.GetGenreNames(); len(genres) > 0 {
+			text += ". Genres: " + strings.Join(genres, ", ")
+		}
+		if m.Media.Overview != "" {
+			text += ". " + m.Media.Overview
+		}
+		docs[i] = RankDoc{Key: fmt.Sprintf("%s-%d", m.Media.MediaType, m.Media.ID), Text: text}
+	}
+
+	ranked := make([]tmdb.BlendMatch, 0, len(matches))
+	for _, i := range RankForAll(ctx, e.aiProvider, moods, docs) {
+		ranked = append(ranked, matches[i])
+	}
+	return ranked
 }
 
 func (e *ToolExecutor) getMediaDetails(ctx context.Context, call tools.ToolCall) (string, error) {
@@ -135,6 +281,28 @@ func (e *ToolExecutor) getMediaDetails(ctx context.Context, call tools.ToolCall)
 		"providers":  formatProviders(providers),
 	}
 
+	// TMDb has no subtitle or dub track data; an English localization is the best signal
+	if translations, err := e.tmdbClient.GetTranslations(mediaType, id); err == nil {
+		english := false
+		for _, lang := range translations {
+			if lang == "en" {
+				english = true
+				break
+			}
+		}
+		result["english_localization"] = english
+		result["language_note"] = "english_localization means TMDb has English metadata, which usually means an English release (subtitles or dub); TMDb does not list actual subtitle or audio tracks"
+	}
+
+	// IMDb and Rotten Tomatoes ratings, when OMDb is configured
+	if omdbClient, err := omdb.NewClient(); err == nil {
+		if imdbID, err := e.tmdbClient.GetIMDbID(mediaType, id); err == nil && imdbID != "" {
+			if ratings, err := omdbClient.GetRatingsByIMDbID(imdbID); err == nil && len(ratings.Summary()) > 0 {
+				result["critic_ratings"] = ratings.Summary()
+			}
+		}
+	}
+
 	jsonBytes, _ := json.MarshalIndent(result, "", "  ")
 	return string(jsonBytes), nil
 }
@@ -168,6 +336,227 @@ func (e *ToolExecutor) getStreamingProviders(ctx context.Context, call tools.Too
 	return string(jsonBytes), nil
 }
 
+func (e *ToolExecutor) getCertifications(ctx context.Context, call tools.ToolCall) (string, error) {
+	if e.tmdbClient == nil {
+		return "", fmt.Errorf("TMDb is not configured")
+	}
+
+	id := call.GetInt("id")
+	mediaType := call.GetString("media_type")
+
+	if id == 0 {
+		return "", fmt.Errorf("id is required")
+	}
+	if mediaType == "" {
+		return "", fmt.Errorf("media_type is required")
+	}
+
+	ratings, err := e.tmdbClient.GetCertifications(mediaType, id)
+	if err != nil {
+		return "", err
+	}
+
+	// Narrow to the requested regions, always keeping the user's own
+	region := strings.ToUpper(config.Get().Preferences.Region)
+	if regions := call.GetStringArray("regions"); len(regions) > 0 {
+		wanted := map[string]bool{region: true}
+		for _, r := range regions {
+			wanted[strings.ToUpper(r)] = true
+		}
+		for country := range ratings {
+			if !wanted[country] {
+				delete(ratings, country)
+			}
+		}
+	}
+
+	result := map[string]interface{}{
+		"ratings":     ratings,
+		"user_region": region,
+	}
+	if len(ratings) == 0 {
+		result["note"] = "TMDb has no age ratings for this title in these regions"
+	}
+
+	// Not indented: the ratings map can cover dozens of countries
+	jsonBytes, _ := json.Marshal(result)
+	return string(jsonBytes), nil
+}
+
+func (e *ToolExecutor) getStreamingProvidersByRegion(ctx context.Context, call tools.ToolCall) (string, error) {
+	if e.tmdbClient == nil {
+		return "", fmt.Errorf("TMDb is not configured")
+	}
+
+	id := call.GetInt("id")
+	mediaType := call.GetString("media_type")
+
+	if id == 0 {
+		return "", fmt.Errorf("id is required")
+	}
+	if mediaType == "" {
+		return "", fmt.Errorf("media_type is required")
+	}
+
+	// Always include the user's own region for comparison
+	region := strings.ToUpper(config.Get().Preferences.Region)
+	regions := call.GetStringArray("regions")
+	if len(regions) > 0 && region != "" && !slices.ContainsFunc(regions, func(r string) bool { return strings.EqualFold(r, region) }) {
+		regions = append([]string{region}, regions...)
+	}
+
+	byRegion, err := e.tmdbClient.GetWatchProvidersByRegion(mediaType, id, regions)
+	if err != nil {
+		return "", err
+	}
+
+	// Group each region's providers by how they're offered, and collect the
+	// regions where it's cheapest to watch
+	var entries []map[string]interface{}
+	var freeIn, subscriptionIn []string
+	for _, r := range byRegion {
+		entry := map[string]interface{}{"region": r.Region}
+		cheapest := r.Cheapest()
+		if cheapest == "" {
+			entry["available"] = false
+			entries = append(entries, entry)
+			continue
+		}
+		entry["cheapest"] = cheapest
+		byType := make(map[string][]string)
+		for _, p := range r.Providers {
+			byType[p.Type] = append(byType[p.Type], p.Name)
+		}
+		for t, names := range byType {
+			entry[t] = names
+		}
+		switch cheapest {
+		case tmdb.MonetizationFree:
+			freeIn = append(freeIn, r.Region)
+		case tmdb.MonetizationFlatrate:
+			subscriptionIn = append(subscriptionIn, r.Region)
+		}
+		entries = append(entries, entry)
+	}
+
+	result := map[string]interface{}{
+		"regions":         entries,
+		"free_in":         freeIn,
+		"subscription_in": subscriptionIn,
+		"user_region":     region,
+	}
+
+	// Not indented: without a regions filter this covers dozens of countries
+	jsonBytes, _ := json.Marshal(result)
+	return string(jsonBytes), nil
+}
+
+// maxBatchProviderTitles caps how many titles one batch provider lookup may request
+const maxBatchProviderTitles = 20
+
+func (e *ToolExecutor) getStreamingProvidersBatch(ctx context.Context, call tools.ToolCall) (string, error) {
+	if e.tmdbClient == nil {
+		return "", fmt.Errorf("TMDb is not configured")
+	}
+
+	titles := call.GetObjectArray("titles")
+	if len(titles) == 0 {
+		return "", fmt.Errorf("titles is required")
+	}
+	if len(titles) > maxBatchProviderTitles {
+		titles = titles[:maxBatchProviderTitles]
+	}
+
+	media := make([]tmdb.Media, 0, len(titles))
+	for _, t := range titles {
+		id := t.GetInt("id")
+		mediaType := t.GetString("media_type")
+		if id == 0 || (mediaType != "movie" && mediaType != "tv") {
+			continue
+		}
+		media = append(media, tmdb.Media{ID: id, MediaType: mediaType})
+	}
+	if len(media) == 0 {
+		return "", fmt.Errorf("no valid titles: each needs an id and media_type of movie or tv")
+	}
+
+	// Looks up all titles in parallel
+	e.tmdbClient.EnrichWithProviders(media)
+
+	var results []map[string]interface{}
+	for _, m := range media {
+		results = append(results, map[string]interface{}{
+			"id":         m.ID,
+			"media_type": m.MediaType,
+			"providers":  formatProviders(m.Providers),
+			"link":       m.WatchLink,
+		})
+	}
+
+	jsonBytes, _ := json.MarshalIndent(results, "", "  ")
+	return string(jsonBytes), nil
+}
+
+func (e *ToolExecutor) getSeason(ctx context.Context, call tools.ToolCall) (string, error) {
+	if e.tmdbClient == nil {
+		return "", fmt.Errorf("TMDb is not configured")
+	}
+
+	id := call.GetInt("id")
+	if id == 0 {
+		return "", fmt.Errorf("id is required")
+	}
+	if _, ok := call.Arguments["season"]; !ok {
+		return "", fmt.Errorf("season is required")
+	}
+	seasonNumber := call.GetInt("season")
+
+	season, err := e.tmdbClient.GetSeason(id, seasonNumber)
+	if err != nil {
+		return "", err
+	}
+
+	episodes := append([]tmdb.Episode(nil), season.Episodes...)
+	order := call.GetString("sort_by")
+	if order == "rating" {
+		sort.SliceStable(episodes, func(i, j int) bool {
+			return episodes[i].VoteAverage > episodes[j].VoteAverage
+		})
+	} else {
+		order = "episode"
+	}
+
+	var formatted []map[string]interface{}
+	for _, ep := range episodes {
+		entry := map[string]interface{}{
+			"episode":  ep.EpisodeNumber,
+			"title":    ep.Name,
+			"air_date": ep.AirDate,
+			"rating":   ep.VoteAverage,
+			"votes":    ep.VoteCount,
+			"overview": truncateStr(ep.Overview, 150),
+		}
+		if ep.Runtime > 0 {
+			entry["runtime"] = ep.Runtime
+		}
+		formatted = append(formatted, entry)
+	}
+
+	result := map[string]interface{}{
+		"season":   season.SeasonNumber,
+		"name":     season.Name,
+		"air_date": season.AirDate,
+		"sort_by":  order,
+		"episodes": formatted,
+	}
+	if len(formatted) == 0 {
+		result["note"] = "TMDb lists no episodes for this season yet"
+	}
+
+	jsonBytes, _ := json.MarshalIndent(result, "", "  ")
+	return string(jsonBytes), nil
+}
+
 func (e *ToolExecutor) getSimilar(ctx context.Context, call tools.ToolCall) (string, error) {
 	if e.tmdbClient == nil {
 		return "", fmt.Errorf("TMDb is not configured")
@@ -183,19 +572,138 @@ func (e *ToolExecutor) getSimilar(ctx context.Context, call tools.ToolCall) (str
 		return "", fmt.Errorf("media_type is required")
 	}
 
-	// Use the existing findSimilar through a search
-	params := &SearchParams{
-		SimilarTo: []string{fmt.Sprintf("%d", id)}, // This won't work directly, need to enhance
-		MediaType: mediaType,
+	resp, err := e.tmdbClient.GetSimilar(mediaType, id)
+	if err != nil {
+		return "", err
 	}
 
-	resp, err := e.tmdbClient.Discover(params)
+	// Limit to first 10 results
+	results := resp.Results
+	if len(results) > 10 {
+		results = results[:10]
+	}
+
+	e.tmdbClient.FillMissingOverviews(results)
+	e.tmdbClient.EnrichWithProviders(results)
+	e.tmdbClient.RankByProviderPreference(results)
+	e.tmdbClient.EnrichWithBingeTime(results)
+	return e.formatMediaResults(results), nil
+}
+
+func (e *ToolExecutor) getFranchise(ctx context.Context, call tools.ToolCall) (string, error) {
+	if e.tmdbClient == nil {
+		return "", fmt.Errorf("TMDb is not configured")
+	}
+
+	title := call.GetString("title")
+	if title == "" {
+		return "", fmt.Errorf("title is required")
+	}
+	requested := call.GetString("watch_order")
+	if requested == "" {
+		requested = tmdb.WatchOrderRelease
+	}
+
+	col, err := e.tmdbClient.FindCollection(title)
 	if err != nil {
 		return "", err
 	}
 
-	e.tmdbClient.EnrichWithProviders(resp.Results)
-	return formatMediaResults(resp.Results), nil
+	parts, used := col.Ordered(requested)
+	if len(parts) == 0 {
+		return "", fmt.Errorf("every title in %s is hidden by the user's blocked titles or genres", col.Name)
+	}
+	e.tmdbClient.EnrichWithProviders(parts)
+
+	var titles []map[string]interface{}
+	for i, m := range parts {
+		titles = append(titles, map[string]interface{}{
+			"position":          i + 1,
+			"id":                m.ID,
+			"title":             m.GetDisplayTitle(),
+			"year":              m.GetDisplayYear(),
+			"media_type":        m.MediaType,
+			"rating":            m.VoteAverage,
+			"vote_count":        m.VoteCount,
+			"overview":          truncateStr(m.Overview, 200),
+			"providers":         formatProviders(m.Providers),
+			"original_language": tmdb.LanguageName(m.OriginalLang),
+			"in_library":        library.Get().Has(m.GetDisplayTitle(), m.GetDisplayYear()),
+		})
+	}
+
+	result := map[string]interface{}{
+		"collection":  col.Name,
+		"watch_order": used,
+		"titles":      titles,
+	}
+	if used != requested {
+		result["note"] = fmt.Sprintf("No chronology is known for %s, so these are in release order. Tell the user this is release order.", col.Name)
+	}
+
+	jsonBytes, _ := json.MarshalIndent(result, "", "  ")
+	return string(jsonBytes), nil
+}
+
+// maxDirectorFilms caps the films returned for prolific directors
+const maxDirectorFilms = 40
+
+func (e *ToolExecutor) getDirectorFilms(ctx context.Context, call tools.ToolCall) (string, error) {
+	if e.tmdbClient == nil {
+		return "", fmt.Errorf("TMDb is not configured")
+	}
+
+	director := call.GetString("director")
+	if director == "" {
+		return "", fmt.Errorf("director is required")
+	}
+	order := call.GetString("sort_by")
+	if order != tmdb.FilmographyByRating {
+		order = tmdb.FilmographyByRelease
+	}
+
+	filmography, err := e.tmdbClient.GetDirectorFilmography(director)
+	if err != nil {
+		return "", err
+	}
+	films := filmography.Sorted(order)
+	if len(films) == 0 {
+		return "", fmt.Errorf("TMDb lists no films directed by %s (or they're all hidden by the user's blocked titles or genres)", filmography.Person)
+	}
+	note := ""
+	if len(films) > maxDirectorFilms {
+		note = fmt.Sprintf("%s has directed %d films; these are the first %d in this order.", filmography.Person, len(films), maxDirectorFilms)
+		films = films[:maxDirectorFilms]
+	}
+	e.tmdbClient.EnrichWithProviders(films)
+
+	var titles []map[string]interface{}
+	for _, m := range films {
+		titles = append(titles, map[string]interface{}{
+			"id":                m.ID,
+			"title":             m.GetDisplayTitle(),
+			"year":              m.GetDisplayYear(),
+			"media_type":        m.MediaType,
+			"rating":            m.VoteAverage,
+			"vote_count":        m.VoteCount,
+			"overview":          truncateStr(m.Overview, 200),
+			"providers":         formatProviders(m.Providers),
+			"original_language": tmdb.LanguageName(m.OriginalLang),
+			"in_library":        library.Get().Has(m.GetDisplayTitle(), m.GetDisplayYear()),
+		})
+	}
+
+	result := map[string]interface{}{
+		"director": filmography.Person,
+		"sort_by":  order,
+		"titles":   titles,
+	}
+	if note != "" {
+		result["note"] = note
+	}
+
+	jsonBytes, _ := json.MarshalIndent(result, "", "  ")
+	return string(jsonBytes), nil
 }
 
 func (e *ToolExecutor) searchByTitle(ctx context.Context, call tools.ToolCall) (string, error) {
@@ -218,8 +726,37 @@ func (e *ToolExecutor) searchByTitle(ctx context.Context, call tools.ToolCall) (
 	if len(results) > 5 {
 		results = results[:5]
 	}
+	e.tmdbClient.FillMissingOverviews(results)
 
-	return formatMediaResults(results), nil
+	return e.formatMediaResults(results), nil
+}
+
+func (e *ToolExecutor) listFilters(ctx context.Context, call tools.ToolCall) (string, error) {
+	category := call.GetString("category")
+	if category == "" {
+		category = "all"
+	}
+
+	result := make(map[string][]string)
+	if category == "genres" || category == "all" {
+		result["genres"] = sortedKeys(tmdb.GenreMap)
+	}
+	if category == "providers" || category == "all" {
+		result["providers"] = sortedKeys(tmdb.WatchProviderMap)
+	}
+	if category == "studios" || category == "all" {
+		result["studios"] = sortedKeys(tmdb.StudioMap)
+	}
+	if category == "networks" || category == "all" {
+		result["networks"] = sortedKeys(tmdb.NetworkMap)
+	}
+	if len(result) == 0 {
+		return "", fmt.Errorf("unknown category: %s", category)
+	}
+
+	// Not indented: these lists are long and only read by the model
+	jsonBytes, _ := json.Marshal(result)
+	return string(jsonBytes), nil
 }
 
 func (e *ToolExecutor) getTraktWatchlist(ctx context.Context, call tools.ToolCall) (string, error) {
@@ -234,16 +771,38 @@ func (e *ToolExecutor) getTraktWatchlist(ctx context.Context, call tools.ToolCal
 		return "", err
 	}
 
+	// Filter here rather than in the model's context, so big lists stay small
+	filter := trakt.WatchlistFilter{
+		Genre:      call.GetString("genre"),
+		MaxRuntime: call.GetInt("max_runtime"),
+	}
+	if call.GetBool("unwatched") {
+		watched, err := e.traktClient.GetWatchedIDs(mediaType)
+		if err != nil {
+			return "", err
+		}
+		filter.Watched = watched
+	}
+	items = trakt.FilterWatchlist(items, filter)
+	if len(items) == 0 {
+		return "[]", nil
+	}
+
 	// Format watchlist items
 	var results []map[string]interface{}
 	for _, item := range items {
 		entry := map[string]interface{}{
-			"type":     item.Type,
-			"title":    item.GetDisplayTitle(),
-			"year":     item.GetDisplayYear(),
-			"rating":   item.GetRating(),
-			"overview": truncateStr(item.GetOverview(), 200),
-			"genres":   item.GetGenres(),
+			"type":       item.Type,
+			"title":      item.GetDisplayTitle(),
+			"year":       item.GetDisplayYear(),
+			"rating":     item.GetRating(),
+			"overview":   truncateStr(item.GetOverview(), 200),
+			"genres":     item.GetGenres(),
+			"tmdb_id":    item.GetTMDBID(),
+			"media_type": item.GetTMDBMediaType(),
+		}
+		if runtime := item.GetRuntime(); runtime > 0 {
+			entry["runtime"] = runtime
 		}
 		results = append(results, entry)
 	}
@@ -257,8 +816,77 @@ func (e *ToolExecutor) getTraktHistory(ctx context.Context, call tools.ToolCall)
 		return "", fmt.Errorf("Trakt is not configured. Run 'wtfsiw trakt auth' to connect your account.")
 	}
 
-	// History endpoint not yet implemented - return placeholder
-	return `{"message": "Trakt history feature not yet implemented"}`, nil
+	mediaType := call.GetString("media_type")
+	limit := call.GetInt("limit")
+	if limit == 0 {
+		limit = 20
+	}
+
+	items, err := e.traktClient.GetHistory(mediaType, limit)
+	if err != nil {
+		return "", err
+	}
+
+	// Format history items
+	var results []map[string]interface{}
+	for _, item := range items {
+		entry := map[string]interface{}{
+			"type":       item.Type,
+			"title":      item.GetDisplayTitle(),
+			"year":       item.GetDisplayYear(),
+			"watched_at": item.WatchedAt,
+			"tmdb_id":    item.GetTMDBID(),
+			"media_type": item.GetTMDBMediaType(),
+		}
+		if item.Episode != nil {
+			entry["episode"] = fmt.Sprintf("S%02dE%02d %s", item.Episode.Season, item.Episode.Number, item.Episode.Title)
+		}
+		results = append(results, entry)
+	}
+
+	jsonBytes, _ := json.MarshalIndent(results, "", "  ")
+	return string(jsonBytes), nil
+}
+
+func (e *ToolExecutor) getNextEpisode(ctx context.Context, call tools.ToolCall) (string, error) {
+	if e.traktClient == nil {
+		return "", fmt.Errorf("Trakt is not configured. Run 'wtfsiw trakt auth' to connect your account.")
+	}
+
+	show, err := e.traktClient.FindShow(call.GetString("title"))
+	if err != nil {
+		return "", err
+	}
+	progress, err := e.traktClient.GetShowProgress(show.IDs.Trakt)
+	if err != nil {
+		return "", err
+	}
+
+	result := map[string]interface{}{
+		"title":      show.Title,
+		"year":       show.Year,
+		"tmdb_id":    show.IDs.TMDB,
+		"media_type": "tv",
+		"aired":      progress.Aired,
+		"completed":  progress.Completed,
+	}
+	if progress.LastEpisode != nil {
+		result["last_watched"] = progress.LastEpisode.Label()
+		result["last_watched_at"] = progress.LastWatchedAt
+	}
+	if ep := progress.NextEpisode; ep != nil {
+		result["next_episode"] = map[string]interface{}{
+			"season":  ep.Season,
+			"episode": ep.Number,
+			"title":   ep.Title,
+			"label":   ep.Label(),
+		}
+	} else {
+		result["caught_up"] = true
+	}
+
+	jsonBytes, _ := json.MarshalIndent(result, "", "  ")
+	return string(jsonBytes), nil
 }
 
 func (e *ToolExecutor) generateRecommendations(ctx context.Context, call tools.ToolCall) (string, error) {
@@ -269,7 +897,7 @@ func (e *ToolExecutor) generateRecommendations(ctx context.Context, call tools.T
 	description := call.GetString("description")
 	count := call.GetInt("count")
 	if count == 0 {
-		count = 5
+		count = config.Get().Preferences.GetAICount()
 	}
 
 	resp, err := e.aiProvider.GetRecommendations(ctx, description, count)
@@ -303,7 +931,7 @@ func (e *ToolExecutor) generateRecommendations(ctx context.Context, call tools.T
 
 // Helper functions
 
-func formatMediaResults(results []tmdb.Media) string {
+func (e *ToolExecutor) formatMediaResults(results []tmdb.Media) string {
 	var formatted []map[string]interface{}
 	for _, m := range results {
 		providers := make([]string, len(m.Providers))
@@ -318,9 +946,22 @@ func formatMediaResults(results []tmdb.Media) string {
 			"media_type": m.MediaType,
 			"rating":     m.VoteAverage,
 			"vote_count": m.VoteCount,
+			"genres":     m.GetGenreNames(),
 			"overview":   truncateStr(m.Overview, 200),
 			"providers":  providers,
 		}
+		if m.OriginalLang != "" {
+			entry["original_language"] = tmdb.LanguageName(m.OriginalLang)
+		}
+		if m.BingeMinutes > 0 {
+			entry["binge_minutes"] = m.BingeMinutes
+		}
+		if m.PosterPath != "" {
+			entry["poster"] = e.tmdbClient.PosterURL(m.PosterPath, "")
+		}
+		if library.Get().Has(m.GetDisplayTitle(), m.GetDisplayYear()) {
+			entry["in_library"] = true
+		}
 		formatted = append(formatted, entry)
 	}
 
@@ -336,12 +977,21 @@ func formatProviders(providers []tmdb.Provider) []string {
 	return names
 }
 
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func truncateStr(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	if utf8.RuneCountInString(s) <= maxLen {
 		return s
 	}
 	// Find last space before maxLen
-	s = s[:maxLen]
+	s = textutil.Head(s, maxLen)
 	if idx := strings.LastIndex(s, " "); idx > 0 {
 		s = s[:idx]
 	}
@@ -0,0 +1,31 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"wtfsiw/internal/textutil"
+)
+
+// ExplainPick asks the AI why rec fits the user's original query, for results
+// (such as TMDb matches) that have no explanation. Like 'trakt pick', it reuses
+// the recommendation request, restricted to the one title.
+func ExplainPick(ctx context.Context, provider Provider, query string, rec Recommendation) (string, error) {
+	title := fmt.Sprintf("%s (%s)", rec.Title, rec.Year)
+	prompt := fmt.Sprintf("Recommend ONLY %s, no other title, and make why_watch explain specifically how it fits this request: %s", title, query)
+	if rec.Overview != "" {
+		prompt += "\n\nOverview of " + title + ": " + textutil.Truncate(rec.Overview, 400)
+	}
+
+	resp, err := provider.GetRecommendations(ctx, prompt, 1)
+	if err != nil {
+		return "", err
+	}
+	for _, r := range resp.Recommendations {
+		if strings.EqualFold(strings.TrimSpace(r.Title), rec.Title) && r.WhyWatch != "" {
+			return r.WhyWatch, nil
+		}
+	}
+	return "", fmt.Errorf("the AI didn't explain %s", title)
+}
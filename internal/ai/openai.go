@@ -2,11 +2,13 @@ package ai
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"math"
 	"regexp"
 
 	"github.com/sashabaranov/go-openai"
+
+	"wtfsiw/internal/config"
 )
 
 type OpenAIProvider struct {
@@ -22,8 +24,8 @@ func NewOpenAIProvider(apiKey string) *OpenAIProvider {
 func cleanNumericFields(jsonStr string) string {
 	// Replace empty strings with 0 for known numeric fields
 	numericFields := []string{
-		"year_from", "year_to", "min_rating", "max_runtime", "vote_count",
-		"min_vote_count", // new field
+		"year", "year_from", "year_to", "min_rating", "max_runtime", "vote_count",
+		"min_vote_count", "min_popularity",
 	}
 	for _, field := range numericFields {
 		// Match "field_name":"" and replace with "field_name":0
@@ -34,40 +36,40 @@ func cleanNumericFields(jsonStr string) string {
 }
 
 func (p *OpenAIProvider) ExtractSearchParams(ctx context.Context, query string) (*SearchParams, error) {
-	resp, err := p.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
-		Model: openai.GPT4oMini,
-		Messages: []openai.ChatCompletionMessage{
-			{
-				Role:    openai.ChatMessageRoleSystem,
-				Content: getSystemPromptExtract(),
+	responseText, err := completeWithRetry(ctx, "OpenAI", query, func(ctx context.Context, prompt string) (string, error) {
+		resp, err := p.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+			Model: openai.GPT4oMini,
+			Messages: []openai.ChatCompletionMessage{
+				{
+					Role:    openai.ChatMessageRoleSystem,
+					Content: withPreferences(getSystemPromptExtract()),
+				},
+				{
+					Role:    openai.ChatMessageRoleUser,
+					Content: prompt,
+				},
 			},
-			{
-				Role:    openai.ChatMessageRoleUser,
-				Content: query,
+			MaxTokens: 1024,
+			ResponseFormat: &openai.ChatCompletionResponseFormat{
+				Type: openai.ChatCompletionResponseFormatTypeJSONObject,
 			},
-		},
-		MaxTokens: 1024,
-		ResponseFormat: &openai.ChatCompletionResponseFormat{
-			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
-		},
+		})
+		if err != nil {
+			return "", fmt.Errorf("openai API error: %w", err)
+		}
+		return firstChoiceContent(resp), nil
 	})
 	if err != nil {
-		return nil, fmt.Errorf("openai API error: %w", err)
-	}
-
-	if len(resp.Choices) == 0 {
-		return nil, fmt.Errorf("empty response from OpenAI")
+		return nil, err
 	}
 
-	responseText := resp.Choices[0].Message.Content
-
 	// Clean up common JSON issues (empty strings for numeric fields)
 	responseText = cleanNumericFields(responseText)
 
 	// Parse JSON response
 	var params SearchParams
-	if err := json.Unmarshal([]byte(responseText), &params); err != nil {
-		return nil, fmt.Errorf("failed to parse OpenAI response as JSON: %w\nResponse: %s", err, responseText)
+	if err := parseJSONResponse("OpenAI", responseText, &params); err != nil {
+		return nil, err
 	}
 
 	// Set defaults if not specified
@@ -79,39 +81,43 @@ func (p *OpenAIProvider) ExtractSearchParams(ctx context.Context, query string)
 }
 
 func (p *OpenAIProvider) GetRecommendations(ctx context.Context, query string, count int) (*RecommendationResponse, error) {
-	userPrompt := fmt.Sprintf("Please recommend %d movies or TV shows based on this request: %s", count, query)
-
-	resp, err := p.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
-		Model: openai.GPT4oMini,
-		Messages: []openai.ChatCompletionMessage{
-			{
-				Role:    openai.ChatMessageRoleSystem,
-				Content: systemPromptRecommend,
+	return collectRecommendations(ctx, query, count, p.requestRecommendations)
+}
+
+// requestRecommendations sends one recommendation prompt to OpenAI
+func (p *OpenAIProvider) requestRecommendations(ctx context.Context, userPrompt string) (*RecommendationResponse, error) {
+	responseText, err := completeWithRetry(ctx, "OpenAI", userPrompt, func(ctx context.Context, prompt string) (string, error) {
+		resp, err := p.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+			Model: openai.GPT4oMini,
+			Messages: []openai.ChatCompletionMessage{
+				{
+					Role:    openai.ChatMessageRoleSystem,
+					Content: withPreferences(systemPromptRecommend),
+				},
+				{
+					Role:    openai.ChatMessageRoleUser,
+					Content: prompt,
+				},
 			},
-			{
-				Role:    openai.ChatMessageRoleUser,
-				Content: userPrompt,
+			MaxTokens:   4096,
+			Temperature: openAITemperature(),
+			ResponseFormat: &openai.ChatCompletionResponseFormat{
+				Type: openai.ChatCompletionResponseFormatTypeJSONObject,
 			},
-		},
-		MaxTokens: 4096,
-		ResponseFormat: &openai.ChatCompletionResponseFormat{
-			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
-		},
+		})
+		if err != nil {
+			return "", fmt.Errorf("openai API error: %w", err)
+		}
+		return firstChoiceContent(resp), nil
 	})
 	if err != nil {
-		return nil, fmt.Errorf("openai API error: %w", err)
+		return nil, err
 	}
 
-	if len(resp.Choices) == 0 {
-		return nil, fmt.Errorf("empty response from OpenAI")
-	}
-
-	responseText := resp.Choices[0].Message.Content
-
 	// Parse JSON response
 	var result RecommendationResponse
-	if err := json.Unmarshal([]byte(responseText), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse OpenAI response as JSON: %w\nResponse: %s", err, responseText)
+	if err := parseJSONResponse("OpenAI", responseText, &result); err != nil {
+		return nil, err
 	}
 
 	// Mark all recommendations as from AI
@@ -121,3 +127,51 @@ func (p *OpenAIProvider) GetRecommendations(ctx context.Context, query string, c
 
 	return &result, nil
 }
+
+// firstChoiceContent returns the text of the first choice, or "" if there are none
+func firstChoiceContent(resp openai.ChatCompletionResponse) string {
+	if len(resp.Choices) == 0 {
+		return ""
+	}
+	return resp.Choices[0].Message.Content
+}
+
+// embeddingModel is the OpenAI model used by Embed
+const embeddingModel = openai.SmallEmbedding3
+
+// Embed returns an embedding for each text using OpenAI's embeddings API
+func (p *OpenAIProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	resp, err := p.client.CreateEmbeddings(ctx, openai.EmbeddingRequest{
+		Input: texts,
+		Model: embeddingModel,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("openai embeddings error: %w", err)
+	}
+	if len(resp.Data) != len(texts) {
+		return nil, fmt.Errorf("openai returned %d embeddings for %d texts", len(resp.Data), len(texts))
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, d := range resp.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("openai returned an embedding for unknown index %d", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	return vectors, nil
+}
+
+// openAITemperature returns ai.temperature for a request, or 0 (omitted, the
+// API default) when it isn't configured. The request field is omitempty, so an
+// explicit 0 is sent as the smallest non-zero value instead.
+func openAITemperature() float32 {
+	t := config.Get().AI.Temperature
+	if t == nil {
+		return 0
+	}
+	if *t == 0 {
+		return math.SmallestNonzeroFloat32
+	}
+	return float32(*t)
+}
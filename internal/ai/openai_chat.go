@@ -10,6 +10,13 @@ import (
 	"wtfsiw/internal/ai/tools"
 )
 
+// Chat responses are capped at openAIChatMaxTokens, like Claude's. A tool call
+// cut off at the cap is retried once with openAIChatRetryMaxTokens.
+const (
+	openAIChatMaxTokens      = 4096
+	openAIChatRetryMaxTokens = 16384
+)
+
 // OpenAIChatProvider implements ChatProvider using OpenAI's API
 type OpenAIChatProvider struct {
 	client *openai.Client
@@ -29,7 +36,7 @@ func (p *OpenAIChatProvider) SendMessage(ctx context.Context, messages []ChatMes
 	// Add system message
 	oaiMessages = append(oaiMessages, openai.ChatCompletionMessage{
 		Role:    openai.ChatMessageRoleSystem,
-		Content: chatSystemPrompt,
+		Content: getChatSystemPrompt(toolDefs),
 	})
 
 	// Convert chat messages
@@ -41,36 +48,31 @@ func (p *OpenAIChatProvider) SendMessage(ctx context.Context, messages []ChatMes
 	// Convert tools
 	oaiTools := tools.ToOpenAITools(toolDefs)
 
-	// Make API call
-	resp, err := p.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
-		Model:    openai.GPT4oMini,
-		Messages: oaiMessages,
-		Tools:    oaiTools,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("OpenAI API error: %w", err)
+	request := openai.ChatCompletionRequest{
+		Model:       openai.GPT4oMini,
+		Messages:    oaiMessages,
+		Tools:       oaiTools,
+		Temperature: openAITemperature(),
+		MaxTokens:   openAIChatMaxTokens,
 	}
-
-	if len(resp.Choices) == 0 {
-		return nil, fmt.Errorf("empty response from OpenAI")
+	choice, err := p.complete(ctx, request)
+	if err != nil {
+		return nil, err
 	}
 
-	choice := resp.Choices[0]
-
-	// Check if there are tool calls
-	if len(choice.Message.ToolCalls) > 0 {
-		toolCalls := make([]tools.ToolCall, len(choice.Message.ToolCalls))
-		for i, tc := range choice.Message.ToolCalls {
-			var args map[string]interface{}
-			if err := json.Unmarshal([]byte(tc.Function.Arguments), &args); err != nil {
-				args = make(map[string]interface{})
-			}
-			toolCalls[i] = tools.ToolCall{
-				ID:        tc.ID,
-				Name:      tc.Function.Name,
-				Arguments: args,
-			}
+	// Tool-call arguments cut off by the token limit aren't valid JSON, and
+	// running the tool without them returns junk. Retry once with more room.
+	toolCalls, truncated := parseOpenAIToolCalls(choice)
+	if truncated {
+		request.MaxTokens = openAIChatRetryMaxTokens
+		if choice, err = p.complete(ctx, request); err != nil {
+			return nil, err
 		}
+		if toolCalls, truncated = parseOpenAIToolCalls(choice); truncated {
+			return nil, fmt.Errorf("OpenAI's tool call was cut off at %d tokens; try asking for fewer titles at once", openAIChatRetryMaxTokens)
+		}
+	}
+	if len(toolCalls) > 0 {
 		return &ChatResponse{
 			Content:    choice.Message.Content,
 			ToolCalls:  toolCalls,
@@ -79,12 +81,52 @@ func (p *OpenAIChatProvider) SendMessage(ctx context.Context, messages []ChatMes
 	}
 
 	// Regular text response
+	stopReason := "end_turn"
+	if choice.FinishReason == openai.FinishReasonLength {
+		stopReason = "max_tokens"
+	}
 	return &ChatResponse{
 		Content:    choice.Message.Content,
-		StopReason: "end_turn",
+		StopReason: stopReason,
 	}, nil
 }
 
+// complete makes one chat completion request and returns its first choice
+func (p *OpenAIChatProvider) complete(ctx context.Context, request openai.ChatCompletionRequest) (openai.ChatCompletionChoice, error) {
+	resp, err := p.client.CreateChatCompletion(ctx, request)
+	if err != nil {
+		return openai.ChatCompletionChoice{}, fmt.Errorf("OpenAI API error: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return openai.ChatCompletionChoice{}, fmt.Errorf("empty response from OpenAI")
+	}
+	return resp.Choices[0], nil
+}
+
+// parseOpenAIToolCalls converts a choice's tool calls, reporting whether any
+// were truncated: cut off by the token limit, or with arguments that don't parse
+func parseOpenAIToolCalls(choice openai.ChatCompletionChoice) ([]tools.ToolCall, bool) {
+	if len(choice.Message.ToolCalls) == 0 {
+		return nil, false
+	}
+	truncated := choice.FinishReason == openai.FinishReasonLength
+	toolCalls := make([]tools.ToolCall, len(choice.Message.ToolCalls))
+	for i, tc := range choice.Message.ToolCalls {
+		args := make(map[string]interface{})
+		if tc.Function.Arguments != "" {
+			if err := json.Unmarshal([]byte(tc.Function.Arguments), &args); err != nil {
+				truncated = true
+			}
+		}
+		toolCalls[i] = tools.ToolCall{
+			ID:        tc.ID,
+			Name:      tc.Function.Name,
+			Arguments: args,
+		}
+	}
+	return toolCalls, truncated
+}
+
 func convertToOpenAIMessage(msg ChatMessage) openai.ChatCompletionMessage {
 	switch msg.Role {
 	case "user":
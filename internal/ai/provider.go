@@ -2,10 +2,14 @@ package ai
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"wtfsiw/internal/config"
+	"wtfsiw/internal/textutil"
 	"wtfsiw/internal/tmdb"
 )
 
@@ -22,8 +26,46 @@ type Recommendation struct {
 	Overview    string   `json:"overview"`
 	WhyWatch    string   `json:"why_watch"`  // AI explanation of why this matches the query
 	Providers   []string `json:"providers"`  // Streaming services (when known)
+	Language    string   `json:"language"`   // Original language name, e.g. "Japanese"
 	VoteCount   int      `json:"vote_count"` // Number of votes (0 if from AI)
 	FromAI      bool     `json:"-"`          // True if recommendation came directly from AI
+	InLibrary   bool     `json:"-"`          // True if found in the local media library
+	OMDbRatings []string `json:"-"`          // External ratings, e.g. "IMDb 8.1", "RT 94%" (when OMDb is configured)
+	WatchLink   string   `json:"-"`          // TMDb watch page (when providers were looked up)
+	BingeTime   int      `json:"-"`          // TV only: minutes to watch every episode (0 if unknown)
+	TMDBID      int      `json:"-"`          // TMDb ID (0 if from AI)
+}
+
+// ProviderURL returns the link for watching on one of the title's providers:
+// the provider's own search for the title when there's a URL template for
+// it, otherwise the TMDb watch page ("" if that isn't known either)
+func (r Recommendation) ProviderURL(provider string) string {
+	if link := tmdb.ProviderLink(provider, r.Title); link != "" {
+		return link
+	}
+	return r.WatchLink
+}
+
+// RecommendationFromMedia converts a TMDb result into a Recommendation
+func RecommendationFromMedia(media tmdb.Media) Recommendation {
+	providers := make([]string, len(media.Providers))
+	for i, p := range media.Providers {
+		providers[i] = p.Name
+	}
+	return Recommendation{
+		Title:     media.GetDisplayTitle(),
+		Year:      media.GetDisplayYear(),
+		MediaType: media.MediaType,
+		Rating:    media.VoteAverage,
+		Genres:    media.GetGenreNames(),
+		Overview:  media.Overview,
+		Providers: providers,
+		Language:  tmdb.LanguageName(media.OriginalLang),
+		VoteCount: media.VoteCount,
+		WatchLink: media.WatchLink,
+		BingeTime: media.BingeMinutes,
+		TMDBID:    media.ID,
+	}
 }
 
 // RecommendationResponse is the structured output from the AI
@@ -36,8 +78,13 @@ type RecommendationResponse struct {
 type Provider interface {
 	ExtractSearchParams(ctx context.Context, query string) (*SearchParams, error)
 	GetRecommendations(ctx context.Context, query string, count int) (*RecommendationResponse, error)
+	// Embed returns one embedding vector per text, or ErrEmbeddingsUnsupported
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
 }
 
+// ErrEmbeddingsUnsupported is returned by providers without an embeddings API
+var ErrEmbeddingsUnsupported = errors.New("embeddings are not supported by this AI provider")
+
 // NewProvider creates a new AI provider based on config
 func NewProvider() (Provider, error) {
 	cfg := config.Get()
@@ -47,17 +94,247 @@ func NewProvider() (Provider, error) {
 		if cfg.AI.ClaudeAPIKey == "" {
 			return nil, fmt.Errorf("Claude API key not configured. Set ANTHROPIC_API_KEY or run: wtfsiw config set ai.claude_api_key YOUR_KEY")
 		}
+		if err := validateTemperature(cfg.AI.Provider); err != nil {
+			return nil, err
+		}
 		return NewClaudeProvider(cfg.AI.ClaudeAPIKey), nil
 	case "openai":
 		if cfg.AI.OpenAIAPIKey == "" {
 			return nil, fmt.Errorf("OpenAI API key not configured. Set OPENAI_API_KEY or run: wtfsiw config set ai.openai_api_key YOUR_KEY")
 		}
+		if err := validateTemperature(cfg.AI.Provider); err != nil {
+			return nil, err
+		}
 		return NewOpenAIProvider(cfg.AI.OpenAIAPIKey), nil
 	default:
 		return nil, fmt.Errorf("unknown AI provider: %s", cfg.AI.Provider)
 	}
 }
 
+// APIKeyConfigured reports whether the selected AI provider has an API key,
+// telling a missing key apart from other setup errors from NewProvider
+func APIKeyConfigured() bool {
+	cfg := config.Get()
+	switch cfg.AI.Provider {
+	case "claude":
+		return cfg.AI.ClaudeAPIKey != ""
+	case "openai":
+		return cfg.AI.OpenAIAPIKey != ""
+	default:
+		return false
+	}
+}
+
+// MaxTemperature returns the highest sampling temperature the provider accepts
+func MaxTemperature(provider string) float64 {
+	if provider == "openai" {
+		return 2.0
+	}
+	return 1.0
+}
+
+// AdventurousTemperature returns the temperature used by --adventurous: high
+// enough to surface less obvious picks while keeping replies coherent
+func AdventurousTemperature(provider string) float64 {
+	if provider == "openai" {
+		return 1.3
+	}
+	return 1.0
+}
+
+// validateTemperature checks ai.temperature against the provider's range
+func validateTemperature(provider string) error {
+	t := config.Get().AI.Temperature
+	if t == nil {
+		return nil
+	}
+	if max := MaxTemperature(provider); *t < 0 || *t > max {
+		return fmt.Errorf("ai.temperature must be between 0 and %g for %s, got %g", max, provider, *t)
+	}
+	return nil
+}
+
+// collectRecommendations asks for count recommendations and makes the count
+// authoritative: extras are trimmed, and if the model under-delivers, one
+// follow-up request asks for the rest (excluding titles already suggested).
+// A failed follow-up keeps what the first request returned.
+func collectRecommendations(ctx context.Context, query string, count int, request func(context.Context, string) (*RecommendationResponse, error)) (*RecommendationResponse, error) {
+	prompt := fmt.Sprintf("Please recommend %d movies or TV shows based on this request: %s", count, query)
+	resp, err := request(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+	resp.Recommendations = uniqueRecommendations(removeBlocked(resp.Recommendations))
+
+	if missing := count - len(resp.Recommendations); missing > 0 && len(resp.Recommendations) > 0 {
+		seen := make([]string, len(resp.Recommendations))
+		for i, rec := range resp.Recommendations {
+			seen[i] = fmt.Sprintf("%s (%s)", rec.Title, rec.Year)
+		}
+		prompt := fmt.Sprintf("Please recommend %d more movies or TV shows based on this request: %s\n\nDo not include any of these: %s",
+			missing, query, strings.Join(seen, "; "))
+		if more, err := request(ctx, prompt); err == nil {
+			resp.Recommendations = uniqueRecommendations(append(resp.Recommendations, removeBlocked(more.Recommendations)...))
+		}
+	}
+
+	if count > 0 && len(resp.Recommendations) > count {
+		resp.Recommendations = resp.Recommendations[:count]
+	}
+	return resp, nil
+}
+
+// emptyReplyNudge is added to the prompt when retrying after an empty reply
+const emptyReplyNudge = "\n\n(Your previous reply was empty. Reply with the JSON now.)"
+
+// completeWithRetry gets a reply to prompt from complete, retrying once with
+// a nudged prompt if it comes back empty or blank. Empty completions are
+// usually transient, and a retry beats making the user re-run the query.
+func completeWithRetry(ctx context.Context, provider, prompt string, complete func(context.Context, string) (string, error)) (string, error) {
+	text, err := complete(ctx, prompt)
+	if err == nil && strings.TrimSpace(text) == "" {
+		text, err = complete(ctx, prompt+emptyReplyNudge)
+	}
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(text) == "" {
+		return "", fmt.Errorf("empty response from %s", provider)
+	}
+	return text, nil
+}
+
+// uniqueRecommendations drops repeated titles (same title and media type), keeping the first
+func uniqueRecommendations(recs []Recommendation) []Recommendation {
+	seen := make(map[string]bool)
+	unique := make([]Recommendation, 0, len(recs))
+	for _, rec := range recs {
+		key := strings.ToLower(strings.TrimSpace(rec.Title)) + "|" + rec.MediaType
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, rec)
+	}
+	return unique
+}
+
+// removeBlocked drops recommendations in a genre or matching a title the user
+// blocked (preferences.exclude_genres and preferences.blocked_titles)
+func removeBlocked(recs []Recommendation) []Recommendation {
+	prefs := config.Get().Preferences
+	if len(prefs.ExcludeGenres) == 0 && len(prefs.BlockedTitles) == 0 {
+		return recs
+	}
+	kept := make([]Recommendation, 0, len(recs))
+outer:
+	for _, rec := range recs {
+		for _, g := range rec.Genres {
+			for _, blocked := range prefs.ExcludeGenres {
+				if sameGenre(g, blocked) {
+					continue outer
+				}
+			}
+		}
+		for _, blocked := range prefs.BlockedTitles {
+			if textutil.ContainsPhrase(rec.Title, blocked) {
+				continue outer
+			}
+		}
+		kept = append(kept, rec)
+	}
+	return kept
+}
+
+// sameGenre compares genre names, treating aliases ("sci-fi", "science fiction") as equal
+func sameGenre(a, b string) bool {
+	if strings.EqualFold(a, b) {
+		return true
+	}
+	id, ok := tmdb.GenreMap[tmdb.NormalizeName(a)]
+	return ok && id == tmdb.GenreMap[tmdb.NormalizeName(b)]
+}
+
+// ClarificationError is returned when the model replies with prose (usually a
+// clarifying question) instead of the requested JSON
+type ClarificationError struct {
+	Message string
+}
+
+func (e *ClarificationError) Error() string {
+	return "AI needs more detail: " + e.Message
+}
+
+// parseJSONResponse unmarshals a model's JSON reply into v. A reply with no
+// JSON object at all is treated as a clarifying question rather than a parse failure.
+func parseJSONResponse(provider, responseText string, v interface{}) error {
+	if !strings.Contains(responseText, "{") {
+		return &ClarificationError{Message: strings.TrimSpace(responseText)}
+	}
+	if err := json.Unmarshal([]byte(responseText), v); err != nil {
+		return fmt.Errorf("failed to parse %s response as JSON: %w\nResponse: %s", provider, err, responseText)
+	}
+	return nil
+}
+
+// kidsModePrompt is appended to system prompts when kids mode is enabled
+const kidsModePrompt = `
+
+KIDS MODE IS ON: Only suggest family-appropriate titles suitable for children (movies rated G or PG, TV rated TV-Y to TV-PG). Never suggest horror, thrillers, or titles with strong violence, sexual content, or language, even if asked.`
+
+// blockedPrompt is appended to system prompts when the user has blocked genres or titles
+const blockedPrompt = `
+
+BLOCKED: The user never wants to see these, even if asked for something similar. Blocked genres: %s. Blocked titles and franchises (including sequels and spin-offs): %s. Never suggest them.`
+
+// neverRecommendPrompt is appended to system prompts when the user has a taste
+// profile (preferences.never_recommend)
+const neverRecommendPrompt = `
+
+TASTE PROFILE: The user has told you, once and for all, never to recommend anything matching these: %s. Apply them to every search and suggestion without being reminded (for example, exclude matching genres or keywords from searches), and skip titles that clearly fit one even if they otherwise match. Only break a rule if the user explicitly asks for that kind of title in this conversation.`
+
+// Appended to system prompts for the foreign_audio preference
+const (
+	subbedPrompt = `
+
+FOREIGN-LANGUAGE TITLES: The user prefers watching in the original language with English subtitles. Don't avoid non-English titles, and mention when English subtitles may be hard to find.`
+
+	originalPrompt = `
+
+FOREIGN-LANGUAGE TITLES: The user is a cinephile who watches in the original language with subtitles and never wants dubs. Lean toward strong international titles as well as English ones when they fit the request, and name the original language when recommending them (e.g. "original Korean audio"). Don't mention dubs, and don't steer away from titles that are subtitle-only.`
+
+	dubbedPrompt = `
+
+FOREIGN-LANGUAGE TITLES: The user prefers English dubs. For non-English titles, favor ones with a well-known English dub (e.g. popular anime, major international releases) and say whether a dub is likely available; flag titles that are probably subtitle-only.`
+)
+
+// withPreferences appends preference-driven instructions (and any --context
+// document) to a system prompt
+func withPreferences(prompt string) string {
+	prefs := config.Get().Preferences
+	if prefs.KidsMode {
+		prompt += kidsModePrompt
+	}
+	switch prefs.ForeignAudio {
+	case "subbed":
+		prompt += subbedPrompt
+	case "original":
+		prompt += originalPrompt
+	case "dubbed":
+		prompt += dubbedPrompt
+	}
+	if len(prefs.ExcludeGenres) > 0 || len(prefs.BlockedTitles) > 0 {
+		prompt += fmt.Sprintf(blockedPrompt, strings.Join(prefs.ExcludeGenres, ", "), strings.Join(prefs.BlockedTitles, ", "))
+	}
+	if len(prefs.NeverRecommend) > 0 {
+		prompt += fmt.Sprintf(neverRecommendPrompt, strings.Join(prefs.NeverRecommend, "; "))
+	}
+	if userContext != "" {
+		prompt += fmt.Sprintf(userContextPrompt, userContext)
+	}
+	return prompt
+}
+
 // getSystemPromptExtract returns the extraction prompt with current date
 func getSystemPromptExtract() string {
 	now := time.Now()
@@ -73,10 +350,12 @@ Parameters to extract:
 CORE SEARCH:
 - keywords: search terms (array of strings, default: [])
 - genres: genres like action, comedy, drama, horror, thriller, sci-fi, romance, documentary, animation, fantasy, mystery, crime, war, western, family, history, music (array, default: [])
+- exclude_genres: genres the user does NOT want, same names as genres (array, default: []). "no horror" = ["horror"]
 - similar_to: reference titles mentioned (array, default: [])
 - media_type: "movie", "tv", or "all" (default: "all")
 
 DATE/YEAR:
+- year: one specific year (integer, default: 0). "2019 horror movies" = 2019. Use it instead of year_from/year_to for a single year
 - year_from: start year (integer, default: 0)
 - year_to: end year (integer, default: 0)
   Examples: "recent" = %d-%d, "last 5 years" = %d-%d, "90s" = 1990-1999, "2010s" = 2010-2019
@@ -84,6 +363,8 @@ DATE/YEAR:
 RATINGS:
 - min_rating: minimum rating 0-10 (number, default: 0). "highly rated" = 7.5+, "critically acclaimed" = 8+
 - min_vote_count: minimum votes for quality (integer, default: 0). "well-known" = 1000+, "popular" = 5000+
+- min_popularity: TMDb popularity floor (number, default: 0). "nothing obscure", "don't show me ultra-obscure stuff" = 5, "mainstream" = 20. Leave 0 for "hidden gems" or "underrated"
+- quality: a preset instead of the numbers above: "mainstream" (widely seen), "critic" (rated 8+ with 500+ votes), "hidden-gem" (rated 7.5+ with 100-1000 votes) (string, default: ""). "hidden gem", "underrated" = "hidden-gem"; "critics' picks" = "critic"
 
 RUNTIME:
 - max_runtime: max minutes (integer, default: 0). "short" = 90, "quick watch" = 100
@@ -91,14 +372,16 @@ RUNTIME:
 LANGUAGE:
 - original_language: ISO 639-1 code (string, default: ""). Examples: "en", "ko" (Korean), "ja" (Japanese), "fr", "es", "de", "it", "zh" (Chinese), "hi" (Hindi)
 
-PEOPLE/STUDIOS:
+PEOPLE/STUDIOS/NETWORKS:
 - actors: actor names mentioned (array, default: [])
 - directors: director names mentioned (array, default: [])
 - studios: production companies (array, default: []). Examples: "Pixar", "A24", "Marvel", "DC", "Disney", "Warner Bros", "Universal", "Paramount", "Sony", "Lionsgate", "Blumhouse", "Studio Ghibli"
+- networks: TV networks or channels that made or aired a show (array, default: []). Examples: "HBO", "AMC", "FX", "Showtime", "BBC One", "Netflix". "HBO shows" or "AMC dramas" = networks, NOT watch_providers or studios; networks imply media_type "tv"
 
 STREAMING:
 - watch_providers: streaming services (array, default: []). Examples: "Netflix", "Amazon Prime Video", "Disney Plus", "HBO Max", "Hulu", "Apple TV Plus", "Paramount Plus", "Peacock"
-- monetization_type: "flatrate" (subscription), "free", "rent", "buy" (string, default: "")
+- provider_match: "all" only when a title must be on every named service at once ("on both Netflix and Prime"), otherwise "" (on any of them, the usual meaning of "on Netflix or Hulu" and "on my services") (string, default: "")
+- monetization_types: any of "flatrate" (subscription), "free", "ads", "rent", "buy" (array, default: []). "free or on my subscriptions, not rentals" = ["flatrate","free"]
 
 CONTENT RATING:
 - certification: "G", "PG", "PG-13", "R", "NC-17" for movies; "TV-Y", "TV-G", "TV-PG", "TV-14", "TV-MA" for TV (string, default: "")
@@ -107,7 +390,7 @@ TV-SPECIFIC:
 - tv_status: "returning" (still airing), "ended", "canceled" (string, default: "")
 
 SORTING:
-- sort_by: "popularity", "rating", "release_date", "revenue" (string, default: "")
+- sort_by: "popularity", "rating", "release_date" (newest first), "revenue", "votes" (most voted), "oldest" (oldest first), "lowest_rated", "least popular", "title" (A-Z), "random" (string, default: ""). Only set when the user asks for an order, e.g. "oldest matching films first" = "oldest"
 
 MOOD (for AI interpretation, not TMDb filter):
 - mood: overall tone like "dark", "fun", "thought-provoking", "feel-good", "intense", "relaxing" (string, default: "")
@@ -115,7 +398,7 @@ MOOD (for AI interpretation, not TMDb filter):
 IMPORTANT: For ALL numeric fields, use 0 as default, NOT empty strings.
 
 Respond with ONLY valid JSON, no markdown. Example:
-{"keywords":["heist"],"genres":["thriller","crime"],"similar_to":["Ocean's Eleven"],"media_type":"movie","year_from":0,"year_to":0,"min_rating":7.5,"min_vote_count":1000,"max_runtime":0,"original_language":"","actors":[],"directors":["Steven Soderbergh"],"studios":[],"watch_providers":["Netflix"],"monetization_type":"flatrate","certification":"","tv_status":"","sort_by":"rating","mood":"fun"}`,
+{"keywords":["heist"],"genres":["thriller","crime"],"exclude_genres":[],"similar_to":["Ocean's Eleven"],"media_type":"movie","year":0,"year_from":0,"year_to":0,"min_rating":7.5,"min_vote_count":1000,"min_popularity":0,"quality":"","max_runtime":0,"original_language":"","actors":[],"directors":["Steven Soderbergh"],"studios":[],"networks":[],"watch_providers":["Netflix"],"monetization_types":["flatrate"],"certification":"","tv_status":"","sort_by":"rating","mood":"fun"}`,
 		currentDate, currentYear,
 		currentYear-2, currentYear, // "recent"
 		currentYear-5, currentYear) // "last 5 years"
@@ -132,6 +415,7 @@ For each recommendation include:
 - overview: A brief 1-2 sentence description (no spoilers)
 - why_watch: A personalized explanation of why this matches what the user is looking for
 - providers: Common streaming services where it's typically available (Netflix, Prime Video, HBO Max, Disney+, Hulu, Apple TV+, etc.) - leave empty if unsure
+- language: The original language (e.g., "English", "Japanese"). For non-English titles, mention in why_watch whether English subtitles or an English dub are commonly available
 
 Respond with ONLY a valid JSON object in this exact format:
 {
@@ -145,7 +429,8 @@ Respond with ONLY a valid JSON object in this exact format:
       "genres": ["drama", "crime", "thriller"],
       "overview": "A high school chemistry teacher turned methamphetamine manufacturer partners with a former student.",
       "why_watch": "Matches your request for dark, psychological content with morally complex characters.",
-      "providers": ["Netflix"]
+      "providers": ["Netflix"],
+      "language": "English"
     }
   ]
 }`
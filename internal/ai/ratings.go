@@ -0,0 +1,53 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"wtfsiw/internal/omdb"
+)
+
+// maxConcurrentRatings bounds parallel OMDb lookups
+const maxConcurrentRatings = 5
+
+// AddOMDbRatings looks up each recommendation's IMDb, Rotten Tomatoes, and
+// Metacritic ratings. Titles OMDb can't find are left without them, and a nil
+// client (OMDb not configured) does nothing.
+func AddOMDbRatings(client *omdb.Client, recs []Recommendation) {
+	if client == nil {
+		return
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentRatings)
+
+	for i := range recs {
+		wg.Add(1)
+		go func(rec *Recommendation) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			ratings, err := client.GetRatings(rec.Title, rec.Year, rec.MediaType)
+			if err == nil {
+				rec.OMDbRatings = ratings.Summary()
+			}
+		}(&recs[i])
+	}
+
+	wg.Wait()
+}
+
+// RatingSummary formats the rating with any OMDb ratings beside it, as
+// "TMDb 7.8 · IMDb 8.1 · RT 94%". Without OMDb ratings it's just "7.8/10".
+func (r Recommendation) RatingSummary() string {
+	rating := fmt.Sprintf("%.1f/10", r.Rating)
+	if len(r.OMDbRatings) == 0 {
+		return rating
+	}
+	if !r.FromAI {
+		rating = fmt.Sprintf("TMDb %.1f", r.Rating) // AI estimates keep the plain form
+	}
+	return strings.Join(append([]string{rating}, r.OMDbRatings...), " · ")
+}
@@ -0,0 +1,169 @@
+package ai
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"wtfsiw/internal/cache"
+	"wtfsiw/internal/config"
+)
+
+// embeddingCacheTTL is how long an item's embedding is reused. The text behind
+// a title rarely changes, so this is long; stale entries just get recomputed.
+const embeddingCacheTTL = 30 * 24 * time.Hour
+
+// RankDoc is a candidate for RankByMood
+type RankDoc struct {
+	Key  string // stable ID used to cache the embedding, e.g. "movie-603" ("" to skip caching)
+	Text string // what to compare against the mood: title, genres, overview
+}
+
+// RankByMood returns the indexes of docs ordered by similarity to mood, most
+// similar first. It compares embeddings when the provider supports them
+// (caching each doc's embedding by Key), and falls back to keyword overlap
+// when it doesn't or the embeddings request fails.
+func RankByMood(ctx context.Context, provider Provider, mood string, docs []RankDoc) []int {
+	scores, err := embeddingScores(ctx, provider, mood, docs)
+	if err != nil {
+		scores = keywordScores(mood, docs)
+	}
+
+	order := make([]int, len(docs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]] > scores[order[b]]
+	})
+	return order
+}
+
+// RankForAll returns the indexes of docs ordered by how well they fit every
+// one of moods, best first. Each doc is scored by its weakest match, so a
+// title that suits one mood but not another sinks below one that suits both.
+// Scoring is the same as RankByMood's; ties keep the docs' original order.
+func RankForAll(ctx context.Context, provider Provider, moods []string, docs []RankDoc) []int {
+	worst := make([]float64, len(docs))
+	for m, mood := range moods {
+		scores, err := embeddingScores(ctx, provider, mood, docs)
+		if err != nil {
+			scores = keywordScores(mood, docs)
+		}
+		for i, s := range scores {
+			if m == 0 || s < worst[i] {
+				worst[i] = s
+			}
+		}
+	}
+
+	order := make([]int, len(docs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return worst[order[a]] > worst[order[b]]
+	})
+	return order
+}
+
+// embeddingScores returns the cosine similarity of each doc to mood
+func embeddingScores(ctx context.Context, provider Provider, mood string, docs []RankDoc) ([]float64, error) {
+	store := cache.New(filepath.Join(config.GetCacheDir(), "embeddings"), embeddingCacheTTL)
+	prefix := config.Get().AI.Provider + "/"
+
+	vectors := make([][]float32, len(docs))
+	texts := []string{mood}
+	var missing []int
+	for i, doc := range docs {
+		if doc.Key == "" || !store.Get(prefix+doc.Key, &vectors[i]) {
+			missing = append(missing, i)
+			texts = append(texts, doc.Text)
+		}
+	}
+
+	// One request for the mood plus every uncached doc
+	embedded, err := provider.Embed(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	moodVector := embedded[0]
+	for j, i := range missing {
+		vectors[i] = embedded[j+1]
+		if docs[i].Key != "" {
+			store.Set(prefix+docs[i].Key, vectors[i])
+		}
+	}
+
+	scores := make([]float64, len(docs))
+	for i, v := range vectors {
+		scores[i] = cosineSimilarity(moodVector, v)
+	}
+	return scores, nil
+}
+
+// cosineSimilarity returns the cosine of the angle between a and b (0 if either is empty)
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// keywordScores scores each doc by the share of the mood's words it contains
+func keywordScores(mood string, docs []RankDoc) []float64 {
+	words := keywords(mood)
+	scores := make([]float64, len(docs))
+	if len(words) == 0 {
+		return scores
+	}
+	for i, doc := range docs {
+		docWords := make(map[string]bool)
+		for _, w := range keywords(doc.Text) {
+			docWords[w] = true
+		}
+		matched := 0
+		for _, w := range words {
+			if docWords[w] {
+				matched++
+			}
+		}
+		scores[i] = float64(matched) / float64(len(words))
+	}
+	return scores
+}
+
+// keywords lowercases text and splits it into words, dropping short and common ones
+func keywords(text string) []string {
+	var words []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(w) < 3 || stopWords[w] {
+			continue
+		}
+		words = append(words, w)
+	}
+	return words
+}
+
+// stopWords are ignored when matching moods by keyword
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "something": true,
+	"that": true, "this": true, "want": true, "watch": true, "movie": true,
+	"show": true, "like": true, "but": true, "not": true, "from": true,
+	"about": true, "some": true, "feel": true, "mood": true, "tonight": true,
+}
@@ -1,5 +1,11 @@
 package tools
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
 // Catalog contains all available tools for the chat assistant
 var Catalog = []ToolDefinition{
 	{
@@ -18,12 +24,23 @@ var Catalog = []ToolDefinition{
 				Items:       &ToolParameter{Type: "string"},
 				Description: "Genre filters: action, comedy, drama, horror, thriller, sci-fi, romance, documentary, animation, fantasy, mystery, crime, war, western, family, history",
 			},
+			{
+				Name:        "exclude_genres",
+				Type:        "array",
+				Items:       &ToolParameter{Type: "string"},
+				Description: "Genres to exclude, using the same names as genres",
+			},
 			{
 				Name:        "media_type",
 				Type:        "string",
 				Enum:        []string{"movie", "tv", "all"},
 				Description: "Type of media to search for",
 			},
+			{
+				Name:        "year",
+				Type:        "integer",
+				Description: "A single release year (e.g. 2019 for '2019 horror movies'); use instead of year_from/year_to",
+			},
 			{
 				Name:        "year_from",
 				Type:        "integer",
@@ -39,6 +56,17 @@ var Catalog = []ToolDefinition{
 				Type:        "number",
 				Description: "Minimum rating (0-10 scale)",
 			},
+			{
+				Name:        "min_popularity",
+				Type:        "number",
+				Description: "Minimum TMDb popularity score, to leave out obscure titles: 5 for 'nothing too obscure', 20 for mainstream only. Omit for hidden gems",
+			},
+			{
+				Name:        "quality",
+				Type:        "string",
+				Enum:        []string{"mainstream", "critic", "hidden-gem"},
+				Description: "Quality preset: mainstream (widely seen, any rating), critic (rating 8+, 500+ votes), or hidden-gem (rating 7.5+, 100-1000 votes) for 'hidden gem' or 'underrated' requests",
+			},
 			{
 				Name:        "language",
 				Type:        "string",
@@ -48,7 +76,19 @@ var Catalog = []ToolDefinition{
 				Name:        "providers",
 				Type:        "array",
 				Items:       &ToolParameter{Type: "string"},
-				Description: "Streaming providers to filter by: Netflix, Disney Plus, HBO Max, Amazon Prime Video, Hulu, Apple TV Plus, etc.",
+				Description: "Streaming providers to filter by: Netflix, Disney Plus, Max (formerly HBO Max), Amazon Prime Video, Hulu, Apple TV Plus, etc. Omit to search the user's own services (if configured); set only when the user names providers.",
+			},
+			{
+				Name:        "provider_match",
+				Type:        "string",
+				Enum:        []string{"any", "all"},
+				Description: "any (default): on at least one of the providers. all: on every one of them at once, only for requests like 'on both Netflix and Prime'",
+			},
+			{
+				Name:        "monetization_types",
+				Type:        "array",
+				Items:       &ToolParameter{Type: "string", Enum: []string{"flatrate", "free", "ads", "rent", "buy"}},
+				Description: "How the title can be watched (any match): flatrate (subscription), free, ads, rent, buy. E.g. ['flatrate', 'free'] to exclude rentals and purchases",
 			},
 			{
 				Name:        "actors",
@@ -62,11 +102,75 @@ var Catalog = []ToolDefinition{
 				Items:       &ToolParameter{Type: "string"},
 				Description: "Production studios: Pixar, A24, Marvel, Studio Ghibli, etc.",
 			},
+			{
+				Name:        "networks",
+				Type:        "array",
+				Items:       &ToolParameter{Type: "string"},
+				Description: "TV networks that made or aired a show: HBO, AMC, FX, BBC One, etc. Use for 'HBO shows' or 'AMC dramas' rather than providers or studios. Searches TV only",
+			},
+			{
+				Name:        "sort_by",
+				Type:        "string",
+				Enum:        []string{"popularity", "rating", "release_date", "revenue", "votes", "oldest", "lowest_rated", "least popular", "title", "random"},
+				Description: "Result order, only when the user asks for one: release_date is newest first, oldest is oldest first. Default is a relevance score",
+			},
+			{
+				Name:        "diverse",
+				Type:        "boolean",
+				Description: "Limit results from the same franchise, director, or decade, e.g. for 'best heist movies' without five Ocean's films. Slower, since each result is looked up",
+			},
+		},
+	},
+	{
+		Name:        "blend_tastes",
+		Description: "Find titles that satisfy several people's tastes at once (e.g. a watch party where one person likes rom-coms and another likes horror). Returns only titles with at least one genre from every group, ranked by how well they suit every group at once. Pick the best compromises from the results rather than titles that please only one group.",
+		Parameters: []ToolParameter{
+			{
+				Name:     "groups",
+				Type:     "array",
+				Required: true,
+				Items: &ToolParameter{
+					Type: "object",
+					Properties: []ToolParameter{
+						{
+							Name:        "label",
+							Type:        "string",
+							Required:    true,
+							Description: "Who or what this group is, e.g. 'partner' or 'me'",
+						},
+						{
+							Name:        "genres",
+							Type:        "array",
+							Required:    true,
+							Items:       &ToolParameter{Type: "string"},
+							Description: "Genres this group likes, using the same names as search_media",
+						},
+					},
+				},
+				Description: "Two or more taste groups to blend",
+			},
+			{
+				Name:        "media_type",
+				Type:        "string",
+				Enum:        []string{"movie", "tv", "all"},
+				Description: "Type of media to search for",
+			},
+			{
+				Name:        "min_rating",
+				Type:        "number",
+				Description: "Minimum rating (0-10 scale)",
+			},
+			{
+				Name:        "providers",
+				Type:        "array",
+				Items:       &ToolParameter{Type: "string"},
+				Description: "Streaming providers to filter by",
+			},
 		},
 	},
 	{
 		Name:        "get_media_details",
-		Description: "Get detailed information about a specific movie or TV show by its TMDb ID. Use this when you need more information about a specific title.",
+		Description: "Get detailed information about a specific movie or TV show by its TMDb ID, including IMDb and Rotten Tomatoes ratings when available. Use this when you need more information about a specific title.",
 		Parameters: []ToolParameter{
 			{
 				Name:        "id",
@@ -102,6 +206,110 @@ var Catalog = []ToolDefinition{
 			},
 		},
 	},
+	{
+		Name:        "get_streaming_providers_batch",
+		Description: "Get streaming availability for several movies or TV shows at once. Use this instead of repeated get_streaming_providers calls, e.g. when the user asks where to watch all of the recommended titles.",
+		Parameters: []ToolParameter{
+			{
+				Name:     "titles",
+				Type:     "array",
+				Required: true,
+				Items: &ToolParameter{
+					Type: "object",
+					Properties: []ToolParameter{
+						{
+							Name:        "id",
+							Type:        "integer",
+							Required:    true,
+							Description: "The TMDb ID of the movie or TV show",
+						},
+						{
+							Name:        "media_type",
+							Type:        "string",
+							Required:    true,
+							Enum:        []string{"movie", "tv"},
+							Description: "Whether it's a movie or TV show",
+						},
+					},
+				},
+				Description: "The titles to look up (max 20)",
+			},
+		},
+	},
+	{
+		Name:        "get_streaming_providers_by_region",
+		Description: "Compare where a movie or TV show can be watched across several countries, and where it's cheapest (free, then subscription, then rent, then buy). Use this for travelers or VPN users asking 'where in the world is this streaming?' or 'is it on Netflix in the UK?'.",
+		Parameters: []ToolParameter{
+			{
+				Name:        "id",
+				Type:        "integer",
+				Required:    true,
+				Description: "The TMDb ID of the movie or TV show",
+			},
+			{
+				Name:        "media_type",
+				Type:        "string",
+				Required:    true,
+				Enum:        []string{"movie", "tv"},
+				Description: "Whether it's a movie or TV show",
+			},
+			{
+				Name:        "regions",
+				Type:        "array",
+				Items:       &ToolParameter{Type: "string"},
+				Description: "ISO 3166-1 country codes to compare (e.g. ['US', 'GB', 'CA']). Omit for every country; the user's own region is always included",
+			},
+		},
+	},
+	{
+		Name:        "get_certifications",
+		Description: "Get a movie or TV show's age rating (certification) in each country, e.g. US PG-13, GB 12A, DE 12. Use this when the user asks whether something is suitable for a child of a given age, or how it's rated in their country.",
+		Parameters: []ToolParameter{
+			{
+				Name:        "id",
+				Type:        "integer",
+				Required:    true,
+				Description: "The TMDb ID of the movie or TV show",
+			},
+			{
+				Name:        "media_type",
+				Type:        "string",
+				Required:    true,
+				Enum:        []string{"movie", "tv"},
+				Description: "Whether it's a movie or TV show",
+			},
+			{
+				Name:        "regions",
+				Type:        "array",
+				Items:       &ToolParameter{Type: "string"},
+				Description: "ISO 3166-1 country codes to return (e.g. ['US', 'GB']). Omit for every country; the user's own region is always included",
+			},
+		},
+	},
+	{
+		Name:        "get_season",
+		Description: "Get the episodes of one season of a TV show: titles, air dates, ratings, and short synopses. Use this for questions like 'what are the best episodes of Black Mirror season 3' or 'when did season 2 air'.",
+		Parameters: []ToolParameter{
+			{
+				Name:        "id",
+				Type:        "integer",
+				Required:    true,
+				Description: "The TMDb ID of the TV show",
+			},
+			{
+				Name:        "season",
+				Type:        "integer",
+				Required:    true,
+				Description: "The season number (0 is specials on most shows)",
+			},
+			{
+				Name:        "sort_by",
+				Type:        "string",
+				Enum:        []string{"episode", "rating"},
+				Description: "episode (default, in order) or rating (best rated first)",
+			},
+		},
+	},
 	{
 		Name:        "get_similar",
 		Description: "Find movies or TV shows similar to a given title. Use this when the user likes a specific title and wants similar recommendations.",
@@ -121,6 +329,42 @@ var Catalog = []ToolDefinition{
 			},
 		},
 	},
+	{
+		Name:        "get_franchise",
+		Description: "Get the films in a movie franchise (a TMDb collection) in watch order. Use this when the user asks what order to watch a series in, or wants every film in a franchise.",
+		Parameters: []ToolParameter{
+			{
+				Name:        "title",
+				Type:        "string",
+				Required:    true,
+				Description: "The franchise name (e.g. 'Star Wars') or any film in it",
+			},
+			{
+				Name:        "watch_order",
+				Type:        "string",
+				Enum:        []string{"release", "chronological"},
+				Description: "release (default) or chronological (story order). Chronological is only known for some franchises; the result's watch_order says which order was used",
+			},
+		},
+	},
+	{
+		Name:        "get_director_films",
+		Description: "Get every film a director has directed, from their TMDb filmography, with where to watch each. Use this for 'all of Denis Villeneuve's films' or 'best Kubrick movies' rather than search_media, whose people filter is unreliable for directors.",
+		Parameters: []ToolParameter{
+			{
+				Name:        "director",
+				Type:        "string",
+				Required:    true,
+				Description: "The director's name",
+			},
+			{
+				Name:        "sort_by",
+				Type:        "string",
+				Enum:        []string{"release", "rating"},
+				Description: "release (default, oldest first) or rating (best rated first)",
+			},
+		},
+	},
 	{
 		Name:        "search_by_title",
 		Description: "Search for a movie or TV show by its title. Use this to find the TMDb ID of a specific title the user mentions.",
@@ -133,9 +377,21 @@ var Catalog = []ToolDefinition{
 			},
 		},
 	},
+	{
+		Name:        "list_filters",
+		Description: "List the genre, streaming provider, studio, and TV network names that search_media understands. Use this to map the user's wording to supported filter values; unrecognized names are silently ignored by search_media.",
+		Parameters: []ToolParameter{
+			{
+				Name:        "category",
+				Type:        "string",
+				Enum:        []string{"genres", "providers", "studios", "networks", "all"},
+				Description: "Which filter names to list (default all)",
+			},
+		},
+	},
 	{
 		Name:        "get_trakt_watchlist",
-		Description: "Get items from the user's Trakt watchlist. Only works if the user has connected their Trakt account.",
+		Description: "Get items from the user's Trakt watchlist. Only works if the user has connected their Trakt account. Use the filters for requests like 'something short and funny from my watchlist' instead of fetching the whole list.",
 		Parameters: []ToolParameter{
 			{
 				Name:        "media_type",
@@ -143,6 +399,21 @@ var Catalog = []ToolDefinition{
 				Enum:        []string{"movies", "shows", ""},
 				Description: "Filter by media type, or leave empty for all",
 			},
+			{
+				Name:        "genre",
+				Type:        "string",
+				Description: "Only items in this Trakt genre (e.g. 'comedy', 'horror', 'science-fiction')",
+			},
+			{
+				Name:        "max_runtime",
+				Type:        "integer",
+				Description: "Only items at most this many minutes long (episode length for shows)",
+			},
+			{
+				Name:        "unwatched",
+				Type:        "boolean",
+				Description: "Drop items the user has already watched on Trakt",
+			},
 		},
 	},
 	{
@@ -162,6 +433,17 @@ var Catalog = []ToolDefinition{
 			},
 		},
 	},
+	{
+		Name:        "get_next_episode",
+		Description: "Get the next episode the user hasn't watched of a show they're watching, from their Trakt progress. Use this for 'where was I in The Bear?' or 'what's my next episode?'. Only works if the user has connected their Trakt account.",
+		Parameters: []ToolParameter{
+			{
+				Name:        "title",
+				Type:        "string",
+				Description: "Show title as the user wrote it; matched against their Trakt history, then watchlist. Leave empty for the show they watched most recently.",
+			},
+		},
+	},
 	{
 		Name:        "generate_recommendations",
 		Description: "Generate AI recommendations directly based on a description. Use this when TMDb search filters aren't sufficient or for subjective/mood-based requests.",
@@ -175,8 +457,60 @@ var Catalog = []ToolDefinition{
 			{
 				Name:        "count",
 				Type:        "integer",
-				Description: "Number of recommendations to generate (default 5)",
+				Description: "Number of recommendations to generate (defaults to the user's configured count)",
 			},
 		},
 	},
 }
+
+// CatalogNames returns the names of the tools in Catalog, in order
+func CatalogNames() []string {
+	return Names(Catalog)
+}
+
+// Names returns the names of defs, in order
+func Names(defs []ToolDefinition) []string {
+	names := make([]string, len(defs))
+	for i, tool := range defs {
+		names[i] = tool.Name
+	}
+	return names
+}
+
+// CheckNames returns an error naming any of names that isn't a tool in Catalog
+func CheckNames(names []string) error {
+	var unknown []string
+	for _, name := range names {
+		if !IsKnown(name) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown tool(s) %s; the tools are: %s", strings.Join(unknown, ", "), strings.Join(CatalogNames(), ", "))
+	}
+	return nil
+}
+
+// Without returns Catalog minus the named tools
+func Without(names []string) []ToolDefinition {
+	if len(names) == 0 {
+		return Catalog
+	}
+	defs := make([]ToolDefinition, 0, len(Catalog))
+	for _, tool := range Catalog {
+		if !slices.Contains(names, tool.Name) {
+			defs = append(defs, tool)
+		}
+	}
+	return defs
+}
+
+// IsKnown reports whether name is a tool in Catalog
+func IsKnown(name string) bool {
+	for _, tool := range Catalog {
+		if tool.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -1,5 +1,11 @@
 package tools
 
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
 // ToolDefinition represents a tool that the AI can call
 type ToolDefinition struct {
 	Name        string
@@ -10,11 +16,12 @@ type ToolDefinition struct {
 // ToolParameter defines a single parameter for a tool
 type ToolParameter struct {
 	Name        string
-	Type        string         // "string", "integer", "number", "boolean", "array", "object"
+	Type        string // "string", "integer", "number", "boolean", "array", "object"
 	Description string
 	Required    bool
-	Enum        []string       // optional: constrained values
-	Items       *ToolParameter // for arrays: type of items
+	Enum        []string        // optional: constrained values
+	Items       *ToolParameter  // for arrays: type of items
+	Properties  []ToolParameter // for objects: named fields
 }
 
 // ToolCall represents a request from the AI to execute a tool
@@ -31,7 +38,9 @@ type ToolResult struct {
 	IsError    bool
 }
 
-// Helper methods for extracting typed arguments
+// Helper methods for extracting typed arguments.
+// Models sometimes stringify arguments ("7" for a number, "action,comedy" for
+// an array), so the numeric and array getters also accept those forms.
 
 // GetString extracts a string argument
 func (tc *ToolCall) GetString(key string) string {
@@ -51,6 +60,10 @@ func (tc *ToolCall) GetInt(key string) int {
 			return int(n)
 		case int:
 			return n
+		case string:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(n), 64); err == nil {
+				return int(f)
+			}
 		}
 	}
 	return 0
@@ -59,8 +72,15 @@ func (tc *ToolCall) GetInt(key string) int {
 // GetFloat extracts a float argument
 func (tc *ToolCall) GetFloat(key string) float64 {
 	if v, ok := tc.Arguments[key]; ok {
-		if f, ok := v.(float64); ok {
+		switch f := v.(type) {
+		case float64:
 			return f
+		case int:
+			return float64(f)
+		case string:
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(f), 64); err == nil {
+				return parsed
+			}
 		}
 	}
 	return 0
@@ -69,8 +89,12 @@ func (tc *ToolCall) GetFloat(key string) float64 {
 // GetBool extracts a boolean argument
 func (tc *ToolCall) GetBool(key string) bool {
 	if v, ok := tc.Arguments[key]; ok {
-		if b, ok := v.(bool); ok {
+		switch b := v.(type) {
+		case bool:
 			return b
+		case string:
+			parsed, _ := strconv.ParseBool(strings.TrimSpace(b))
+			return parsed
 		}
 	}
 	return false
@@ -79,7 +103,8 @@ func (tc *ToolCall) GetBool(key string) bool {
 // GetStringArray extracts a string array argument
 func (tc *ToolCall) GetStringArray(key string) []string {
 	if v, ok := tc.Arguments[key]; ok {
-		if arr, ok := v.([]interface{}); ok {
+		switch arr := v.(type) {
+		case []interface{}:
 			result := make([]string, 0, len(arr))
 			for _, item := range arr {
 				if s, ok := item.(string); ok {
@@ -87,6 +112,47 @@ func (tc *ToolCall) GetStringArray(key string) []string {
 				}
 			}
 			return result
+		case string:
+			// "action,comedy" or a JSON-encoded array
+			return splitStringList(arr)
+		}
+	}
+	return nil
+}
+
+// splitStringList parses a stringified list: a JSON array or comma-separated values
+func splitStringList(s string) []string {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "[") {
+		var arr []string
+		if err := json.Unmarshal([]byte(s), &arr); err == nil {
+			return arr
+		}
+		s = strings.Trim(s, "[]")
+	}
+
+	var result []string
+	for _, part := range strings.Split(s, ",") {
+		part = strings.Trim(strings.TrimSpace(part), `"'`)
+		if part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
+
+// GetObjectArray extracts an array of objects argument
+func (tc *ToolCall) GetObjectArray(key string) []ToolCall {
+	if v, ok := tc.Arguments[key]; ok {
+		if arr, ok := v.([]interface{}); ok {
+			result := make([]ToolCall, 0, len(arr))
+			for _, item := range arr {
+				if obj, ok := item.(map[string]interface{}); ok {
+					// Wrap in a ToolCall so the typed getters can be reused
+					result = append(result, ToolCall{Arguments: obj})
+				}
+			}
+			return result
 		}
 	}
 	return nil
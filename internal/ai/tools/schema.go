@@ -2,10 +2,11 @@ package tools
 
 import (
 	"github.com/sashabaranov/go-openai"
-	"github.com/sashabaranov/go-openai/jsonschema"
 )
 
-// ToOpenAITools converts tool definitions to OpenAI tool format
+// ToOpenAITools converts tool definitions to OpenAI tool format.
+// Functions use strict mode so arguments always match the schema (e.g. arrays
+// arrive as arrays, not comma-joined strings).
 func ToOpenAITools(tools []ToolDefinition) []openai.Tool {
 	result := make([]openai.Tool, len(tools))
 	for i, tool := range tools {
@@ -14,59 +15,75 @@ func ToOpenAITools(tools []ToolDefinition) []openai.Tool {
 			Function: &openai.FunctionDefinition{
 				Name:        tool.Name,
 				Description: tool.Description,
-				Parameters:  toOpenAISchema(tool.Parameters),
+				Strict:      true,
+				Parameters:  toOpenAIStrictSchema(tool.Parameters),
 			},
 		}
 	}
 	return result
 }
 
-func toOpenAISchema(params []ToolParameter) jsonschema.Definition {
-	properties := make(map[string]jsonschema.Definition)
-	required := make([]string, 0)
+// toOpenAIStrictSchema builds an object schema that satisfies OpenAI strict mode:
+// every property is listed as required, optional ones are made nullable instead,
+// and additional properties are disallowed
+func toOpenAIStrictSchema(params []ToolParameter) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := make([]string, 0, len(params))
 
 	for _, p := range params {
-		properties[p.Name] = paramToJSONSchema(p)
-		if p.Required {
-			required = append(required, p.Name)
+		prop := paramToOpenAISchema(p)
+		if !p.Required {
+			prop["type"] = []string{prop["type"].(string), "null"}
+			if enum, ok := prop["enum"].([]interface{}); ok {
+				prop["enum"] = append(enum, nil)
+			}
 		}
+		properties[p.Name] = prop
+		required = append(required, p.Name)
 	}
 
-	return jsonschema.Definition{
-		Type:       jsonschema.Object,
-		Properties: properties,
-		Required:   required,
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
 	}
 }
 
-func paramToJSONSchema(p ToolParameter) jsonschema.Definition {
-	def := jsonschema.Definition{
-		Description: p.Description,
+func paramToOpenAISchema(p ToolParameter) map[string]interface{} {
+	if p.Type == "object" {
+		schema := toOpenAIStrictSchema(p.Properties)
+		if p.Description != "" {
+			schema["description"] = p.Description
+		}
+		return schema
+	}
+
+	schema := map[string]interface{}{
+		"type": p.Type,
+	}
+	if p.Description != "" {
+		schema["description"] = p.Description
 	}
 
-	switch p.Type {
-	case "string":
-		def.Type = jsonschema.String
-		if len(p.Enum) > 0 {
-			def.Enum = p.Enum
+	if len(p.Enum) > 0 {
+		enum := make([]interface{}, len(p.Enum))
+		for i, v := range p.Enum {
+			enum[i] = v
 		}
-	case "integer":
-		def.Type = jsonschema.Integer
-	case "number":
-		def.Type = jsonschema.Number
-	case "boolean":
-		def.Type = jsonschema.Boolean
-	case "array":
-		def.Type = jsonschema.Array
+		schema["enum"] = enum
+	}
+
+	if p.Type == "array" {
+		// Strict mode requires an item type; default to strings
+		items := ToolParameter{Type: "string"}
 		if p.Items != nil {
-			itemDef := paramToJSONSchema(*p.Items)
-			def.Items = &itemDef
+			items = *p.Items
 		}
-	case "object":
-		def.Type = jsonschema.Object
+		schema["items"] = paramToOpenAISchema(items)
 	}
 
-	return def
+	return schema
 }
 
 // ToAnthropicInputSchema converts tool parameters to Anthropic input schema format
@@ -108,5 +125,11 @@ func paramToAnthropicSchema(p ToolParameter) map[string]interface{} {
 		schema["items"] = paramToAnthropicSchema(*p.Items)
 	}
 
+	if p.Type == "object" && len(p.Properties) > 0 {
+		for k, v := range ToAnthropicInputSchema(p.Properties) {
+			schema[k] = v
+		}
+	}
+
 	return schema
 }
@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Store is a simple on-disk cache of JSON values that expire after a TTL.
+// Each key is stored in its own file, so concurrent lookups need no locking.
+type Store struct {
+	dir string
+	ttl time.Duration
+}
+
+// pruneInterval is how often Set sweeps a store's directory for expired entries
+const pruneInterval = 24 * time.Hour
+
+// pruneMarker is the file whose modification time records a store's last sweep
+const pruneMarker = ".pruned"
+
+// entry is the on-disk format of a cached value
+type entry struct {
+	StoredAt time.Time       `json:"stored_at"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// New creates a store that keeps entries under dir for ttl
+func New(dir string, ttl time.Duration) *Store {
+	return &Store{dir: dir, ttl: ttl}
+}
+
+// Get loads the value cached under key into v. Returns false if there is no
+// entry, it has expired, or it can't be read.
+func (s *Store) Get(key string, v interface{}) bool {
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		return false
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return false
+	}
+	if time.Since(e.StoredAt) > s.ttl {
+		os.Remove(s.path(key))
+		return false
+	}
+	return json.Unmarshal(e.Value, v) == nil
+}
+
+// Set caches v under key. Failures are ignored; a cache miss is never fatal.
+func (s *Store) Set(key string, v interface{}) {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(entry{StoredAt: time.Now(), Value: value})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return
+	}
+
+	// Write to a temp file and rename so readers never see a partial entry
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), s.path(key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+
+	s.maybePrune()
+}
+
+// maybePrune removes expired entries, at most once per pruneInterval, so
+// entries that are never looked up again don't pile up forever
+func (s *Store) maybePrune() {
+	marker := filepath.Join(s.dir, pruneMarker)
+	if info, err := os.Stat(marker); err == nil && time.Since(info.ModTime()) < pruneInterval {
+		return
+	}
+	f, err := os.Create(marker)
+	if err != nil {
+		return
+	}
+	f.Close()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return
+	}
+	for _, d := range entries {
+		if d.IsDir() || d.Name() == pruneMarker {
+			continue
+		}
+		// Entries are written in one go, so the file time is when they were stored
+		if info, err := d.Info(); err == nil && time.Since(info.ModTime()) > s.ttl {
+			os.Remove(filepath.Join(s.dir, d.Name()))
+		}
+	}
+}
+
+// Delete removes the entry for key, if any
+func (s *Store) Delete(key string) {
+	os.Remove(s.path(key))
+}
+
+// path returns the file for key. Keys are hashed so any string is a safe filename.
+func (s *Store) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Clear removes every cached entry under dir and returns how many were removed
+func Clear(dir string) (int, error) {
+	var removed int
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		return nil
+	})
+	if err != nil {
+		return removed, err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return removed, err
+	}
+	return removed, nil
+}
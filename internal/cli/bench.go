@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"wtfsiw/internal/ai"
+	"wtfsiw/internal/textutil"
+)
+
+// benchMaxColumnWidth caps each provider's column in the side-by-side view
+const benchMaxColumnWidth = 48
+
+// BenchResult is one provider's timings and output for a benchmarked query
+type BenchResult struct {
+	Provider        string
+	ExtractTime     time.Duration
+	RecommendTime   time.Duration
+	Params          string // extracted search params as JSON
+	Recommendations []ai.Recommendation
+	ExtractErr      error
+	RecommendErr    error
+}
+
+// Total is the combined time of both steps
+func (r BenchResult) Total() time.Duration {
+	return r.ExtractTime + r.RecommendTime
+}
+
+// PrintBenchResults shows each provider's results, side by side when styled
+func PrintBenchResults(results []BenchResult, styled bool) {
+	fastest := -1
+	for i, r := range results {
+		if r.ExtractErr == nil && r.RecommendErr == nil && (fastest < 0 || r.Total() < results[fastest].Total()) {
+			fastest = i
+		}
+	}
+	if len(results) < 2 {
+		fastest = -1 // nothing to compare against
+	}
+
+	if !styled {
+		for i, r := range results {
+			fmt.Println(benchColumn(r, i == fastest, 0, false))
+			fmt.Println()
+		}
+		return
+	}
+
+	width := getTerminalWidth()/len(results) - 2
+	if width > benchMaxColumnWidth {
+		width = benchMaxColumnWidth
+	}
+	columns := make([]string, len(results))
+	for i, r := range results {
+		columns[i] = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(surface2).
+			Padding(0, 1).
+			Width(width).
+			Render(benchColumn(r, i == fastest, width-2, true))
+	}
+	fmt.Println(lipgloss.JoinHorizontal(lipgloss.Top, columns...))
+	fmt.Println()
+}
+
+// benchColumn renders one provider's results. width truncates long lines (0 = no limit).
+func benchColumn(r BenchResult, fastest bool, width int, styled bool) string {
+	render := func(style lipgloss.Style, s string) string {
+		if !styled {
+			return s
+		}
+		return style.Render(s)
+	}
+	label := lipgloss.NewStyle().Foreground(mutedColor)
+
+	var sb strings.Builder
+	name := strings.ToUpper(r.Provider[:1]) + r.Provider[1:]
+	if r.Provider == "openai" {
+		name = "OpenAI"
+	}
+	sb.WriteString(render(headerStyle, name))
+	if fastest {
+		sb.WriteString(" " + render(whyWatchStyle, "⚡ fastest"))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(fmt.Sprintf("%s %s\n", render(label, "Extract:  "), formatDuration(r.ExtractTime)))
+	sb.WriteString(fmt.Sprintf("%s %s\n", render(label, "Recommend:"), formatDuration(r.RecommendTime)))
+	sb.WriteString(fmt.Sprintf("%s %s\n\n", render(label, "Total:    "), render(ratingStyle, formatDuration(r.Total()))))
+
+	sb.WriteString(render(indexStyle, "Search params") + "\n")
+	if r.ExtractErr != nil {
+		sb.WriteString(render(lipgloss.NewStyle().Foreground(red), "✗ "+r.ExtractErr.Error()) + "\n")
+	} else {
+		sb.WriteString(render(yearStyle, r.Params) + "\n")
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(render(indexStyle, "Recommendations") + "\n")
+	if r.RecommendErr != nil {
+		sb.WriteString(render(lipgloss.NewStyle().Foreground(red), "✗ "+r.RecommendErr.Error()) + "\n")
+	}
+	for i, rec := range r.Recommendations {
+		line := fmt.Sprintf("%d. %s (%s)", i+1, rec.Title, rec.Year)
+		if width > 0 {
+			line = textutil.Truncate(line, width)
+		}
+		sb.WriteString(render(titleStyle, line) + "\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// formatDuration renders a step time as "1.24s"
+func formatDuration(d time.Duration) string {
+	return fmt.Sprintf("%.2fs", d.Seconds())
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"wtfsiw/internal/tmdb"
+)
+
+// coverageBarWidth is the length of the bar for the service with the most titles
+const coverageBarWidth = 20
+
+// PrintCoverage shows how many of a query's matches each service streams,
+// most first, with the user's own services marked
+func PrintCoverage(cov tmdb.Coverage, region string, styled bool) {
+	render := func(style lipgloss.Style, s string) string {
+		if !styled {
+			return s
+		}
+		return style.Render(s)
+	}
+	label := lipgloss.NewStyle().Foreground(mutedColor)
+	bar := lipgloss.NewStyle().Foreground(secondaryColor)
+	yours := lipgloss.NewStyle().Foreground(successColor)
+
+	if cov.Total == 0 {
+		if styled {
+			PrintNoResults()
+		} else {
+			fmt.Println("No results found.")
+		}
+		return
+	}
+
+	matches := "matches"
+	if cov.Total == 1 {
+		matches = "match"
+	}
+	fmt.Println(render(headerStyle, fmt.Sprintf("Of %d %s in %s:", cov.Total, matches, region)))
+	fmt.Println()
+
+	nameWidth := len("none")
+	most := 1
+	for _, p := range cov.Providers {
+		if len(p.Provider) > nameWidth {
+			nameWidth = len(p.Provider)
+		}
+		if p.Titles > most {
+			most = p.Titles
+		}
+	}
+	if cov.None > most {
+		most = cov.None
+	}
+
+	row := func(name string, count int, mark string) {
+		width := count * coverageBarWidth / most
+		if width == 0 && count > 0 {
+			width = 1
+		}
+		fmt.Printf("  %-*s %3d  %s%s\n", nameWidth, name, count,
+			render(bar, strings.Repeat("█", width)), mark)
+	}
+	for _, p := range cov.Providers {
+		mark := ""
+		if p.Yours {
+			mark = " " + render(yours, "✓ yours")
+		}
+		row(p.Provider, p.Titles, mark)
+	}
+	row("none", cov.None, " "+render(label, "rent/buy only or unavailable"))
+	fmt.Println()
+
+	if cov.HasYours {
+		fmt.Printf("%s %s\n\n", render(label, "Your services stream"),
+			render(titleStyle, fmt.Sprintf("%d of %d (%d%%)", cov.Yours, cov.Total, cov.Yours*100/cov.Total)))
+	}
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"wtfsiw/internal/ai"
+)
+
+// formatPresets are the template-based --format names (csv and tsv are
+// written with encoding/csv instead)
+var formatPresets = map[string]string{
+	"oneline": `{{.Index}}. {{.Title}} ({{.Year}}) {{printf "%.1f" .Rating}}{{if .Providers}} - {{join .Providers ", "}}{{end}}`,
+}
+
+// formatColumns are the csv/tsv columns, in order
+var formatColumns = []string{"index", "title", "year", "media_type", "rating", "providers", "why_watch"}
+
+// FormatItem is what a --format template is executed with: a result plus
+// its 1-based position, e.g. {{.Index}} {{.Title}} ({{.Year}}) {{.Rating}}
+type FormatItem struct {
+	ai.Recommendation
+	Index int
+}
+
+// Formatter prints results in a user-chosen layout for scripting
+type Formatter struct {
+	preset string // "csv" or "tsv" ("" for templates)
+	tmpl   *template.Template
+}
+
+// NewFormatter parses a --format value: a preset name (oneline, csv, tsv)
+// or a Go template. Templates are checked against an empty result, so
+// unknown fields are reported before any search runs.
+func NewFormatter(spec string) (*Formatter, error) {
+	if spec == "csv" || spec == "tsv" {
+		return &Formatter{preset: spec}, nil
+	}
+	if preset, ok := formatPresets[spec]; ok {
+		spec = preset
+	}
+
+	tmpl, err := template.New("format").Funcs(template.FuncMap{
+		"join": strings.Join,
+	}).Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --format template: %w\n\nUse oneline, csv, tsv, or a template like '{{.Title}} ({{.Year}}) {{.Rating}}'", err)
+	}
+	if err := tmpl.Execute(io.Discard, FormatItem{}); err != nil {
+		return nil, fmt.Errorf("invalid --format template: %w\n\nAvailable fields: .Index .Title .Year .MediaType .Rating .Genres .Overview .WhyWatch .Providers .Language .VoteCount .InLibrary .OMDbRatings .BingeTime", err)
+	}
+	return &Formatter{tmpl: tmpl}, nil
+}
+
+// Print writes one line per result. csv and tsv start with a header row.
+func (f *Formatter) Print(w io.Writer, recommendations []ai.Recommendation) error {
+	if f.preset != "" {
+		return f.printTable(w, recommendations)
+	}
+	for i, rec := range recommendations {
+		if err := f.tmpl.Execute(w, FormatItem{Recommendation: rec, Index: i + 1}); err != nil {
+			return fmt.Errorf("failed to format %q: %w", rec.Title, err)
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// printTable writes the results as csv or tsv
+func (f *Formatter) printTable(w io.Writer, recommendations []ai.Recommendation) error {
+	out := csv.NewWriter(w)
+	if f.preset == "tsv" {
+		out.Comma = '\t'
+	}
+	if err := out.Write(formatColumns); err != nil {
+		return err
+	}
+	for i, rec := range recommendations {
+		row := []string{
+			strconv.Itoa(i + 1),
+			rec.Title,
+			rec.Year,
+			rec.MediaType,
+			strconv.FormatFloat(rec.Rating, 'f', 1, 64),
+			strings.Join(rec.Providers, "; "),
+			rec.WhyWatch,
+		}
+		if err := out.Write(row); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
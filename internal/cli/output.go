@@ -1,115 +1,180 @@
 package cli
 
 import (
+	"bufio"
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"golang.org/x/term"
 
 	"wtfsiw/internal/ai"
+	"wtfsiw/internal/textutil"
+	"wtfsiw/internal/theme"
+	"wtfsiw/internal/tmdb"
 )
 
-// Catppuccin Mocha colors
+// Active color palette (Catppuccin Mocha by default, see ApplyTheme)
 var (
 	// Accent colors
-	mauve    = lipgloss.Color("#cba6f7")
-	red      = lipgloss.Color("#f38ba8")
-	peach    = lipgloss.Color("#fab387")
-	yellow   = lipgloss.Color("#f9e2af")
-	green    = lipgloss.Color("#a6e3a1")
-	teal     = lipgloss.Color("#94e2d5")
-	sapphire = lipgloss.Color("#74c7ec")
-	lavender = lipgloss.Color("#b4befe")
+	mauve    lipgloss.TerminalColor
+	red      lipgloss.TerminalColor
+	peach    lipgloss.TerminalColor
+	yellow   lipgloss.TerminalColor
+	green    lipgloss.TerminalColor
+	teal     lipgloss.TerminalColor
+	sapphire lipgloss.TerminalColor
+	lavender lipgloss.TerminalColor
 
 	// Text colors
-	text     = lipgloss.Color("#cdd6f4")
-	subtext0 = lipgloss.Color("#a6adc8")
+	text     lipgloss.TerminalColor
+	subtext0 lipgloss.TerminalColor
 
 	// Surface colors
-	surface2 = lipgloss.Color("#585b70")
-	surface1 = lipgloss.Color("#45475a")
-	overlay1 = lipgloss.Color("#7f849c")
+	surface2 lipgloss.TerminalColor
+	surface1 lipgloss.TerminalColor
+	overlay1 lipgloss.TerminalColor
 
 	// Base colors
-	base = lipgloss.Color("#1e1e2e")
+	base lipgloss.TerminalColor
 
 	// Semantic aliases
-	primaryColor   = mauve
+	primaryColor   lipgloss.TerminalColor
+	secondaryColor lipgloss.TerminalColor
+	accentColor    lipgloss.TerminalColor
+	mutedColor     lipgloss.TerminalColor
+	successColor   lipgloss.TerminalColor
+
+	// Styles
+	headerStyle   lipgloss.Style
+	queryStyle    lipgloss.Style
+	titleStyle    lipgloss.Style
+	yearStyle     lipgloss.Style
+	ratingStyle   lipgloss.Style
+	providerStyle lipgloss.Style
+	whyWatchStyle lipgloss.Style
+	overviewStyle lipgloss.Style
+	summaryStyle  lipgloss.Style
+	indexStyle    lipgloss.Style
+	dividerStyle  lipgloss.Style
+
+	// Spinner frames
+	spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+)
+
+// ApplyTheme sets the active color palette and rebuilds all CLI styles
+func ApplyTheme(p theme.Palette) {
+	mauve = p.Mauve
+	red = p.Red
+	peach = p.Peach
+	yellow = p.Yellow
+	green = p.Green
+	teal = p.Teal
+	sapphire = p.Sapphire
+	lavender = p.Lavender
+	text = p.Text
+	subtext0 = p.Subtext0
+	surface2 = p.Surface2
+	surface1 = p.Surface1
+	overlay1 = p.Overlay1
+	base = p.Base
+
+	buildStyles()
+}
+
+func init() {
+	ApplyTheme(theme.Mocha)
+}
+
+// buildStyles (re)creates the styles from the active palette
+func buildStyles() {
+	// Semantic aliases
+	primaryColor = mauve
 	secondaryColor = teal
-	accentColor    = yellow
-	mutedColor     = overlay1
-	successColor   = green
+	accentColor = yellow
+	mutedColor = overlay1
+	successColor = green
 
 	// Styles
 	headerStyle = lipgloss.NewStyle().
-			Foreground(mauve).
-			Bold(true)
+		Foreground(mauve).
+		Bold(true)
 
 	queryStyle = lipgloss.NewStyle().
-			Foreground(sapphire).
-			Italic(true)
+		Foreground(sapphire).
+		Italic(true)
 
 	titleStyle = lipgloss.NewStyle().
-			Foreground(yellow).
-			Bold(true)
+		Foreground(yellow).
+		Bold(true)
 
 	yearStyle = lipgloss.NewStyle().
-			Foreground(subtext0)
+		Foreground(subtext0)
 
 	ratingStyle = lipgloss.NewStyle().
-			Foreground(yellow)
+		Foreground(yellow)
 
 	providerStyle = lipgloss.NewStyle().
-			Foreground(base).
-			Background(teal).
-			Padding(0, 1)
+		Foreground(base).
+		Background(teal).
+		Padding(0, 1)
 
 	whyWatchStyle = lipgloss.NewStyle().
-			Foreground(green).
-			Italic(true)
+		Foreground(green).
+		Italic(true)
 
 	overviewStyle = lipgloss.NewStyle().
-			Foreground(text)
+		Foreground(text)
 
 	summaryStyle = lipgloss.NewStyle().
-			Foreground(teal).
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(surface2).
-			Padding(0, 1)
+		Foreground(teal).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(surface2).
+		Padding(0, 1)
 
 	indexStyle = lipgloss.NewStyle().
-			Foreground(mauve).
-			Bold(true)
+		Foreground(mauve).
+		Bold(true)
 
 	dividerStyle = lipgloss.NewStyle().
-			Foreground(surface1)
-
-	// Spinner frames
-	spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
-)
+		Foreground(surface1)
+}
 
-// Spinner handles animated loading indicator
+// Spinner handles animated loading indicator. Stop and StopWithMessage are
+// safe to call more than once, and without Start.
 type Spinner struct {
-	message string
-	done    chan bool
-	ticker  *time.Ticker
+	message  string
+	done     chan struct{}
+	exited   chan struct{} // closed when the animation goroutine returns
+	ticker   *time.Ticker
+	stopOnce sync.Once
 }
 
 // NewSpinner creates a new spinner with the given message
 func NewSpinner(message string) *Spinner {
 	return &Spinner{
 		message: message,
-		done:    make(chan bool),
+		done:    make(chan struct{}),
 	}
 }
 
 // Start begins the spinner animation
 func (s *Spinner) Start() {
+	if s.ticker != nil {
+		return
+	}
+	select {
+	case <-s.done:
+		return // already stopped
+	default:
+	}
 	s.ticker = time.NewTicker(80 * time.Millisecond)
+	s.exited = make(chan struct{})
 	go func() {
+		defer close(s.exited)
 		frame := 0
 		for {
 			select {
@@ -124,18 +189,35 @@ func (s *Spinner) Start() {
 	}()
 }
 
+// stop ends the animation and waits for the goroutine to exit, so no frame is
+// drawn after the line is cleared. Reports false if already stopped.
+func (s *Spinner) stop() bool {
+	stopped := false
+	s.stopOnce.Do(func() {
+		stopped = true
+		close(s.done)
+		if s.ticker != nil {
+			s.ticker.Stop()
+			<-s.exited
+		}
+	})
+	return stopped
+}
+
 // Stop ends the spinner animation
 func (s *Spinner) Stop() {
-	s.ticker.Stop()
-	s.done <- true
+	if !s.stop() {
+		return
+	}
 	// Clear the line
 	fmt.Print("\r\033[K")
 }
 
 // StopWithMessage ends spinner and shows a completion message
 func (s *Spinner) StopWithMessage(msg string) {
-	s.ticker.Stop()
-	s.done <- true
+	if !s.stop() {
+		return
+	}
 	fmt.Print("\r\033[K")
 	checkmark := lipgloss.NewStyle().Foreground(successColor).Render("✓")
 	fmt.Printf("%s %s\n", checkmark, msg)
@@ -181,7 +263,10 @@ func PrintRecommendation(index int, rec ai.Recommendation, animate bool) {
 
 	// Rating with stars
 	stars := renderStars(rec.Rating)
-	ratingStr := ratingStyle.Render(fmt.Sprintf("%s %.1f/10", stars, rec.Rating))
+	ratingStr := ratingStyle.Render(fmt.Sprintf("%s %s", stars, rec.RatingSummary()))
+	if !(rec.Rating > 0) && len(rec.OMDbRatings) == 0 {
+		ratingStr = ratingStyle.Render(stars) // no "0.0/10" next to N/A
+	}
 
 	// Print with optional animation
 	if animate {
@@ -207,6 +292,19 @@ func PrintRecommendation(index int, rec ai.Recommendation, animate bool) {
 		fmt.Println(providerStr)
 	}
 
+	if rec.BingeTime > 0 {
+		fmt.Printf("   %s\n", yearStyle.Render("⏱ "+tmdb.BingeLabel(rec.BingeTime)))
+	}
+
+	if rec.InLibrary {
+		fmt.Printf("   %s\n", whyWatchStyle.Render("💾 In your library"))
+	}
+
+	// Original language, when it isn't the user's
+	if note := tmdb.LanguageNote(rec.Language); note != "" {
+		fmt.Printf("   %s\n", yearStyle.Render("🗣 "+note))
+	}
+
 	// Why watch (AI explanation)
 	if rec.WhyWatch != "" {
 		why := whyWatchStyle.Render("💡 " + rec.WhyWatch)
@@ -220,9 +318,7 @@ func PrintRecommendation(index int, rec ai.Recommendation, animate bool) {
 		if maxLen > 120 {
 			maxLen = 120
 		}
-		if len(overview) > maxLen {
-			overview = overview[:maxLen-3] + "..."
-		}
+		overview = textutil.Truncate(overview, maxLen)
 		fmt.Printf("   %s\n", overviewStyle.Render(overview))
 	}
 
@@ -248,6 +344,28 @@ func PrintNoResults() {
 	fmt.Println(msg)
 }
 
+// PrintNote shows a muted informational message
+func PrintNote(msg string) {
+	noteStyle := lipgloss.NewStyle().
+		Foreground(mutedColor).
+		Italic(true)
+	fmt.Printf("%s %s\n", noteStyle.Render("ℹ"), noteStyle.Render(msg))
+}
+
+// Confirm asks a yes/no question, defaulting to no. It returns false without
+// asking when stdin isn't a terminal, so piped runs never block on it.
+func Confirm(question string) bool {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false
+	}
+	promptStyle := lipgloss.NewStyle().Foreground(lavender)
+	fmt.Print(promptStyle.Render(question + " [y/N] "))
+
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
 // PrintError shows a styled error message
 func PrintError(err error) {
 	errStyle := lipgloss.NewStyle().
@@ -264,7 +382,18 @@ func typewriter(text string, delay time.Duration) {
 	}
 }
 
+// noRatingStars stands in for the stars of an unrated title (0, negative, or
+// NaN), the same width so ratings stay aligned
+const noRatingStars = " N/A "
+
 func renderStars(rating float64) string {
+	if !(rating > 0) {
+		return noRatingStars
+	}
+	if rating > 10 {
+		rating = 10 // some sources and AI output go past 10
+	}
+
 	stars := int(rating / 2)
 	halfStar := (rating/2 - float64(stars)) >= 0.5
 
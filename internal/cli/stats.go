@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"wtfsiw/internal/trakt"
+)
+
+// Watchlist dashboard layout
+const (
+	statsTopGenres = 8
+	statsBarWidth  = 20
+)
+
+// PrintWatchlistStats renders watchlist stats as a small dashboard
+func PrintWatchlistStats(stats trakt.WatchlistStats) {
+	fmt.Println()
+	fmt.Println(headerStyle.Render("📊 Your Watchlist"))
+	fmt.Println()
+
+	label := lipgloss.NewStyle().Foreground(mutedColor)
+	value := lipgloss.NewStyle().Foreground(text).Bold(true)
+
+	lines := []string{
+		fmt.Sprintf("%s %s  %s %s  %s %s",
+			value.Render(fmt.Sprint(stats.Total)), label.Render(plural(stats.Total, "title")),
+			value.Render(fmt.Sprint(stats.Movies)), label.Render(plural(stats.Movies, "movie")),
+			value.Render(fmt.Sprint(stats.Shows)), label.Render(plural(stats.Shows, "show"))),
+		fmt.Sprintf("%s %s", value.Render(formatRuntime(stats.RuntimeMinutes)), label.Render("of content")),
+	}
+	if stats.AverageRating > 0 {
+		lines = append(lines, fmt.Sprintf("%s %s", label.Render("Average rating"), ratingStyle.Render(fmt.Sprintf("%.1f/10", stats.AverageRating))))
+	}
+	if stats.Oldest != nil {
+		lines = append(lines, fmt.Sprintf("%s %s %s", label.Render("Oldest"), titleStyle.Render(stats.Oldest.GetDisplayTitle()), yearStyle.Render(fmt.Sprintf("(%d)", stats.Oldest.GetDisplayYear()))))
+	}
+	if stats.Newest != nil {
+		lines = append(lines, fmt.Sprintf("%s %s %s", label.Render("Newest"), titleStyle.Render(stats.Newest.GetDisplayTitle()), yearStyle.Render(fmt.Sprintf("(%d)", stats.Newest.GetDisplayYear()))))
+	}
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(surface2).
+		Padding(0, 1)
+	fmt.Println(box.Render(strings.Join(lines, "\n")))
+
+	if len(stats.Genres) == 0 {
+		fmt.Println()
+		return
+	}
+
+	fmt.Println()
+	fmt.Println(indexStyle.Render("Top genres"))
+
+	genres := stats.Genres
+	if len(genres) > statsTopGenres {
+		genres = genres[:statsTopGenres]
+	}
+	nameWidth := 0
+	for _, g := range genres {
+		if len(g.Genre) > nameWidth {
+			nameWidth = len(g.Genre)
+		}
+	}
+
+	bar := lipgloss.NewStyle().Foreground(secondaryColor)
+	most := genres[0].Count
+	for _, g := range genres {
+		width := g.Count * statsBarWidth / most
+		if width == 0 {
+			width = 1
+		}
+		fmt.Printf("  %-*s %s %s\n", nameWidth, g.Genre, bar.Render(strings.Repeat("█", width)), label.Render(fmt.Sprint(g.Count)))
+	}
+	fmt.Println()
+}
+
+// formatRuntime renders a total runtime as "47 hours" (or minutes when under an hour)
+func formatRuntime(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%d minutes", minutes)
+	}
+	hours := (minutes + 30) / 60
+	if hours == 1 {
+		return "1 hour"
+	}
+	if hours >= 48 {
+		return fmt.Sprintf("%d hours (%.1f days)", hours, float64(minutes)/(60*24))
+	}
+	return fmt.Sprintf("%d hours", hours)
+}
+
+// plural adds an "s" to word unless n is 1
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
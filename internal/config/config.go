@@ -1,9 +1,11 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -12,19 +14,26 @@ type Config struct {
 	AI          AIConfig          `mapstructure:"ai"`
 	TMDB        TMDBConfig        `mapstructure:"tmdb"`
 	Trakt       TraktConfig       `mapstructure:"trakt"`
+	OMDB        OMDBConfig        `mapstructure:"omdb"`
 	Preferences PreferencesConfig `mapstructure:"preferences"`
 }
 
 type AIConfig struct {
-	Provider     string `mapstructure:"provider"`
-	ClaudeAPIKey string `mapstructure:"claude_api_key"`
-	OpenAIAPIKey string `mapstructure:"openai_api_key"`
+	Provider      string   `mapstructure:"provider"`
+	ClaudeAPIKey  string   `mapstructure:"claude_api_key"`
+	OpenAIAPIKey  string   `mapstructure:"openai_api_key"`
+	Temperature   *float64 `mapstructure:"temperature"`    // nil uses the provider's default
+	DisabledTools []string `mapstructure:"disabled_tools"` // chat tools the model isn't offered
 }
 
 type TMDBConfig struct {
 	APIKey string `mapstructure:"api_key"`
 }
 
+type OMDBConfig struct {
+	APIKey string `mapstructure:"api_key"`
+}
+
 type TraktConfig struct {
 	ClientID     string `mapstructure:"client_id"`
 	ClientSecret string `mapstructure:"client_secret"`
@@ -32,15 +41,80 @@ type TraktConfig struct {
 }
 
 type PreferencesConfig struct {
-	DefaultType string  `mapstructure:"default_type"`
-	Region      string  `mapstructure:"region"`
-	Language    string  `mapstructure:"language"`
-	MinRating   float64 `mapstructure:"min_rating"`
-	MaxResults  int     `mapstructure:"max_results"`
+	DefaultType        string   `mapstructure:"default_type"`
+	Region             string   `mapstructure:"region"`
+	Language           string   `mapstructure:"language"`
+	MinRating          float64  `mapstructure:"min_rating"`
+	Theme              string   `mapstructure:"theme"`
+	KidsMode           bool     `mapstructure:"kids_mode"`
+	IncludeAdult       bool     `mapstructure:"include_adult"`
+	CollapseDuplicates bool     `mapstructure:"collapse_duplicates"`
+	AutoTitle          bool     `mapstructure:"auto_title"`   // ask the model to name chat sessions
+	AIFallback         bool     `mapstructure:"ai_fallback"`  // use AI suggestions when TMDb finds nothing
+	AICount            int      `mapstructure:"ai_count"`     // default number of AI-generated recommendations
+	SearchCount        int      `mapstructure:"search_count"` // default number of TMDb search results
+	CacheResponses     bool     `mapstructure:"cache_responses"`
+	ForeignAudio       string   `mapstructure:"foreign_audio"`       // "original", "subbed", "dubbed", or "" for no preference
+	LibraryPath        string   `mapstructure:"library_path"`        // local media folder to cross-reference ("" = off)
+	Providers          []string `mapstructure:"providers"`           // services searched when a query names none (empty = all)
+	MinPopularity      float64  `mapstructure:"min_popularity"`      // TMDb popularity floor for searches (0 = off)
+	ExcludeGenres      []string `mapstructure:"exclude_genres"`      // genres left out of every search
+	BlockedTitles      []string `mapstructure:"blocked_titles"`      // titles (or franchise names) never shown
+	PrimaryProvider    string   `mapstructure:"primary_provider"`    // service to favor in results, "rotate" to spread them ("" = off)
+	TitleSuggestions   bool     `mapstructure:"title_suggestions"`   // suggest TMDb titles while typing in the TUI
+	ShowSearchFilters  bool     `mapstructure:"show_search_filters"` // show the filters a chat search used above its results
+	ChatMaxMessages    int      `mapstructure:"chat_max_messages"`   // summarize older chat messages past this many (0 = never)
+	NeverRecommend     []string `mapstructure:"never_recommend"`     // free-text taste rules, e.g. "musicals", "jump-scare horror"
+	Diverse            bool     `mapstructure:"diverse"`             // always cap results per collection/director/decade (--diverse)
+	MaxPerCollection   int      `mapstructure:"max_per_collection"`  // diverse results from one collection (0 = no cap)
+	MaxPerDirector     int      `mapstructure:"max_per_director"`    // diverse results by one director or creator (0 = no cap)
+	MaxPerDecade       int      `mapstructure:"max_per_decade"`      // diverse results from one decade (0 = no cap)
+	BingeTime          bool     `mapstructure:"binge_time"`          // show a TV show's total runtime on cards and in details
+}
+
+// Fallbacks used when counts are unset or invalid
+const (
+	defaultAICount     = 5
+	defaultSearchCount = 10
+)
+
+// GetAICount returns the default number of AI-generated recommendations
+func (p PreferencesConfig) GetAICount() int {
+	if p.AICount > 0 {
+		return p.AICount
+	}
+	return defaultAICount
+}
+
+// GetSearchCount returns the default number of TMDb search results
+func (p PreferencesConfig) GetSearchCount() int {
+	if p.SearchCount > 0 {
+		return p.SearchCount
+	}
+	return defaultSearchCount
 }
 
 var cfg *Config
 
+// profile is the active profile's name ("" for the base config alone)
+var profile string
+
+// SetProfile selects a named profile (~/.config/wtfsiw/profiles/<name>.yaml)
+// to load over the base config. Call it before Init.
+func SetProfile(name string) error {
+	name = strings.TrimSpace(name)
+	if strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return fmt.Errorf("invalid profile name %q", name)
+	}
+	profile = name
+	return nil
+}
+
+// ActiveProfile returns the selected profile's name, or "" for none
+func ActiveProfile() string {
+	return profile
+}
+
 func Init() error {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -63,12 +137,37 @@ func Init() error {
 	viper.SetDefault("preferences.region", "US")
 	viper.SetDefault("preferences.language", "en")
 	viper.SetDefault("preferences.min_rating", 0.0)
-	viper.SetDefault("preferences.max_results", 10)
+	viper.SetDefault("preferences.theme", "mocha")
+	viper.SetDefault("preferences.kids_mode", false)
+	viper.SetDefault("preferences.include_adult", false)
+	viper.SetDefault("preferences.collapse_duplicates", true)
+	viper.SetDefault("preferences.auto_title", true)
+	viper.SetDefault("preferences.ai_fallback", true)
+	viper.SetDefault("preferences.ai_count", defaultAICount)
+	viper.SetDefault("preferences.search_count", defaultSearchCount)
+	viper.SetDefault("preferences.cache_responses", true)
+	viper.SetDefault("preferences.foreign_audio", "")
+	viper.SetDefault("preferences.library_path", "")
+	viper.SetDefault("preferences.providers", []string{})
+	viper.SetDefault("preferences.min_popularity", 0.0)
+	viper.SetDefault("preferences.exclude_genres", []string{})
+	viper.SetDefault("preferences.blocked_titles", []string{})
+	viper.SetDefault("preferences.primary_provider", "")
+	viper.SetDefault("preferences.title_suggestions", false)
+	viper.SetDefault("preferences.show_search_filters", true)
+	viper.SetDefault("preferences.chat_max_messages", 60)
+	viper.SetDefault("preferences.never_recommend", []string{})
+	viper.SetDefault("preferences.diverse", false)
+	viper.SetDefault("preferences.max_per_collection", 1)
+	viper.SetDefault("preferences.max_per_director", 2)
+	viper.SetDefault("preferences.max_per_decade", 0)
+	viper.SetDefault("preferences.binge_time", false)
 
 	// Bind environment variables
 	viper.BindEnv("ai.claude_api_key", "ANTHROPIC_API_KEY")
 	viper.BindEnv("ai.openai_api_key", "OPENAI_API_KEY")
 	viper.BindEnv("tmdb.api_key", "TMDB_API_KEY")
+	viper.BindEnv("omdb.api_key", "OMDB_API_KEY")
 	viper.BindEnv("trakt.client_id", "TRAKT_CLIENT_ID")
 	viper.BindEnv("trakt.client_secret", "TRAKT_CLIENT_SECRET")
 	viper.BindEnv("trakt.access_token", "TRAKT_ACCESS_TOKEN")
@@ -80,11 +179,34 @@ func Init() error {
 		}
 	}
 
+	// The active profile's settings override the base config's
+	var profileErr error
+	if profile != "" {
+		profileErr = mergeProfile()
+	}
+
 	cfg = &Config{}
 	if err := viper.Unmarshal(cfg); err != nil {
 		return fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	return profileErr
+}
+
+// mergeProfile reads the active profile over the base config. A profile
+// without a file yet is reported, but the base config still loads.
+func mergeProfile() error {
+	path := GetProfilePath(profile)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("profile %q has no settings yet, using the base config (set some with: wtfsiw --profile %s config set <key> <value>)", profile, profile)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read profile %q: %w", profile, err)
+	}
+	if err := viper.MergeConfig(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("failed to read profile %q: %w", profile, err)
+	}
 	return nil
 }
 
@@ -104,13 +226,78 @@ func Save() error {
 	return viper.WriteConfigAs(GetConfigPath())
 }
 
+// Set saves a setting to the active profile, or to the base config when no
+// profile is selected
 func Set(key, value string) error {
+	if profile != "" {
+		return setInProfile(key, value)
+	}
 	viper.Set(key, value)
 	return Save()
 }
 
+// setInProfile writes a setting to the active profile's file only, so the
+// base config's settings aren't copied into it (or the profile's into the base)
+func setInProfile(key, value string) error {
+	path := GetProfilePath(profile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create profiles directory: %w", err)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if _, err := os.Stat(path); err == nil {
+		if err := v.ReadInConfig(); err != nil {
+			return fmt.Errorf("failed to read profile %q: %w", profile, err)
+		}
+	}
+	v.Set(key, value)
+	if err := v.WriteConfigAs(path); err != nil {
+		return err
+	}
+
+	viper.Set(key, value)
+	return nil
+}
+
+// GetProfilesDir returns the path to the named profiles directory
+func GetProfilesDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "wtfsiw", "profiles")
+}
+
+// GetProfilePath returns the path to a named profile's config file
+func GetProfilePath(name string) string {
+	return filepath.Join(GetProfilesDir(), name+".yaml")
+}
+
 // GetSessionsDir returns the path to the sessions directory
 func GetSessionsDir() string {
 	home, _ := os.UserHomeDir()
 	return filepath.Join(home, ".config", "wtfsiw", "sessions")
 }
+
+// GetCacheDir returns the path to the cache directory
+func GetCacheDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "wtfsiw", "cache")
+}
+
+// GetAvailabilityDir returns the path to the per-title streaming availability
+// history. It's outside the cache directory so clearing the cache keeps it.
+func GetAvailabilityDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "wtfsiw", "availability")
+}
+
+// GetPicksPath returns the path to the local "my picks" list
+func GetPicksPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "wtfsiw", "picks.json")
+}
+
+// GetWatchForPath returns the path to the watch-for state file
+func GetWatchForPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "wtfsiw", "watch_for.json")
+}
@@ -0,0 +1,228 @@
+package library
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"wtfsiw/internal/cache"
+	"wtfsiw/internal/config"
+)
+
+// scanCacheTTL is how long a library scan is reused before the directory is walked again
+const scanCacheTTL = 6 * time.Hour
+
+// videoExtensions are the file types counted as library items
+var videoExtensions = map[string]bool{
+	".mkv": true, ".mp4": true, ".m4v": true, ".avi": true,
+	".mov": true, ".wmv": true, ".webm": true, ".ts": true, ".mpg": true,
+}
+
+var (
+	// yearPattern finds a release year: "Heat (1995)", "Heat.1995.1080p".
+	// The character after the year is checked separately (see parseName) so
+	// adjacent years like "2049.2017" both match.
+	yearPattern = regexp.MustCompile(`[\(\[\s._-]((?:19|20)\d{2})`)
+	// episodePattern finds a TV episode marker: "S01E02", "1x02"
+	episodePattern = regexp.MustCompile(`(?i)[\s._-](?:s\d{1,2}e\d{1,3}|\d{1,2}x\d{2})`)
+	// seasonDirPattern matches season folders: "Season 1", "S01", "Specials"
+	seasonDirPattern = regexp.MustCompile(`(?i)^(?:season\s*\d+|s\d{1,2}|specials)$`)
+)
+
+// Item is a title found in the library
+type Item struct {
+	Title string `json:"title"`
+	Year  int    `json:"year,omitempty"` // 0 when the name has no year
+}
+
+// Library is the set of titles found under the configured library path
+type Library struct {
+	byTitle map[string][]int // normalized title -> years (0 = unknown)
+}
+
+var (
+	defaultLibrary *Library
+	loadOnce       sync.Once
+)
+
+// Get returns the library at preferences.library_path, scanning it (or loading
+// a recent scan from cache) on first use. Returns nil when no path is set or it
+// can't be read; a nil *Library owns nothing.
+func Get() *Library {
+	loadOnce.Do(func() {
+		path := config.Get().Preferences.LibraryPath
+		if path == "" {
+			return
+		}
+		if strings.HasPrefix(path, "~/") {
+			home, _ := os.UserHomeDir()
+			path = filepath.Join(home, path[2:])
+		}
+
+		store := cache.New(filepath.Join(config.GetCacheDir(), "library"), scanCacheTTL)
+		var items []Item
+		if !store.Get(path, &items) {
+			var err error
+			items, err = Scan(path)
+			if err != nil {
+				return
+			}
+			store.Set(path, items)
+		}
+		defaultLibrary = New(items)
+	})
+	return defaultLibrary
+}
+
+// New builds a library from scanned items
+func New(items []Item) *Library {
+	l := &Library{byTitle: make(map[string][]int)}
+	for _, item := range items {
+		key := normalizeTitle(item.Title)
+		if key != "" {
+			l.byTitle[key] = append(l.byTitle[key], item.Year)
+		}
+	}
+	return l
+}
+
+// Has reports whether a title is in the library. year may be "2019" or a TV
+// range like "2008-2013"; years within one of each other match, since file
+// names often use a different release date than TMDb. Titles without a year on
+// either side match by title alone.
+func (l *Library) Has(title, year string) bool {
+	if l == nil {
+		return false
+	}
+	years, ok := l.byTitle[normalizeTitle(title)]
+	if !ok {
+		return false
+	}
+
+	want := 0
+	if len(year) >= 4 {
+		want, _ = strconv.Atoi(year[:4])
+	}
+	for _, y := range years {
+		if y == 0 || want == 0 || abs(y-want) <= 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// Scan walks root and returns the titles found in video file names, using
+// Plex/Jellyfin-style folders ("Movies/Heat (1995)/Heat (1995).mkv",
+// "TV/Breaking Bad (2008)/Season 01/...") or bare release names
+// ("Heat.1995.1080p.BluRay.mkv", "Breaking.Bad.S01E01.mkv")
+func Scan(root string) ([]Item, error) {
+	if _, err := os.Stat(root); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[Item]bool)
+	var items []Item
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // skip unreadable entries
+		}
+		if d.IsDir() {
+			if strings.HasPrefix(d.Name(), ".") && path != root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !videoExtensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+
+		item, ok := parsePath(path)
+		if ok && !seen[item] {
+			seen[item] = true
+			items = append(items, item)
+		}
+		return nil
+	})
+	return items, err
+}
+
+// parsePath extracts a title and year from a video file's path
+func parsePath(path string) (Item, bool) {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	dir := filepath.Base(filepath.Dir(path))
+
+	// Episodes: the show is named by the file prefix or the folder above the season folder
+	if loc := episodePattern.FindStringIndex(name); loc != nil {
+		if seasonDirPattern.MatchString(dir) {
+			return parseName(filepath.Base(filepath.Dir(filepath.Dir(path))))
+		}
+		if item, ok := parseName(name[:loc[0]]); ok {
+			return item, true
+		}
+		return parseName(dir)
+	}
+
+	// Movies: prefer the file name, fall back to the folder for names like "movie.mkv"
+	if item, ok := parseName(name); ok && item.Year != 0 {
+		return item, true
+	}
+	if folder, ok := parseName(dir); ok && folder.Year != 0 {
+		return folder, true
+	}
+	return parseName(name)
+}
+
+// parseName splits a release-style name into a title and (optional) year
+func parseName(name string) (Item, bool) {
+	var item Item
+	// The last year wins, so titles containing one ("Blade Runner 2049 (2017)") keep it
+	yearAt := -1
+	for _, loc := range yearPattern.FindAllStringSubmatchIndex(name, -1) {
+		if loc[0] > 0 && (loc[3] == len(name) || strings.ContainsRune(")] ._-", rune(name[loc[3]]))) {
+			yearAt = loc[0]
+			item.Year, _ = strconv.Atoi(name[loc[2]:loc[3]])
+		}
+	}
+	if yearAt > 0 {
+		name = name[:yearAt]
+	}
+
+	// Dots and underscores stand in for spaces in release names
+	name = strings.NewReplacer(".", " ", "_", " ").Replace(name)
+	// Drop bracketed tags like "[1080p]" or "(Extended)"
+	if i := strings.IndexAny(name, "[("); i > 0 {
+		name = name[:i]
+	}
+	item.Title = strings.TrimSpace(strings.Trim(name, " -"))
+	return item, item.Title != ""
+}
+
+// normalizeTitle lowercases a title and drops punctuation, articles, and
+// spacing so "The Lord of the Rings: The Two Towers" matches
+// "Lord.of.the.Rings.The.Two.Towers"
+func normalizeTitle(title string) string {
+	title = strings.ToLower(strings.ReplaceAll(title, "&", " and "))
+	var words []string
+	for _, w := range strings.FieldsFunc(title, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if w == "the" || w == "a" || w == "an" {
+			continue
+		}
+		words = append(words, w)
+	}
+	return strings.Join(words, "")
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,25 @@
+package netutil
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+// OpenBrowser opens url in the default browser (or the app registered for it)
+// without waiting for it to exit
+func OpenBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait() // reap the process once it exits
+	return nil
+}
@@ -0,0 +1,33 @@
+package netutil
+
+import (
+	"errors"
+	"net"
+	"syscall"
+)
+
+// ErrOffline replaces low-level network errors when there's no connection
+var ErrOffline = errors.New("you appear to be offline, check your connection and try again")
+
+// IsOffline reports whether err looks like a lost connection: a failed DNS
+// lookup or an unreachable or downed network. Other dial failures, such as a
+// refused connection or a timeout, mean the service itself is having trouble.
+func IsOffline(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	return errors.Is(err, syscall.ENETUNREACH) || errors.Is(err, syscall.EHOSTUNREACH) || errors.Is(err, syscall.ENETDOWN)
+}
+
+// Friendly returns ErrOffline for offline errors and err unchanged otherwise
+func Friendly(err error) error {
+	if IsOffline(err) {
+		return ErrOffline
+	}
+	return err
+}
@@ -0,0 +1,95 @@
+package netutil
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// minReportedWait is the shortest wait passed to a RateLimiter's wait hook.
+// Shorter pauses aren't noticeable, so reporting them would only flicker.
+const minReportedWait = 250 * time.Millisecond
+
+// RateLimiter spaces out requests to an API that every caller shares (a token
+// bucket), and holds everyone back after the server says to slow down
+type RateLimiter struct {
+	mu          sync.Mutex
+	rate        float64 // requests allowed per second
+	burst       float64 // requests allowed at once after a quiet spell
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time // set by Pause, e.g. after an HTTP 429
+	onWait      func(time.Duration)
+}
+
+// NewRateLimiter allows perSecond requests per second, in bursts of up to burst
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		rate:   perSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// OnWait sets a function called (from the waiting goroutine) whenever a
+// request is held back long enough to notice, e.g. to show a status message
+func (l *RateLimiter) OnWait(fn func(wait time.Duration)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onWait = fn
+}
+
+// Wait blocks until the next request may be sent. A nil limiter never waits.
+func (l *RateLimiter) Wait() {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	var wait time.Duration
+	if l.tokens < 1 {
+		wait = time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	}
+	if paused := l.pausedUntil.Sub(now); paused > wait {
+		wait = paused
+	}
+	l.tokens-- // reserve this request's slot; later callers queue behind it
+	onWait := l.onWait
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return
+	}
+	if onWait != nil && wait >= minReportedWait {
+		onWait(wait)
+	}
+	time.Sleep(wait)
+}
+
+// Pause holds back every caller for d, e.g. for a server's Retry-After
+func (l *RateLimiter) Pause(d time.Duration) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := time.Now().Add(d); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// RetryAfter returns how long an HTTP 429 response asks to wait, or fallback
+// when it doesn't say (only the delay-seconds form of Retry-After is used)
+func RetryAfter(resp *http.Response, fallback time.Duration) time.Duration {
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return fallback
+}
@@ -0,0 +1,176 @@
+package omdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"wtfsiw/internal/cache"
+	"wtfsiw/internal/config"
+)
+
+const baseURL = "https://www.omdbapi.com/"
+
+// ratingsCacheTTL is how long a title's ratings are reused. Scores on
+// established titles barely move, and the free OMDb tier allows 1,000 requests a day.
+const ratingsCacheTTL = 7 * 24 * time.Hour
+
+// missCacheTTL is how long OMDb's "not found" and other error replies are
+// reused. Shorter than ratingsCacheTTL, since OMDb does add titles, but long
+// enough that titles it doesn't know don't eat into the daily request quota.
+const missCacheTTL = 24 * time.Hour
+
+// Client handles OMDb API requests
+type Client struct {
+	apiKey     string
+	httpClient *http.Client
+	ratings    *cache.Store
+	misses     *cache.Store // error messages for queries OMDb has no title for
+}
+
+// Ratings are a title's scores from the sources OMDb aggregates. Each is ""
+// when OMDb has no score from that source.
+type Ratings struct {
+	IMDb           string `json:"imdb"`            // "8.1" (out of 10)
+	RottenTomatoes string `json:"rotten_tomatoes"` // "94%"
+	Metacritic     string `json:"metacritic"`      // "76" (out of 100)
+}
+
+// titleResponse is the subset of an OMDb title lookup used here
+type titleResponse struct {
+	Response   string `json:"Response"` // "True" or "False"
+	Error      string `json:"Error"`
+	IMDbRating string `json:"imdbRating"`
+	Metascore  string `json:"Metascore"`
+	Ratings    []struct {
+		Source string `json:"Source"`
+		Value  string `json:"Value"`
+	} `json:"Ratings"`
+}
+
+// NewClient creates a new OMDb API client
+func NewClient() (*Client, error) {
+	cfg := config.Get()
+	if cfg.OMDB.APIKey == "" {
+		return nil, fmt.Errorf("OMDb API key not configured. Set OMDB_API_KEY or run: wtfsiw config set omdb.api_key YOUR_KEY")
+	}
+
+	return &Client{
+		apiKey: cfg.OMDB.APIKey,
+		httpClient: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+		ratings: cache.New(filepath.Join(config.GetCacheDir(), "omdb"), ratingsCacheTTL),
+		misses:  cache.New(filepath.Join(config.GetCacheDir(), "omdb-misses"), missCacheTTL),
+	}, nil
+}
+
+// GetRatings looks up a title's ratings by title and year. mediaType is
+// "movie" or "tv"; year may be a TV range like "2008-2013".
+func (c *Client) GetRatings(title, year, mediaType string) (*Ratings, error) {
+	params := url.Values{}
+	params.Set("t", title)
+	if len(year) >= 4 {
+		params.Set("y", year[:4])
+	}
+	switch mediaType {
+	case "movie":
+		params.Set("type", "movie")
+	case "tv":
+		params.Set("type", "series")
+	}
+	return c.lookup(params)
+}
+
+// GetRatingsByIMDbID looks up a title's ratings by its IMDb ID ("tt0113277")
+func (c *Client) GetRatingsByIMDbID(imdbID string) (*Ratings, error) {
+	params := url.Values{}
+	params.Set("i", imdbID)
+	return c.lookup(params)
+}
+
+// lookup fetches (or loads from cache) the ratings for an OMDb title query
+func (c *Client) lookup(params url.Values) (*Ratings, error) {
+	key := params.Encode()
+	var cached Ratings
+	if c.ratings.Get(key, &cached) {
+		return &cached, nil
+	}
+	var miss string
+	if c.misses.Get(key, &miss) {
+		return nil, fmt.Errorf("OMDb: %s", miss)
+	}
+
+	query := url.Values{}
+	for k, v := range params {
+		query[k] = v
+	}
+	query.Set("apikey", c.apiKey)
+
+	resp, err := c.httpClient.Get(baseURL + "?" + query.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OMDb API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var title titleResponse
+	if err := json.Unmarshal(body, &title); err != nil {
+		return nil, fmt.Errorf("failed to parse OMDb response: %w", err)
+	}
+	if title.Response != "True" {
+		c.misses.Set(key, title.Error)
+		return nil, fmt.Errorf("OMDb: %s", title.Error)
+	}
+
+	ratings := Ratings{
+		IMDb:       available(title.IMDbRating),
+		Metacritic: available(title.Metascore),
+	}
+	for _, r := range title.Ratings {
+		if r.Source == "Rotten Tomatoes" {
+			ratings.RottenTomatoes = available(r.Value)
+		}
+	}
+	c.ratings.Set(key, ratings)
+	return &ratings, nil
+}
+
+// Summary formats the available ratings for display: ["IMDb 8.1", "RT 94%", "MC 76"]
+func (r *Ratings) Summary() []string {
+	if r == nil {
+		return nil
+	}
+	var parts []string
+	if r.IMDb != "" {
+		parts = append(parts, "IMDb "+r.IMDb)
+	}
+	if r.RottenTomatoes != "" {
+		parts = append(parts, "RT "+r.RottenTomatoes)
+	}
+	if r.Metacritic != "" {
+		parts = append(parts, "MC "+r.Metacritic)
+	}
+	return parts
+}
+
+// available returns "" for OMDb's "N/A" placeholder
+func available(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "N/A" {
+		return ""
+	}
+	return value
+}
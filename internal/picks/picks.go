@@ -0,0 +1,118 @@
+package picks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"wtfsiw/internal/config"
+)
+
+// Entry is a title saved to the local picks list
+type Entry struct {
+	TMDBID    int       `json:"tmdb_id,omitempty"` // 0 for AI suggestions not matched on TMDb
+	MediaType string    `json:"media_type,omitempty"`
+	Title     string    `json:"title"`
+	Year      string    `json:"year,omitempty"`
+	AddedAt   time.Time `json:"added_at"`
+}
+
+// List is the persisted "my picks" list, a lightweight watchlist that
+// doesn't need Trakt
+type List struct {
+	Entries []Entry `json:"entries"`
+}
+
+// Load reads the picks list from disk (an empty list if none exists yet)
+func Load() (*List, error) {
+	data, err := os.ReadFile(config.GetPicksPath())
+	if os.IsNotExist(err) {
+		return &List{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read picks file: %w", err)
+	}
+
+	var l List
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("failed to parse picks file: %w", err)
+	}
+	return &l, nil
+}
+
+// Save writes the picks list to disk
+func (l *List) Save() error {
+	path := config.GetPicksPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal picks: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write picks file: %w", err)
+	}
+	return nil
+}
+
+// Add records a new entry. Returns false if the title is already a pick.
+func (l *List) Add(e Entry) bool {
+	for _, existing := range l.Entries {
+		if existing.same(e) {
+			return false
+		}
+	}
+	l.Entries = append(l.Entries, e)
+	return true
+}
+
+// same reports whether two entries are the same title: by TMDb ID when both
+// have one, otherwise by title and year
+func (e Entry) same(other Entry) bool {
+	if e.TMDBID != 0 && other.TMDBID != 0 {
+		return e.TMDBID == other.TMDBID && e.MediaType == other.MediaType
+	}
+	return strings.EqualFold(e.Title, other.Title) && e.Year == other.Year
+}
+
+// Remove deletes the first entry whose title matches (case-insensitive), or
+// else the entry with that number in the list (as shown by 'picks list').
+// Titles come first so a numeric title like "1917" can still be removed by name.
+func (l *List) Remove(titleOrNumber string) (Entry, bool) {
+	titleOrNumber = strings.TrimSpace(titleOrNumber)
+	for i, e := range l.Entries {
+		if strings.EqualFold(e.Title, titleOrNumber) {
+			l.Entries = append(l.Entries[:i], l.Entries[i+1:]...)
+			return e, true
+		}
+	}
+	if n, err := strconv.Atoi(titleOrNumber); err == nil && n >= 1 && n <= len(l.Entries) {
+		e := l.Entries[n-1]
+		l.Entries = append(l.Entries[:n-1], l.Entries[n:]...)
+		return e, true
+	}
+	return Entry{}, false
+}
+
+// Pick adds a title to the picks list on disk, for the TUIs' "add to my
+// picks" key. added is false if it was already there.
+func Pick(e Entry) (added bool, err error) {
+	l, err := Load()
+	if err != nil {
+		return false, err
+	}
+	if e.AddedAt.IsZero() {
+		e.AddedAt = time.Now()
+	}
+	if !l.Add(e) {
+		return false, nil
+	}
+	return true, l.Save()
+}
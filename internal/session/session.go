@@ -8,11 +8,13 @@ import (
 	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 
 	"wtfsiw/internal/ai"
 	"wtfsiw/internal/config"
+	"wtfsiw/internal/textutil"
 )
 
 // Session represents a chat session
@@ -22,8 +24,24 @@ type Session struct {
 	UpdatedAt time.Time        `json:"updated_at"`
 	Title     string           `json:"title,omitempty"` // Auto-generated from first message
 	Messages  []ai.ChatMessage `json:"messages"`
+
+	// TitleGenerated is set once the model has been asked for a title, so it's only asked once
+	TitleGenerated bool `json:"title_generated,omitempty"`
+
+	// Summary stands in for Messages[:SummarizedThrough] when the conversation
+	// is sent to the provider (see APIMessages). Messages always keeps the full
+	// history.
+	Summary           string `json:"summary,omitempty"`
+	SummarizedThrough int    `json:"summarized_through,omitempty"`
+
+	// undos counts UndoLastUserMessage calls, so a summary started before an
+	// undo can be told apart from one of the messages sent since (see Compact)
+	undos int
 }
 
+// titleAfterExchanges is how many user messages a session needs before the model names it
+const titleAfterExchanges = 2
+
 // New creates a new empty session
 func New() *Session {
 	return &Session{
@@ -46,6 +64,116 @@ func (s *Session) AddMessage(msg ai.ChatMessage) {
 	}
 }
 
+// NeedsTitle reports whether the session is ready for a model-generated title:
+// it hasn't had one yet and has at least titleAfterExchanges user messages
+func (s *Session) NeedsTitle() bool {
+	if s.TitleGenerated {
+		return false
+	}
+	userMessages := 0
+	for _, msg := range s.Messages {
+		if msg.Role == "user" {
+			userMessages++
+		}
+	}
+	return userMessages >= titleAfterExchanges
+}
+
+// SetGeneratedTitle replaces the truncated first-message title with one from the model
+func (s *Session) SetGeneratedTitle(title string) {
+	s.Title = truncateTitle(title, 50)
+	s.TitleGenerated = true
+}
+
+// RewindToLastUserMessage drops everything after the most recent user message
+// (assistant replies, tool calls and tool results) so the conversation can be
+// re-sent. Returns false if there is no user message to rewind to.
+func (s *Session) RewindToLastUserMessage() bool {
+	for i := len(s.Messages) - 1; i >= 0; i-- {
+		if s.Messages[i].Role == "user" {
+			s.Messages = s.Messages[:i+1]
+			s.UpdatedAt = time.Now()
+			return true
+		}
+	}
+	return false
+}
+
+// UndoLastUserMessage drops the most recent user message and everything
+// after it, leaving the conversation as it was before that message was sent.
+// A summary covering any of the dropped messages is discarded too (Messages
+// still has the full history). Returns false if there is no user message.
+func (s *Session) UndoLastUserMessage() bool {
+	for i := len(s.Messages) - 1; i >= 0; i-- {
+		if s.Messages[i].Role == "user" {
+			s.Messages = s.Messages[:i]
+			s.undos++
+			if s.SummarizedThrough > i {
+				s.Summary = ""
+				s.SummarizedThrough = 0
+			}
+			if len(s.Messages) == 0 {
+				s.Title = "" // retitled from the next first message
+				s.TitleGenerated = false
+			}
+			s.UpdatedAt = time.Now()
+			return true
+		}
+	}
+	return false
+}
+
+// APIMessages returns the conversation to send to the provider: the messages
+// after SummarizedThrough, with the summary of the earlier ones prepended to
+// the first of them (always a user message). Without a summary it's Messages.
+func (s *Session) APIMessages() []ai.ChatMessage {
+	if s.Summary == "" || s.SummarizedThrough <= 0 || s.SummarizedThrough >= len(s.Messages) {
+		return s.Messages
+	}
+	recent := append([]ai.ChatMessage(nil), s.Messages[s.SummarizedThrough:]...)
+	recent[0].Content = fmt.Sprintf("[Summary of our conversation so far: %s]\n\n%s", s.Summary, recent[0].Content)
+	return recent
+}
+
+// CompactionPoint returns where to cut the conversation so that what's sent to
+// the provider fits in maxMessages: the index of the user message starting
+// the most recent turns that fill about half of it. Everything before that
+// should be summarized. ok is false when the conversation is short enough,
+// or there's no earlier turn boundary to cut at.
+func (s *Session) CompactionPoint(maxMessages int) (cut int, ok bool) {
+	if maxMessages <= 0 || len(s.Messages)-s.SummarizedThrough <= maxMessages {
+		return 0, false
+	}
+	keep := maxMessages / 2
+	for i := s.SummarizedThrough + 1; i < len(s.Messages); i++ {
+		if s.Messages[i].Role == "user" {
+			cut = i
+			if len(s.Messages)-i <= keep {
+				break
+			}
+		}
+	}
+	return cut, cut > s.SummarizedThrough
+}
+
+// Undos returns a count that changes whenever messages are undone. Pass it
+// to Compact with the summary of messages read at that point.
+func (s *Session) Undos() int {
+	return s.undos
+}
+
+// Compact records summary as covering Messages[:through]. undos is Undos()
+// from when the summarized messages were read: if messages were undone since,
+// they may have been replaced, so the summary is dropped.
+func (s *Session) Compact(summary string, through, undos int) {
+	if undos != s.undos || through <= s.SummarizedThrough || through >= len(s.Messages) {
+		return // stale: the session moved on or was rewound
+	}
+	s.Summary = summary
+	s.SummarizedThrough = through
+	s.UpdatedAt = time.Now()
+}
+
 // Save persists the session to disk
 func (s *Session) Save() error {
 	sessionsDir := config.GetSessionsDir()
@@ -194,13 +322,13 @@ func truncateTitle(s string, maxLen int) string {
 	s = strings.ReplaceAll(s, "\n", " ")
 	s = strings.ReplaceAll(s, "\r", " ")
 
-	if len(s) <= maxLen {
+	if utf8.RuneCountInString(s) <= maxLen {
 		return s
 	}
 
 	// Find last space before maxLen
-	s = s[:maxLen]
-	if idx := strings.LastIndex(s, " "); idx > maxLen/2 {
+	s = textutil.Head(s, maxLen)
+	if idx := strings.LastIndex(s, " "); idx > len(s)/2 {
 		s = s[:idx]
 	}
 	return s + "..."
@@ -0,0 +1,24 @@
+package textutil
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ContainsPhrase reports whether phrase appears in s as whole words, ignoring
+// case and punctuation: "fast furious" matches "Fast & Furious 6" but "up"
+// doesn't match "Upgrade"
+func ContainsPhrase(s, phrase string) bool {
+	p := words(phrase)
+	if p == "" {
+		return false
+	}
+	return strings.Contains(" "+words(s)+" ", " "+p+" ")
+}
+
+// words lowercases s and reduces it to its letters and digits, one space between words
+func words(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
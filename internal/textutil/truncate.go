@@ -0,0 +1,31 @@
+package textutil
+
+import "unicode/utf8"
+
+// Truncate shortens s to at most max runes, replacing the tail with "..." when cut.
+// Counting runes rather than bytes keeps non-ASCII titles (Korean, Japanese,
+// accented text) from being split mid-character.
+func Truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	if max <= 3 {
+		return Head(s, max)
+	}
+	return Head(s, max-3) + "..."
+}
+
+// Head returns the first n runes of s
+func Head(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	i := 0
+	for pos := range s {
+		if i == n {
+			return s[:pos]
+		}
+		i++
+	}
+	return s
+}
@@ -0,0 +1,205 @@
+package theme
+
+import (
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Palette holds the colors used by the TUI and CLI styles.
+// Slot names follow Catppuccin; other themes map their colors onto the same roles.
+type Palette struct {
+	// Accent colors
+	Rosewater lipgloss.TerminalColor
+	Flamingo  lipgloss.TerminalColor
+	Pink      lipgloss.TerminalColor
+	Mauve     lipgloss.TerminalColor
+	Red       lipgloss.TerminalColor
+	Maroon    lipgloss.TerminalColor
+	Peach     lipgloss.TerminalColor
+	Yellow    lipgloss.TerminalColor
+	Green     lipgloss.TerminalColor
+	Teal      lipgloss.TerminalColor
+	Sky       lipgloss.TerminalColor
+	Sapphire  lipgloss.TerminalColor
+	Blue      lipgloss.TerminalColor
+	Lavender  lipgloss.TerminalColor
+
+	// Text colors
+	Text     lipgloss.TerminalColor
+	Subtext1 lipgloss.TerminalColor
+	Subtext0 lipgloss.TerminalColor
+
+	// Overlay colors
+	Overlay2 lipgloss.TerminalColor
+	Overlay1 lipgloss.TerminalColor
+	Overlay0 lipgloss.TerminalColor
+
+	// Surface colors
+	Surface2 lipgloss.TerminalColor
+	Surface1 lipgloss.TerminalColor
+	Surface0 lipgloss.TerminalColor
+
+	// Base colors
+	Base   lipgloss.TerminalColor
+	Mantle lipgloss.TerminalColor
+	Crust  lipgloss.TerminalColor
+}
+
+// Mocha is the Catppuccin Mocha palette (dark, default)
+var Mocha = Palette{
+	Rosewater: lipgloss.Color("#f5e0dc"),
+	Flamingo:  lipgloss.Color("#f2cdcd"),
+	Pink:      lipgloss.Color("#f5c2e7"),
+	Mauve:     lipgloss.Color("#cba6f7"),
+	Red:       lipgloss.Color("#f38ba8"),
+	Maroon:    lipgloss.Color("#eba0ac"),
+	Peach:     lipgloss.Color("#fab387"),
+	Yellow:    lipgloss.Color("#f9e2af"),
+	Green:     lipgloss.Color("#a6e3a1"),
+	Teal:      lipgloss.Color("#94e2d5"),
+	Sky:       lipgloss.Color("#89dceb"),
+	Sapphire:  lipgloss.Color("#74c7ec"),
+	Blue:      lipgloss.Color("#89b4fa"),
+	Lavender:  lipgloss.Color("#b4befe"),
+
+	Text:     lipgloss.Color("#cdd6f4"),
+	Subtext1: lipgloss.Color("#bac2de"),
+	Subtext0: lipgloss.Color("#a6adc8"),
+
+	Overlay2: lipgloss.Color("#9399b2"),
+	Overlay1: lipgloss.Color("#7f849c"),
+	Overlay0: lipgloss.Color("#6c7086"),
+
+	Surface2: lipgloss.Color("#585b70"),
+	Surface1: lipgloss.Color("#45475a"),
+	Surface0: lipgloss.Color("#313244"),
+
+	Base:   lipgloss.Color("#1e1e2e"),
+	Mantle: lipgloss.Color("#181825"),
+	Crust:  lipgloss.Color("#11111b"),
+}
+
+// Latte is the Catppuccin Latte palette (light terminals)
+var Latte = Palette{
+	Rosewater: lipgloss.Color("#dc8a78"),
+	Flamingo:  lipgloss.Color("#dd7878"),
+	Pink:      lipgloss.Color("#ea76cb"),
+	Mauve:     lipgloss.Color("#8839ef"),
+	Red:       lipgloss.Color("#d20f39"),
+	Maroon:    lipgloss.Color("#e64553"),
+	Peach:     lipgloss.Color("#fe640b"),
+	Yellow:    lipgloss.Color("#df8e1d"),
+	Green:     lipgloss.Color("#40a02b"),
+	Teal:      lipgloss.Color("#179299"),
+	Sky:       lipgloss.Color("#04a5e5"),
+	Sapphire:  lipgloss.Color("#209fb5"),
+	Blue:      lipgloss.Color("#1e66f5"),
+	Lavender:  lipgloss.Color("#7287fd"),
+
+	Text:     lipgloss.Color("#4c4f69"),
+	Subtext1: lipgloss.Color("#5c5f77"),
+	Subtext0: lipgloss.Color("#6c6f85"),
+
+	Overlay2: lipgloss.Color("#7c7f93"),
+	Overlay1: lipgloss.Color("#8c8fa1"),
+	Overlay0: lipgloss.Color("#9ca0b0"),
+
+	Surface2: lipgloss.Color("#acb0be"),
+	Surface1: lipgloss.Color("#bcc0cc"),
+	Surface0: lipgloss.Color("#ccd0da"),
+
+	Base:   lipgloss.Color("#eff1f5"),
+	Mantle: lipgloss.Color("#e6e9ef"),
+	Crust:  lipgloss.Color("#dce0e8"),
+}
+
+// Dracula maps the Dracula palette onto the Catppuccin roles
+var Dracula = Palette{
+	Rosewater: lipgloss.Color("#ffb86c"),
+	Flamingo:  lipgloss.Color("#ff92df"),
+	Pink:      lipgloss.Color("#ff79c6"),
+	Mauve:     lipgloss.Color("#bd93f9"),
+	Red:       lipgloss.Color("#ff5555"),
+	Maroon:    lipgloss.Color("#ff6e6e"),
+	Peach:     lipgloss.Color("#ffb86c"),
+	Yellow:    lipgloss.Color("#f1fa8c"),
+	Green:     lipgloss.Color("#50fa7b"),
+	Teal:      lipgloss.Color("#8be9fd"),
+	Sky:       lipgloss.Color("#8be9fd"),
+	Sapphire:  lipgloss.Color("#a4ffff"),
+	Blue:      lipgloss.Color("#6272a4"),
+	Lavender:  lipgloss.Color("#d6acff"),
+
+	Text:     lipgloss.Color("#f8f8f2"),
+	Subtext1: lipgloss.Color("#e2e2dc"),
+	Subtext0: lipgloss.Color("#bfbfbf"),
+
+	Overlay2: lipgloss.Color("#7b88b8"),
+	Overlay1: lipgloss.Color("#6272a4"),
+	Overlay0: lipgloss.Color("#565c81"),
+
+	Surface2: lipgloss.Color("#565a74"),
+	Surface1: lipgloss.Color("#44475a"),
+	Surface0: lipgloss.Color("#383a4a"),
+
+	Base:   lipgloss.Color("#282a36"),
+	Mantle: lipgloss.Color("#21222c"),
+	Crust:  lipgloss.Color("#191a21"),
+}
+
+// None disables all colors (bold/italic/borders are kept)
+var None = Palette{
+	Rosewater: lipgloss.NoColor{},
+	Flamingo:  lipgloss.NoColor{},
+	Pink:      lipgloss.NoColor{},
+	Mauve:     lipgloss.NoColor{},
+	Red:       lipgloss.NoColor{},
+	Maroon:    lipgloss.NoColor{},
+	Peach:     lipgloss.NoColor{},
+	Yellow:    lipgloss.NoColor{},
+	Green:     lipgloss.NoColor{},
+	Teal:      lipgloss.NoColor{},
+	Sky:       lipgloss.NoColor{},
+	Sapphire:  lipgloss.NoColor{},
+	Blue:      lipgloss.NoColor{},
+	Lavender:  lipgloss.NoColor{},
+
+	Text:     lipgloss.NoColor{},
+	Subtext1: lipgloss.NoColor{},
+	Subtext0: lipgloss.NoColor{},
+
+	Overlay2: lipgloss.NoColor{},
+	Overlay1: lipgloss.NoColor{},
+	Overlay0: lipgloss.NoColor{},
+
+	Surface2: lipgloss.NoColor{},
+	Surface1: lipgloss.NoColor{},
+	Surface0: lipgloss.NoColor{},
+
+	Base:   lipgloss.NoColor{},
+	Mantle: lipgloss.NoColor{},
+	Crust:  lipgloss.NoColor{},
+}
+
+// Themes maps theme names (as used in preferences.theme) to palettes
+var Themes = map[string]Palette{
+	"mocha":    Mocha,
+	"latte":    Latte,
+	"dracula":  Dracula,
+	"none":     None,
+	"no-color": None,
+}
+
+// Get returns the palette for a theme name, falling back to Mocha.
+// The NO_COLOR environment variable (https://no-color.org) always wins.
+func Get(name string) Palette {
+	if os.Getenv("NO_COLOR") != "" {
+		return None
+	}
+	if p, ok := Themes[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return p
+	}
+	return Mocha
+}
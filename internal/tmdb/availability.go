@@ -0,0 +1,121 @@
+package tmdb
+
+import (
+	"time"
+)
+
+// availabilityHistoryTTL is how long a title's availability history is kept
+// after it last changed. Lookups that find the same providers don't rewrite
+// the history, so they don't extend it.
+const availabilityHistoryTTL = 365 * 24 * time.Hour
+
+// maxAvailabilitySnapshots caps the changes remembered per title
+const maxAvailabilitySnapshots = 10
+
+// Monetization types a Provider can have (Provider.Type)
+const (
+	MonetizationFlatrate = "flatrate" // included with a subscription
+	MonetizationFree     = "free"
+	MonetizationRent     = "rent"
+	MonetizationBuy      = "buy"
+)
+
+// AvailabilitySnapshot is how a title could be watched when it was looked up
+type AvailabilitySnapshot struct {
+	Streaming []string  `json:"streaming,omitempty"` // subscription or free providers
+	Paid      []string  `json:"paid,omitempty"`      // rent or buy only
+	Seen      time.Time `json:"seen"`
+}
+
+// availabilityHistory is a title's snapshots, one per change, oldest first
+type availabilityHistory struct {
+	Snapshots []AvailabilitySnapshot `json:"snapshots"`
+	Notified  bool                   `json:"notified,omitempty"` // the latest move to streaming was reported
+}
+
+// StreamingChange is a title that could only be rented or bought when it was
+// last looked up, and can now be streamed
+type StreamingChange struct {
+	Media     Media
+	Providers []string  // where it streams now
+	Since     time.Time // when the change was first seen
+}
+
+// snapshotOf splits providers into streaming and rent/buy-only names
+func snapshotOf(providers []Provider) AvailabilitySnapshot {
+	snap := AvailabilitySnapshot{Seen: time.Now()}
+	for _, p := range providers {
+		switch p.Type {
+		case MonetizationFlatrate, MonetizationFree:
+			snap.Streaming = append(snap.Streaming, p.Name)
+		case MonetizationRent, MonetizationBuy:
+			snap.Paid = append(snap.Paid, p.Name)
+		}
+	}
+	return snap
+}
+
+// sameAvailability reports whether two snapshots list the same providers
+func sameAvailability(a, b AvailabilitySnapshot) bool {
+	return equalStrings(a.Streaming, b.Streaming) && equalStrings(a.Paid, b.Paid)
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// recordAvailability adds a freshly fetched provider list to the title's
+// history when it differs from the last one recorded
+func (c *Client) recordAvailability(key string, providers []Provider) {
+	if c.availability == nil {
+		return
+	}
+	var history availabilityHistory
+	c.availability.Get(key, &history)
+
+	snap := snapshotOf(providers)
+	if n := len(history.Snapshots); n > 0 && sameAvailability(history.Snapshots[n-1], snap) {
+		return
+	}
+	history.Snapshots = append(history.Snapshots, snap)
+	if len(history.Snapshots) > maxAvailabilitySnapshots {
+		history.Snapshots = history.Snapshots[len(history.Snapshots)-maxAvailabilitySnapshots:]
+	}
+	history.Notified = false
+	c.availability.Set(key, history)
+}
+
+// NewlyStreaming returns the results that have moved from rent/buy only to
+// streaming since an earlier lookup, each reported once. Call it after
+// EnrichWithProviders.
+func (c *Client) NewlyStreaming(results []Media) []StreamingChange {
+	if c.availability == nil {
+		return nil
+	}
+
+	var changes []StreamingChange
+	for _, m := range results {
+		key := c.providerKey(m.MediaType, m.ID)
+		var history availabilityHistory
+		if !c.availability.Get(key, &history) || history.Notified || len(history.Snapshots) < 2 {
+			continue
+		}
+		before, now := history.Snapshots[len(history.Snapshots)-2], history.Snapshots[len(history.Snapshots)-1]
+		if len(before.Streaming) > 0 || len(before.Paid) == 0 || len(now.Streaming) == 0 {
+			continue
+		}
+
+		changes = append(changes, StreamingChange{Media: m, Providers: now.Streaming, Since: now.Seen})
+		history.Notified = true
+		c.availability.Set(key, history)
+	}
+	return changes
+}
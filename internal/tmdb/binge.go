@@ -0,0 +1,80 @@
+package tmdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// tvDetails is the subset of /tv/{id} used for binge estimates
+type tvDetails struct {
+	NumberOfEpisodes int   `json:"number_of_episodes"`
+	EpisodeRunTime   []int `json:"episode_run_time"` // one entry per typical length, often empty for newer shows
+	LastEpisode      *struct {
+		Runtime int `json:"runtime"`
+	} `json:"last_episode_to_air"`
+}
+
+// bingeMinutes is the show's episode count times its average episode length
+// (0 when either is unknown)
+func (d tvDetails) bingeMinutes() int {
+	runtime := 0
+	if len(d.EpisodeRunTime) > 0 {
+		total := 0
+		for _, r := range d.EpisodeRunTime {
+			total += r
+		}
+		runtime = total / len(d.EpisodeRunTime)
+	} else if d.LastEpisode != nil {
+		runtime = d.LastEpisode.Runtime
+	}
+	return d.NumberOfEpisodes * runtime
+}
+
+// EnrichWithBingeTime sets BingeMinutes on TV results, from one /tv/{id}
+// request each. Does nothing unless preferences.binge_time is on.
+func (c *Client) EnrichWithBingeTime(results []Media) {
+	if !c.bingeTime {
+		return
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentLookups)
+
+	for i := range results {
+		if results[i].GetMediaType() != "tv" || results[i].BingeMinutes > 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(m *Media) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			data, err := c.getCached(fmt.Sprintf("/tv/%d", m.ID), nil)
+			if err != nil {
+				return
+			}
+			var details tvDetails
+			if err := json.Unmarshal(data, &details); err == nil {
+				m.BingeMinutes = details.bingeMinutes()
+			}
+		}(&results[i])
+	}
+
+	wg.Wait()
+}
+
+// BingeLabel formats a binge time as "≈ 22 hours total" ("" for 0)
+func BingeLabel(minutes int) string {
+	switch {
+	case minutes <= 0:
+		return ""
+	case minutes < 60:
+		return fmt.Sprintf("≈ %d minutes total", minutes)
+	case minutes < 90:
+		return "≈ 1 hour total"
+	default:
+		return fmt.Sprintf("≈ %d hours total", (minutes+30)/60)
+	}
+}
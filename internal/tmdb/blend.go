@@ -0,0 +1,173 @@
+package tmdb
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// maxBlendQueries caps the genre combinations queried for one blended search
+const maxBlendQueries = 12
+
+// TasteGroup is one viewer's (or one mood's) constraints in a blended search
+type TasteGroup struct {
+	Label  string
+	Genres []string
+}
+
+// BlendMatch is a title found by a blended search, with the genres it shares with each group
+type BlendMatch struct {
+	Media   Media
+	Matches map[string][]string // group label -> matched genre names
+}
+
+// Blend finds titles that satisfy every taste group rather than any one of them.
+// It queries Discover for each combination of one genre per group (an AND
+// filter), so every result has at least one genre from each group. Results are
+// ranked by how many of the groups' genres they cover, then by rating and
+// popularity. base supplies shared filters (media type, rating, providers...).
+func (c *Client) Blend(groups []TasteGroup, base SearchParams, limit int) ([]BlendMatch, error) {
+	// Resolve genre names, dropping unknown ones
+	var usable []TasteGroup
+	for _, g := range groups {
+		var genres []string
+		for _, name := range g.Genres {
+			if _, ok := GenreMap[NormalizeName(name)]; ok {
+				genres = append(genres, NormalizeName(name))
+			}
+		}
+		if len(genres) > 0 {
+			usable = append(usable, TasteGroup{Label: g.Label, Genres: genres})
+		}
+	}
+	if len(usable) < 2 {
+		return nil, fmt.Errorf("need at least two groups with recognized genres to blend")
+	}
+
+	combos := genreCombinations(usable, maxBlendQueries)
+
+	// Run the combination queries in parallel
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentLookups)
+	seen := make(map[string]bool)
+	var candidates []Media
+
+	for _, combo := range combos {
+		wg.Add(1)
+		go func(genres []string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			params := base
+			params.Genres = genres
+			params.Keywords = nil // keyword and similar-title searches ignore genres
+			params.SimilarTo = nil
+			params.Limit = 20
+
+			resp, err := c.Discover(&params)
+			if err != nil {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, m := range resp.Results {
+				key := fmt.Sprintf("%s-%d", m.MediaType, m.ID)
+				if !seen[key] {
+					seen[key] = true
+					candidates = append(candidates, m)
+				}
+			}
+		}(combo)
+	}
+	wg.Wait()
+
+	matches := make([]BlendMatch, 0, len(candidates))
+	for _, m := range candidates {
+		matches = append(matches, BlendMatch{Media: m, Matches: matchGroups(m, usable)})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		ci, cj := matches[i].coverage(), matches[j].coverage()
+		if ci != cj {
+			return ci > cj
+		}
+		si := matches[i].Media.VoteAverage * (1 + matches[i].Media.Popularity/100)
+		sj := matches[j].Media.VoteAverage * (1 + matches[j].Media.Popularity/100)
+		return si > sj
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// coverage is the total number of group genres a match has
+func (b BlendMatch) coverage() int {
+	n := 0
+	for _, genres := range b.Matches {
+		n += len(genres)
+	}
+	return n
+}
+
+// genreCombinations returns up to max combinations of one genre per group, deduplicated
+func genreCombinations(groups []TasteGroup, max int) [][]string {
+	combos := [][]string{{}}
+	for _, g := range groups {
+		var next [][]string
+		for _, combo := range combos {
+			for _, genre := range g.Genres {
+				if len(next) >= max {
+					break
+				}
+				extended := append(append([]string{}, combo...), genre)
+				next = append(next, extended)
+			}
+		}
+		combos = next
+	}
+
+	// Groups can share a genre (e.g. both like comedy); collapse those combos
+	seen := make(map[string]bool)
+	var unique [][]string
+	for _, combo := range combos {
+		set := make(map[string]bool)
+		var genres []string
+		for _, g := range combo {
+			if !set[g] {
+				set[g] = true
+				genres = append(genres, g)
+			}
+		}
+		sort.Strings(genres)
+		key := strings.Join(genres, ",")
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, genres)
+		}
+	}
+	return unique
+}
+
+// matchGroups returns, for each group, the group's genres the title has
+func matchGroups(m Media, groups []TasteGroup) map[string][]string {
+	has := make(map[int]bool)
+	for _, id := range m.GenreIDs {
+		has[id] = true
+	}
+
+	result := make(map[string][]string)
+	for _, g := range groups {
+		for _, name := range g.Genres {
+			if has[GenreMap[name]] {
+				result[g.Label] = append(result[g.Label], name)
+			}
+		}
+	}
+	return result
+}
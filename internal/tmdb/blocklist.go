@@ -0,0 +1,70 @@
+package tmdb
+
+import (
+	"strings"
+
+	"wtfsiw/internal/textutil"
+)
+
+// applyBlockedGenres adds the user's blocked genres (preferences.exclude_genres)
+// to a search's exclusions and drops them from its requested genres
+func applyBlockedGenres(sp *SearchParams, blocked []string) {
+	genres := make([]string, 0, len(sp.Genres))
+	for _, g := range sp.Genres {
+		if !containsFold(blocked, NormalizeName(g)) {
+			genres = append(genres, g)
+		}
+	}
+	sp.Genres = genres
+	sp.ExcludeGenres = append(append([]string{}, sp.ExcludeGenres...), blocked...)
+}
+
+// isBlocked reports whether a result is in a blocked genre or matches a blocked title
+func (c *Client) isBlocked(m Media) bool {
+	for _, id := range m.GenreIDs {
+		if c.blockedGenreIDs[id] {
+			return true
+		}
+	}
+	title := m.GetDisplayTitle()
+	for _, t := range c.blockedTitles {
+		if textutil.ContainsPhrase(title, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeBlocked filters blocked genres and titles out of results
+func (c *Client) removeBlocked(results []Media) []Media {
+	if len(c.blockedGenreIDs) == 0 && len(c.blockedTitles) == 0 {
+		return results
+	}
+	kept := results[:0]
+	for _, m := range results {
+		if !c.isBlocked(m) {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+// genreIDSet resolves genre names to a set of TMDb genre IDs, ignoring unknown names
+func genreIDSet(names []string) map[int]bool {
+	ids := make(map[int]bool)
+	for _, name := range names {
+		if id, ok := GenreMap[NormalizeName(name)]; ok {
+			ids[id] = true
+		}
+	}
+	return ids
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package tmdb
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// TMDb release types, in the order their certification is preferred
+// when a country lists several releases of a movie
+var releaseTypePriority = []int{
+	3, // theatrical
+	2, // theatrical (limited)
+	4, // digital
+	5, // physical
+	6, // TV
+	1, // premiere
+}
+
+// releaseDatesResponse is /movie/{id}/release_dates
+type releaseDatesResponse struct {
+	Results []struct {
+		Country      string `json:"iso_3166_1"`
+		ReleaseDates []struct {
+			Certification string `json:"certification"`
+			Type          int    `json:"type"`
+		} `json:"release_dates"`
+	} `json:"results"`
+}
+
+// contentRatingsResponse is /tv/{id}/content_ratings
+type contentRatingsResponse struct {
+	Results []struct {
+		Country string `json:"iso_3166_1"`
+		Rating  string `json:"rating"`
+	} `json:"results"`
+}
+
+// GetCertifications returns a title's age rating in each country that has
+// one, keyed by ISO 3166-1 code: {"US": "PG-13", "GB": "12A", "DE": "12"}
+func (c *Client) GetCertifications(mediaType string, id int) (map[string]string, error) {
+	ratings := make(map[string]string)
+
+	switch mediaType {
+	case "movie":
+		data, err := c.get(fmt.Sprintf("/movie/%d/release_dates", id), nil)
+		if err != nil {
+			return nil, err
+		}
+		var resp releaseDatesResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, fmt.Errorf("failed to parse release dates response: %w", err)
+		}
+		for _, country := range resp.Results {
+			byType := make(map[int]string)
+			for _, rd := range country.ReleaseDates {
+				if rd.Certification != "" && byType[rd.Type] == "" {
+					byType[rd.Type] = rd.Certification
+				}
+			}
+			for _, t := range releaseTypePriority {
+				if cert := byType[t]; cert != "" {
+					ratings[country.Country] = cert
+					break
+				}
+			}
+		}
+
+	case "tv":
+		data, err := c.get(fmt.Sprintf("/tv/%d/content_ratings", id), nil)
+		if err != nil {
+			return nil, err
+		}
+		var resp contentRatingsResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, fmt.Errorf("failed to parse content ratings response: %w", err)
+		}
+		for _, r := range resp.Results {
+			if r.Rating != "" {
+				ratings[r.Country] = r.Rating
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("invalid media type: %s", mediaType)
+	}
+
+	return ratings, nil
+}
@@ -1,23 +1,61 @@
 package tmdb
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"wtfsiw/internal/cache"
 	"wtfsiw/internal/config"
+	"wtfsiw/internal/netutil"
 )
 
 const baseURL = "https://api.themoviedb.org/3"
 
+// responseCacheTTL is how long identical search and discover responses are reused
+const responseCacheTTL = 10 * time.Minute
+
+// TMDb allows around 50 requests a second per IP. Stay under it so a chat
+// turn fanning out into many lookups slows down instead of failing.
+const (
+	requestsPerSecond   = 40
+	requestBurst        = 20
+	maxRateLimitRetries = 2
+)
+
 type Client struct {
-	apiKey     string
-	httpClient *http.Client
-	region     string
-	language   string
+	apiKey          string
+	httpClient      *http.Client
+	region          string
+	language        string
+	kidsMode        bool
+	includeAdult    bool          // never true in kids mode
+	maxResults      int           // default number of results returned by Discover
+	myProviders     []string      // applied by Discover when a search names no providers
+	primaryProvider string        // preferences.primary_provider (see RankByProviderPreference)
+	minPopularity   float64       // applied by Discover when a search sets no popularity floor
+	blockedGenres   []string      // preferences.exclude_genres, excluded from every search
+	blockedGenreIDs map[int]bool  // the same genres as IDs, for filtering results
+	blockedTitles   []string      // preferences.blocked_titles, filtered out of every result set
+	diverse         bool          // preferences.diverse, applies diversityCaps to every search
+	diversityCaps   DiversityCaps // see diversify
+	bingeTime       bool          // preferences.binge_time, see EnrichWithBingeTime
+	providers       *cache.Store
+	availability    *cache.Store         // per-title provider history, kept across cache clears
+	responses       *cache.Store         // nil when response caching is disabled
+	images          ImageConfig          // image base URL and sizes from /configuration, see imageConfig
+	imagesOnce      sync.Once            // loads images on first use
+	limiter         *netutil.RateLimiter // shared by every request from this client
+	diagnostics     io.Writer            // where --verbose diagnostics go (nil for none), see SetDiagnostics
+	regionWarning   sync.Once            // shows the default-region diagnostic once
 }
 
 func NewClient() (*Client, error) {
@@ -26,62 +64,162 @@ func NewClient() (*Client, error) {
 		return nil, fmt.Errorf("TMDb API key not configured. Set TMDB_API_KEY or run: wtfsiw config set tmdb.api_key YOUR_KEY")
 	}
 
-	return &Client{
+	var responses *cache.Store
+	if cfg.Preferences.CacheResponses {
+		responses = cache.New(filepath.Join(config.GetCacheDir(), "responses"), responseCacheTTL)
+	}
+
+	client := &Client{
 		apiKey: cfg.TMDB.APIKey,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		region:   cfg.Preferences.Region,
-		language: cfg.Preferences.Language,
-	}, nil
+		region:          cfg.Preferences.Region,
+		language:        cfg.Preferences.Language,
+		kidsMode:        cfg.Preferences.KidsMode,
+		includeAdult:    cfg.Preferences.IncludeAdult && !cfg.Preferences.KidsMode,
+		maxResults:      cfg.Preferences.GetSearchCount(),
+		myProviders:     cfg.Preferences.Providers,
+		primaryProvider: cfg.Preferences.PrimaryProvider,
+		minPopularity:   cfg.Preferences.MinPopularity,
+		blockedGenres:   cfg.Preferences.ExcludeGenres,
+		blockedGenreIDs: genreIDSet(cfg.Preferences.ExcludeGenres),
+		blockedTitles:   cfg.Preferences.BlockedTitles,
+		diverse:         cfg.Preferences.Diverse,
+		diversityCaps: DiversityCaps{
+			PerCollection: cfg.Preferences.MaxPerCollection,
+			PerDirector:   cfg.Preferences.MaxPerDirector,
+			PerDecade:     cfg.Preferences.MaxPerDecade,
+		},
+		bingeTime:    cfg.Preferences.BingeTime,
+		providers:    cache.New(filepath.Join(config.GetCacheDir(), "providers"), providerCacheTTL),
+		availability: cache.New(config.GetAvailabilityDir(), availabilityHistoryTTL),
+		responses:    responses,
+		limiter:      netutil.NewRateLimiter(requestsPerSecond, requestBurst),
+	}
+	return client, nil
+}
+
+// SetDiagnostics sends search diagnostics, such as provider filters that
+// fell back to defaultWatchRegion, to w (nil turns them off)
+func (c *Client) SetDiagnostics(w io.Writer) {
+	c.diagnostics = w
 }
 
 func (c *Client) get(endpoint string, params url.Values) ([]byte, error) {
+	return c.getContext(context.Background(), endpoint, params)
+}
+
+// getContext is get with a context, so a caller can abandon the request
+func (c *Client) getContext(ctx context.Context, endpoint string, params url.Values) ([]byte, error) {
 	if params == nil {
 		params = url.Values{}
 	}
 	params.Set("api_key", c.apiKey)
-	if c.language != "" {
+	if c.language != "" && params.Get("language") == "" {
 		params.Set("language", c.language)
 	}
 
 	fullURL := fmt.Sprintf("%s%s?%s", baseURL, endpoint, params.Encode())
 
-	resp, err := c.httpClient.Get(fullURL)
-	if err != nil {
-		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	for attempt := 0; ; attempt++ {
+		c.limiter.Wait()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("HTTP request failed: %w", err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		// Over the limit anyway (other clients share the IP): hold back
+		// every request from this client, then try again
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < maxRateLimitRetries {
+			c.limiter.Pause(netutil.RetryAfter(resp, time.Second))
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("TMDb API error (status %d): %s", resp.StatusCode, string(body))
+		}
+
+		return body, nil
 	}
-	defer resp.Body.Close()
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+// OnRateLimitWait sets a function called when requests are held back by the
+// rate limit long enough to notice, so a UI can say why it's waiting
+func (c *Client) OnRateLimitWait(fn func(wait time.Duration)) {
+	c.limiter.OnWait(fn)
+}
+
+// getCached is get for search and discover queries, reusing a recent response
+// to the identical query when response caching is enabled
+func (c *Client) getCached(endpoint string, params url.Values) ([]byte, error) {
+	return c.getCachedContext(context.Background(), endpoint, params)
+}
+
+// getCachedContext is getCached with a context
+func (c *Client) getCachedContext(ctx context.Context, endpoint string, params url.Values) ([]byte, error) {
+	if c.responses == nil {
+		return c.getContext(ctx, endpoint, params)
+	}
+
+	// Key on the query as sent, minus the API key (get adds it to params)
+	query := url.Values{}
+	for k, v := range params {
+		query[k] = v
+	}
+	if c.language != "" && query.Get("language") == "" {
+		query.Set("language", c.language)
 	}
+	key := endpoint + "?" + query.Encode()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("TMDb API error (status %d): %s", resp.StatusCode, string(body))
+	var cached json.RawMessage
+	if c.responses.Get(key, &cached) {
+		return cached, nil
 	}
 
-	return body, nil
+	data, err := c.getContext(ctx, endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+	c.responses.Set(key, json.RawMessage(data))
+	return data, nil
+}
+
+// adultParam returns the include_adult value for search and discover requests
+func (c *Client) adultParam() string {
+	return strconv.FormatBool(c.includeAdult)
 }
 
 // Media represents a movie or TV show
 type Media struct {
-	ID           int      `json:"id"`
-	Title        string   `json:"title,omitempty"`        // for movies
-	Name         string   `json:"name,omitempty"`         // for TV shows
-	Overview     string   `json:"overview"`
-	PosterPath   string   `json:"poster_path"`
-	BackdropPath string   `json:"backdrop_path"`
-	VoteAverage  float64  `json:"vote_average"`
-	VoteCount    int      `json:"vote_count"`
-	ReleaseDate  string   `json:"release_date,omitempty"`  // for movies
-	FirstAirDate string   `json:"first_air_date,omitempty"` // for TV shows
-	GenreIDs     []int    `json:"genre_ids"`
-	MediaType    string   `json:"media_type,omitempty"`
-	Popularity   float64  `json:"popularity"`
-	Runtime      int      `json:"runtime,omitempty"` // only in detail view
-	Providers    []Provider `json:"-"` // populated separately
+	ID           int        `json:"id"`
+	Title        string     `json:"title,omitempty"` // for movies
+	Name         string     `json:"name,omitempty"`  // for TV shows
+	Overview     string     `json:"overview"`
+	PosterPath   string     `json:"poster_path"`
+	BackdropPath string     `json:"backdrop_path"`
+	VoteAverage  float64    `json:"vote_average"`
+	VoteCount    int        `json:"vote_count"`
+	ReleaseDate  string     `json:"release_date,omitempty"`   // for movies
+	FirstAirDate string     `json:"first_air_date,omitempty"` // for TV shows
+	GenreIDs     []int      `json:"genre_ids"`
+	MediaType    string     `json:"media_type,omitempty"`
+	OriginalLang string     `json:"original_language,omitempty"` // ISO 639-1 code
+	Popularity   float64    `json:"popularity"`
+	Adult        bool       `json:"adult"`
+	Runtime      int        `json:"runtime,omitempty"` // only in detail view
+	Providers    []Provider `json:"-"`                 // populated separately
+	WatchLink    string     `json:"-"`                 // TMDb watch page, populated with Providers
+	BingeMinutes int        `json:"-"`                 // TV only: total runtime, populated by EnrichWithBingeTime
 }
 
 // GetDisplayTitle returns the appropriate title based on media type
@@ -92,6 +230,20 @@ func (m *Media) GetDisplayTitle() string {
 	return m.Name
 }
 
+// GetMediaType returns "movie" or "tv": MediaType when it's set (search and
+// discover set it), otherwise a guess from the fields TMDb fills in for each.
+// TV shows have a name and first air date, movies a title and release date.
+func (m *Media) GetMediaType() string {
+	switch {
+	case m.MediaType == "movie" || m.MediaType == "tv":
+		return m.MediaType
+	case m.FirstAirDate != "" || (m.Name != "" && m.Title == ""):
+		return "tv"
+	default:
+		return "movie"
+	}
+}
+
 // GetDisplayYear returns the release year
 func (m *Media) GetDisplayYear() string {
 	date := m.ReleaseDate
@@ -109,6 +261,7 @@ type Provider struct {
 	ID       int    `json:"provider_id"`
 	Name     string `json:"provider_name"`
 	LogoPath string `json:"logo_path"`
+	Type     string `json:"type,omitempty"` // how it's offered: flatrate, free, rent, or buy (set by wtfsiw)
 }
 
 // SearchResponse represents the API response for search/discover
@@ -128,18 +281,27 @@ type Genre struct {
 // SearchParams represents the structured search parameters for discovering content
 type SearchParams struct {
 	// Core search
-	Keywords  []string `json:"keywords"`
-	Genres    []string `json:"genres"`
-	SimilarTo []string `json:"similar_to"`
-	MediaType string   `json:"media_type"` // movie, tv, or all
+	Keywords      []string `json:"keywords"`
+	Genres        []string `json:"genres"`
+	ExcludeGenres []string `json:"exclude_genres,omitempty"` // genres to leave out
+	SimilarTo     []string `json:"similar_to"`
+	MediaType     string   `json:"media_type"` // movie, tv, or all
 
 	// Date/Year filters
+	Year     int `json:"year,omitempty"` // a single year, overriding YearFrom and YearTo
 	YearFrom int `json:"year_from,omitempty"`
 	YearTo   int `json:"year_to,omitempty"`
 
 	// Rating filters
 	MinRating    float64 `json:"min_rating,omitempty"`     // 0-10 scale
 	MinVoteCount int     `json:"min_vote_count,omitempty"` // minimum number of votes
+	MaxVoteCount int     `json:"-"`                        // maximum number of votes, set by Quality (0 = none)
+
+	// Quality preset bundling the rating and vote filters: mainstream, critic, or hidden-gem
+	Quality string `json:"quality,omitempty"`
+
+	// Popularity floor (TMDb popularity score, 0 = preferences.min_popularity)
+	MinPopularity float64 `json:"min_popularity,omitempty"`
 
 	// Runtime
 	MaxRuntime int `json:"max_runtime,omitempty"` // in minutes
@@ -151,14 +313,17 @@ type SearchParams struct {
 	Actors    []string `json:"actors,omitempty"`    // actor names mentioned
 	Directors []string `json:"directors,omitempty"` // director names mentioned
 	Studios   []string `json:"studios,omitempty"`   // production companies: Pixar, A24, Marvel, etc.
+	Networks  []string `json:"networks,omitempty"`  // TV networks: HBO, AMC, BBC One, etc. (TV only)
 
 	// Streaming
-	WatchProviders    []string `json:"watch_providers,omitempty"`     // Netflix, HBO Max, Disney+, etc.
-	MonetizationType  string   `json:"monetization_type,omitempty"`   // flatrate, free, rent, buy
+	WatchProviders    []string `json:"watch_providers,omitempty"`     // Netflix, HBO Max, Disney+, etc. (empty = preferences.providers)
+	ProviderMatch     string   `json:"provider_match,omitempty"`      // "any" (default) or "all" of WatchProviders
+	MonetizationTypes []string `json:"monetization_types,omitempty"`  // flatrate, free, rent, buy (OR logic)
 	AvailableInRegion string   `json:"available_in_region,omitempty"` // ISO 3166-1 code: US, GB, etc.
 
 	// Content rating
-	Certification string `json:"certification,omitempty"` // G, PG, PG-13, R, NC-17 (movies) or TV-Y, TV-G, TV-PG, TV-14, TV-MA (TV)
+	Certification    string `json:"certification,omitempty"`     // G, PG, PG-13, R, NC-17 (movies) or TV-Y, TV-G, TV-PG, TV-14, TV-MA (TV)
+	MaxCertification string `json:"max_certification,omitempty"` // highest allowed rating; movie ratings are translated for TV
 
 	// TV-specific
 	TVStatus string `json:"tv_status,omitempty"` // returning, ended, canceled
@@ -166,10 +331,30 @@ type SearchParams struct {
 	// Sorting
 	SortBy string `json:"sort_by,omitempty"` // popularity, rating, release_date, revenue
 
+	// Result count (0 = preferences.search_count)
+	Limit int `json:"-"`
+
+	// Cap results sharing a collection, director, or decade (always on with preferences.diverse)
+	Diverse bool `json:"diverse,omitempty"`
+
+	// Search every service even when preferences.providers is set (coverage reports)
+	AllProviders bool `json:"-"`
+
 	// Non-TMDb (AI interpretation)
 	Mood string `json:"mood,omitempty"` // overall mood/tone (used for AI recommendations)
 }
 
+// HasFilters reports whether the params contain anything TMDb can search on.
+// Mood, media type, sorting and limits alone are not enough to narrow a search.
+func (sp *SearchParams) HasFilters() bool {
+	return len(sp.Keywords) > 0 || len(sp.Genres) > 0 || len(sp.ExcludeGenres) > 0 ||
+		len(sp.SimilarTo) > 0 || len(sp.Actors) > 0 || len(sp.Directors) > 0 ||
+		len(sp.Studios) > 0 || len(sp.Networks) > 0 || len(sp.WatchProviders) > 0 ||
+		sp.Year > 0 || sp.YearFrom > 0 || sp.YearTo > 0 || sp.MinRating > 0 || sp.MinPopularity > 0 || sp.MaxRuntime > 0 ||
+		sp.Quality != "" || sp.OriginalLang != "" || sp.Certification != "" || sp.MaxCertification != "" ||
+		sp.TVStatus != ""
+}
+
 // GenreMap maps genre names to IDs
 var GenreMap = map[string]int{
 	// Movie genres
@@ -200,6 +385,53 @@ var GenreMap = map[string]int{
 	"soap":               10766,
 	"talk":               10767,
 	"war & politics":     10768,
+	"sci-fi & fantasy":   10765,
+	"tv movie":           10770,
+}
+
+// genreNames maps genre IDs back to display names, built from GenreMap. Where
+// several names share an ID the longest wins ("science fiction" over "sci-fi").
+var genreNames = func() map[int]string {
+	names := make(map[int]string)
+	for name, id := range GenreMap {
+		if current, ok := names[id]; !ok || len(name) > len(current) || (len(name) == len(current) && name < current) {
+			names[id] = name
+		}
+	}
+	for id, name := range names {
+		words := strings.Fields(name)
+		for i, w := range words {
+			if w == "tv" {
+				words[i] = "TV"
+				continue
+			}
+			parts := strings.Split(w, "-") // "sci-fi" -> "Sci-Fi"
+			for j, p := range parts {
+				if p != "" {
+					parts[j] = strings.ToUpper(p[:1]) + p[1:]
+				}
+			}
+			words[i] = strings.Join(parts, "-")
+		}
+		names[id] = strings.Join(words, " ")
+	}
+	return names
+}()
+
+// GenreName returns the display name for a TMDb genre ID ("Science Fiction"), or "" if unknown
+func GenreName(id int) string {
+	return genreNames[id]
+}
+
+// GetGenreNames resolves the result's genre IDs to display names, skipping unknown IDs
+func (m *Media) GetGenreNames() []string {
+	var names []string
+	for _, id := range m.GenreIDs {
+		if name := GenreName(id); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
 }
 
 func (c *Client) parseSearchResponse(data []byte) (*SearchResponse, error) {
@@ -209,3 +441,26 @@ func (c *Client) parseSearchResponse(data []byte) (*SearchResponse, error) {
 	}
 	return &resp, nil
 }
+
+// externalIDsResponse is the subset of /{type}/{id}/external_ids used here
+type externalIDsResponse struct {
+	IMDbID string `json:"imdb_id"`
+}
+
+// GetIMDbID returns a title's IMDb ID ("tt0113277"), or "" if TMDb has none
+func (c *Client) GetIMDbID(mediaType string, id int) (string, error) {
+	if mediaType != "movie" && mediaType != "tv" {
+		return "", fmt.Errorf("invalid media type: %s", mediaType)
+	}
+
+	data, err := c.get(fmt.Sprintf("/%s/%d/external_ids", mediaType, id), nil)
+	if err != nil {
+		return "", err
+	}
+
+	var resp externalIDsResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return "", fmt.Errorf("failed to parse external IDs response: %w", err)
+	}
+	return resp.IMDbID, nil
+}
@@ -0,0 +1,163 @@
+package tmdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+)
+
+// Watch orders for a collection's films
+const (
+	WatchOrderRelease       = "release"
+	WatchOrderChronological = "chronological"
+)
+
+// Collection is a TMDb movie collection (a franchise or series of films)
+type Collection struct {
+	ID       int     `json:"id"`
+	Name     string  `json:"name"`
+	Overview string  `json:"overview"`
+	Parts    []Media `json:"parts"`
+}
+
+// collectionSearchResponse is the /search/collection response
+type collectionSearchResponse struct {
+	Results []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"results"`
+}
+
+// movieCollectionResponse is the part of /movie/{id} naming its collection
+type movieCollectionResponse struct {
+	BelongsToCollection *struct {
+		ID int `json:"id"`
+	} `json:"belongs_to_collection"`
+}
+
+// FindCollection finds the collection for a franchise name ("Star Wars") or
+// for any film in it ("Rogue One", "The Empire Strikes Back")
+func (c *Client) FindCollection(query string) (*Collection, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("include_adult", c.adultParam())
+
+	data, err := c.get("/search/collection", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var search collectionSearchResponse
+	if err := json.Unmarshal(data, &search); err != nil {
+		return nil, fmt.Errorf("failed to parse collection search response: %w", err)
+	}
+	if len(search.Results) > 0 {
+		return c.GetCollection(search.Results[0].ID)
+	}
+
+	// Not a collection name; try it as a film title and use the film's collection
+	movieID := c.searchMovieID(query)
+	if movieID == 0 {
+		return nil, fmt.Errorf("no franchise found for %q", query)
+	}
+
+	data, err = c.get(fmt.Sprintf("/movie/%d", movieID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var movie movieCollectionResponse
+	if err := json.Unmarshal(data, &movie); err != nil {
+		return nil, fmt.Errorf("failed to parse movie response: %w", err)
+	}
+	if movie.BelongsToCollection == nil {
+		return nil, fmt.Errorf("%q isn't part of a TMDb collection", query)
+	}
+	return c.GetCollection(movie.BelongsToCollection.ID)
+}
+
+// GetCollection fetches a collection and its films
+func (c *Client) GetCollection(id int) (*Collection, error) {
+	data, err := c.get(fmt.Sprintf("/collection/%d", id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var col Collection
+	if err := json.Unmarshal(data, &col); err != nil {
+		return nil, fmt.Errorf("failed to parse collection response: %w", err)
+	}
+
+	parts := make([]Media, 0, len(col.Parts))
+	for _, m := range col.Parts {
+		if (m.Adult && !c.includeAdult) || c.isBlocked(m) {
+			continue
+		}
+		m.MediaType = "movie"
+		parts = append(parts, m)
+	}
+	col.Parts = parts
+	return &col, nil
+}
+
+// searchMovieID returns the TMDb ID of the best movie match for title, or 0
+func (c *Client) searchMovieID(title string) int {
+	params := url.Values{}
+	params.Set("query", title)
+	params.Set("include_adult", c.adultParam())
+
+	data, err := c.get("/search/movie", params)
+	if err != nil {
+		return 0
+	}
+
+	resp, err := c.parseSearchResponse(data)
+	if err != nil || len(resp.Results) == 0 {
+		return 0
+	}
+	return resp.Results[0].ID
+}
+
+// Ordered returns the collection's films in the requested watch order, and the
+// order actually used. TMDb has no story chronology, so chronological order
+// comes from ChronologyMap; collections without an entry use release order.
+func (col *Collection) Ordered(order string) ([]Media, string) {
+	parts := make([]Media, len(col.Parts))
+	copy(parts, col.Parts)
+
+	// Release order first; it also places films missing from a chronology
+	sort.SliceStable(parts, func(i, j int) bool {
+		return releaseBefore(parts[i].ReleaseDate, parts[j].ReleaseDate)
+	})
+
+	if order != WatchOrderChronological {
+		return parts, WatchOrderRelease
+	}
+	chronology, ok := ChronologyMap[col.ID]
+	if !ok {
+		return parts, WatchOrderRelease
+	}
+
+	position := make(map[int]int, len(chronology))
+	for i, id := range chronology {
+		position[id] = i
+	}
+	sort.SliceStable(parts, func(i, j int) bool {
+		pi, iok := position[parts[i].ID]
+		pj, jok := position[parts[j].ID]
+		if iok && jok {
+			return pi < pj
+		}
+		return iok && !jok // films without a known place go last, in release order
+	})
+	return parts, WatchOrderChronological
+}
+
+// releaseBefore orders release dates ascending, with unknown (unreleased) dates last
+func releaseBefore(a, b string) bool {
+	if a == "" || b == "" {
+		return a != "" && b == ""
+	}
+	return a < b
+}
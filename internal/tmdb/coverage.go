@@ -0,0 +1,63 @@
+package tmdb
+
+import "sort"
+
+// ProviderCount is how many titles of a result set a service streams
+type ProviderCount struct {
+	Provider string
+	Titles   int
+	Yours    bool // in preferences.providers
+}
+
+// Coverage summarizes which services stream a set of titles
+type Coverage struct {
+	Total     int
+	Providers []ProviderCount // most titles first
+	None      int             // titles no service streams (rent or buy only, or nowhere)
+	Yours     int             // titles on at least one of preferences.providers
+	HasYours  bool            // whether preferences.providers is set
+}
+
+// Coverage counts, for results enriched by EnrichWithProviders, how many
+// titles each service streams (subscription or free; rent and buy don't count)
+func (c *Client) Coverage(results []Media) Coverage {
+	mine := make(map[int]bool)
+	for _, name := range c.myProviders {
+		if id, ok := ProviderID(name); ok {
+			mine[id] = true
+		}
+	}
+
+	cov := Coverage{Total: len(results), HasYours: len(mine) > 0}
+	counts := make(map[int]*ProviderCount)
+	var order []int
+	for _, m := range results {
+		streaming, yours := false, false
+		for _, p := range m.Providers {
+			if p.Type != MonetizationFlatrate && p.Type != MonetizationFree {
+				continue
+			}
+			streaming = true
+			yours = yours || mine[p.ID]
+			if _, ok := counts[p.ID]; !ok {
+				counts[p.ID] = &ProviderCount{Provider: p.Name, Yours: mine[p.ID]}
+				order = append(order, p.ID)
+			}
+			counts[p.ID].Titles++
+		}
+		if !streaming {
+			cov.None++
+		}
+		if yours {
+			cov.Yours++
+		}
+	}
+
+	for _, id := range order {
+		cov.Providers = append(cov.Providers, *counts[id])
+	}
+	sort.SliceStable(cov.Providers, func(i, j int) bool {
+		return cov.Providers[i].Titles > cov.Providers[j].Titles
+	})
+	return cov
+}
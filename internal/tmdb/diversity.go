@@ -0,0 +1,160 @@
+package tmdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+)
+
+// maxDiversityCandidates bounds how many results are looked up for
+// diversity, since each one costs a request
+const maxDiversityCandidates = 60
+
+// DiversityCaps limit how many results may share an attribute. 0 means no
+// cap on that attribute.
+type DiversityCaps struct {
+	PerCollection int // films in the same collection ("Ocean's", "Toy Story")
+	PerDirector   int // titles by the same director (or creator, for TV)
+	PerDecade     int // titles released in the same decade
+}
+
+// diversityInfo is what a title is grouped by
+type diversityInfo struct {
+	collection int
+	creators   []int
+}
+
+// creditsDetailsResponse is the part of /movie/{id} or /tv/{id} (with
+// credits appended) naming its collection, directors, and creators
+type creditsDetailsResponse struct {
+	BelongsToCollection *struct {
+		ID int `json:"id"`
+	} `json:"belongs_to_collection"`
+	CreatedBy []struct {
+		ID int `json:"id"`
+	} `json:"created_by"`
+	Credits struct {
+		Crew []struct {
+			ID  int    `json:"id"`
+			Job string `json:"job"`
+		} `json:"crew"`
+	} `json:"credits"`
+}
+
+// diversify keeps results in order, skipping any that would push a
+// collection, director, or decade past its cap, until limit are kept.
+// Titles whose details can't be fetched are only capped by decade.
+func (c *Client) diversify(results []Media, limit int) []Media {
+	caps := c.diversityCaps
+	if caps == (DiversityCaps{}) {
+		return results
+	}
+
+	candidates := results
+	if len(candidates) > maxDiversityCandidates {
+		candidates = candidates[:maxDiversityCandidates]
+	}
+	var info []diversityInfo
+	if caps.PerCollection > 0 || caps.PerDirector > 0 {
+		info = c.fetchDiversityInfo(candidates)
+	}
+
+	collections := make(map[int]int)
+	creators := make(map[int]int)
+	decades := make(map[int]int)
+	var kept []Media
+	for i, m := range candidates {
+		if len(kept) >= limit {
+			break
+		}
+		decade := releaseDecade(m)
+		if caps.PerDecade > 0 && decade > 0 && decades[decade] >= caps.PerDecade {
+			continue
+		}
+		var d diversityInfo
+		if info != nil {
+			d = info[i]
+		}
+		if caps.PerCollection > 0 && d.collection > 0 && collections[d.collection] >= caps.PerCollection {
+			continue
+		}
+		if caps.PerDirector > 0 && overCap(creators, d.creators, caps.PerDirector) {
+			continue
+		}
+
+		decades[decade]++
+		if d.collection > 0 {
+			collections[d.collection]++
+		}
+		for _, id := range d.creators {
+			creators[id]++
+		}
+		kept = append(kept, m)
+	}
+	return kept
+}
+
+// overCap reports whether any of ids has already been counted max times
+func overCap(counts map[int]int, ids []int, max int) bool {
+	for _, id := range ids {
+		if counts[id] >= max {
+			return true
+		}
+	}
+	return false
+}
+
+// releaseDecade returns the decade a title came out in (1990 for 1994), or 0 if unknown
+func releaseDecade(m Media) int {
+	year, err := strconv.Atoi(m.GetDisplayYear())
+	if err != nil {
+		return 0
+	}
+	return year / 10 * 10
+}
+
+// fetchDiversityInfo looks up each result's collection and directors (or
+// creators) in parallel. Failed lookups leave a zero diversityInfo.
+func (c *Client) fetchDiversityInfo(results []Media) []diversityInfo {
+	info := make([]diversityInfo, len(results))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentLookups)
+
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			m := results[i]
+			params := url.Values{}
+			params.Set("append_to_response", "credits")
+			data, err := c.getCached(fmt.Sprintf("/%s/%d", m.GetMediaType(), m.ID), params)
+			if err != nil {
+				return
+			}
+			var details creditsDetailsResponse
+			if err := json.Unmarshal(data, &details); err != nil {
+				return
+			}
+
+			if details.BelongsToCollection != nil {
+				info[i].collection = details.BelongsToCollection.ID
+			}
+			for _, creator := range details.CreatedBy {
+				info[i].creators = append(info[i].creators, creator.ID)
+			}
+			for _, crew := range details.Credits.Crew {
+				if crew.Job == "Director" && m.GetMediaType() == "movie" {
+					info[i].creators = append(info[i].creators, crew.ID)
+				}
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	return info
+}
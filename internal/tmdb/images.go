@@ -0,0 +1,117 @@
+package tmdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"wtfsiw/internal/cache"
+	"wtfsiw/internal/config"
+)
+
+// imageConfigCacheTTL is how long TMDb's /configuration is reused. TMDb
+// suggests re-checking it every few days; it almost never changes.
+const imageConfigCacheTTL = 7 * 24 * time.Hour
+
+// DefaultPosterSize is a poster width that's sharp in a terminal image
+// viewer or a browser without being a large download
+const DefaultPosterSize = "w500"
+
+// defaultImageConfig is used until (or if) /configuration can't be fetched
+var defaultImageConfig = ImageConfig{
+	SecureBaseURL: "https://image.tmdb.org/t/p/",
+	PosterSizes:   []string{"w92", "w154", "w185", "w342", "w500", "w780", "original"},
+}
+
+// ImageConfig is where TMDb serves images from and in which sizes
+type ImageConfig struct {
+	SecureBaseURL string   `json:"secure_base_url"`
+	PosterSizes   []string `json:"poster_sizes"`
+}
+
+// configurationResponse is the subset of /configuration used here
+type configurationResponse struct {
+	Images ImageConfig `json:"images"`
+}
+
+// imageConfig returns TMDb's image configuration, loading it the first time
+// an image URL is needed rather than on every client start
+func (c *Client) imageConfig() ImageConfig {
+	c.imagesOnce.Do(func() {
+		c.images = c.loadImageConfig()
+	})
+	return c.images
+}
+
+// loadImageConfig returns TMDb's image configuration, from the cache when
+// possible. Any failure falls back to the long-standing defaults, so image
+// URLs still work offline or if TMDb's answer is unusable. The defaults are
+// cached too, so a failing /configuration isn't retried on every run.
+func (c *Client) loadImageConfig() ImageConfig {
+	store := cache.New(filepath.Join(config.GetCacheDir(), "configuration"), imageConfigCacheTTL)
+
+	var images ImageConfig
+	if store.Get("images", &images) {
+		return images
+	}
+
+	data, err := c.get("/configuration", nil)
+	if err != nil {
+		store.Set("images", defaultImageConfig)
+		return defaultImageConfig
+	}
+	var resp configurationResponse
+	if err := json.Unmarshal(data, &resp); err != nil || resp.Images.SecureBaseURL == "" {
+		store.Set("images", defaultImageConfig)
+		return defaultImageConfig
+	}
+	if len(resp.Images.PosterSizes) == 0 {
+		resp.Images.PosterSizes = defaultImageConfig.PosterSizes
+	}
+
+	store.Set("images", resp.Images)
+	return resp.Images
+}
+
+// PosterURL returns the full URL of a poster_path ("/abc.jpg") at the given
+// size ("w342", "original", or "" for DefaultPosterSize). A size TMDb doesn't
+// offer is rounded up to the next available width. Returns "" without a path.
+func (c *Client) PosterURL(path, size string) string {
+	if path == "" {
+		return ""
+	}
+	if size == "" {
+		size = DefaultPosterSize
+	}
+	images := c.imageConfig()
+	base := strings.TrimSuffix(images.SecureBaseURL, "/")
+	return fmt.Sprintf("%s/%s/%s", base, closestSize(images.PosterSizes, size), strings.TrimPrefix(path, "/"))
+}
+
+// closestSize returns size if it's available, otherwise the smallest
+// available width at least as large, or "original" if none is
+func closestSize(available []string, size string) string {
+	want, ok := sizeWidth(size)
+	best, bestWidth := "original", 0
+	for _, s := range available {
+		if s == size {
+			return s
+		}
+		if w, isWidth := sizeWidth(s); ok && isWidth && w >= want && (bestWidth == 0 || w < bestWidth) {
+			best, bestWidth = s, w
+		}
+	}
+	return best
+}
+
+// sizeWidth parses a width size like "w342"
+func sizeWidth(size string) (int, bool) {
+	if !strings.HasPrefix(size, "w") {
+		return 0, false
+	}
+	w, err := strconv.Atoi(size[1:])
+	return w, err == nil
+}
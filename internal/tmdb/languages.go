@@ -0,0 +1,84 @@
+package tmdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"wtfsiw/internal/config"
+)
+
+// translationsResponse is the /{type}/{id}/translations response
+type translationsResponse struct {
+	Translations []struct {
+		Language string `json:"iso_639_1"`
+	} `json:"translations"`
+}
+
+// LanguageName returns the display name for an ISO 639-1 code (the upper-cased
+// code if unknown, "" for an empty code)
+func LanguageName(code string) string {
+	if name, ok := LanguageNameMap[NormalizeName(code)]; ok {
+		return name
+	}
+	return strings.ToUpper(code)
+}
+
+// IsForeignLanguage reports whether a language name (as from LanguageName)
+// differs from the user's preferences.language
+func IsForeignLanguage(name string) bool {
+	if name == "" {
+		return false
+	}
+	locale, _, _ := strings.Cut(config.Get().Preferences.Language, "-")
+	if locale == "" {
+		locale = "en"
+	}
+	return name != LanguageName(locale)
+}
+
+// LanguageNote labels a title's original language for display, with a
+// reminder of the foreign_audio preference ("" when it's the user's language)
+func LanguageNote(name string) string {
+	if !IsForeignLanguage(name) {
+		return ""
+	}
+	switch config.Get().Preferences.ForeignAudio {
+	case "original":
+		return "Original " + name + " audio"
+	case "subbed":
+		return name + " (check for English subtitles)"
+	case "dubbed":
+		return name + " (check for an English dub)"
+	}
+	return name
+}
+
+// GetTranslations returns the ISO 639-1 codes of the languages a title has been
+// localized into on TMDb. TMDb doesn't list subtitle or audio tracks, but an
+// English localization usually means an English release (subtitled or dubbed).
+func (c *Client) GetTranslations(mediaType string, id int) ([]string, error) {
+	if mediaType != "movie" && mediaType != "tv" {
+		return nil, fmt.Errorf("invalid media type: %s", mediaType)
+	}
+
+	data, err := c.get(fmt.Sprintf("/%s/%d/translations", mediaType, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp translationsResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse translations response: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var languages []string
+	for _, t := range resp.Translations {
+		if t.Language != "" && !seen[t.Language] {
+			seen[t.Language] = true
+			languages = append(languages, t.Language)
+		}
+	}
+	return languages, nil
+}
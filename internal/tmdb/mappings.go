@@ -1,7 +1,23 @@
 package tmdb
 
+import (
+	"strings"
+	"unicode"
+)
+
+// NormalizeName turns a genre, provider, or other filter name from model output
+// into a key for the maps below: lowercased, with surrounding whitespace and
+// punctuation dropped ("Netflix." and " Disney+ " become "netflix" and "disney+").
+// Symbols like the + in "Disney+" aren't punctuation, so they're kept.
+func NormalizeName(name string) string {
+	return strings.ToLower(strings.TrimFunc(name, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}))
+}
+
 // WatchProviderMap maps common provider names to TMDb provider IDs
-// Based on US region - IDs may vary by region
+// Based on US region - IDs may vary by region. Names from before a rebrand
+// are kept as aliases of the current ID (see ProviderRebrandMap).
 var WatchProviderMap = map[string]int{
 	"netflix":            8,
 	"amazon prime":       9,
@@ -9,25 +25,29 @@ var WatchProviderMap = map[string]int{
 	"prime video":        9,
 	"disney+":            337,
 	"disney plus":        337,
-	"hbo max":            384,
-	"max":                1899, // HBO Max rebranded to Max
+	"hbo max":            1899, // rebranded to Max
+	"hbo":                1899,
+	"max":                1899,
 	"hulu":               15,
 	"apple tv+":          350,
 	"apple tv plus":      350,
 	"paramount+":         531,
 	"paramount plus":     531,
 	"peacock":            386,
+	"peacock premium":    386,
 	"showtime":           37,
 	"starz":              43,
 	"criterion channel":  258,
 	"mubi":               11,
 	"shudder":            99,
 	"tubi":               73,
+	"tubi tv":            73,
 	"pluto tv":           300,
 	"crunchyroll":        283,
 	"funimation":         269,
 	"youtube":            192,
 	"google play":        3,
+	"google play movies": 3,
 	"vudu":               7,
 	"fandango at home":   7, // Vudu rebranded
 	"amazon video":       10,
@@ -42,6 +62,66 @@ var WatchProviderMap = map[string]int{
 	"bet plus":           1759,
 }
 
+// ProviderRebrandMap maps retired TMDb provider IDs to the ID that replaced them.
+// TMDb still returns the old IDs for some titles and regions.
+var ProviderRebrandMap = map[int]int{
+	384: 1899, // HBO Max -> Max
+}
+
+// ProviderNameMap gives the current display name for rebranded providers,
+// used in place of whatever name TMDb returned
+var ProviderNameMap = map[int]string{
+	1899: "Max",
+	7:    "Fandango at Home", // formerly Vudu
+}
+
+// ProviderAbbrevMap maps TMDb provider IDs to short badge labels.
+// Covers every provider in WatchProviderMap.
+var ProviderAbbrevMap = map[int]string{
+	8:    "N",     // Netflix
+	9:    "P",     // Amazon Prime Video
+	337:  "D+",    // Disney Plus
+	384:  "M",     // HBO Max
+	1899: "M",     // Max
+	15:   "H",     // Hulu
+	350:  "A+",    // Apple TV Plus
+	531:  "P+",    // Paramount Plus
+	386:  "Pk",    // Peacock
+	37:   "SHO",   // Showtime
+	43:   "STZ",   // Starz
+	258:  "CC",    // Criterion Channel
+	11:   "MUBI",  // MUBI
+	99:   "SHU",   // Shudder
+	73:   "Tubi",  // Tubi
+	300:  "Pluto", // Pluto TV
+	283:  "CR",    // Crunchyroll
+	269:  "FUNi",  // Funimation
+	192:  "YT",    // YouTube
+	3:    "GP",    // Google Play
+	7:    "FaH",   // Fandango at Home (Vudu)
+	10:   "AV",    // Amazon Video
+	2:    "ATV",   // Apple TV
+	636:  "MGM+",  // MGM Plus
+	526:  "AMC+",  // AMC Plus
+	520:  "DSC+",  // Discovery Plus
+	1759: "BET+",  // BET Plus
+}
+
+// ProviderLinkMap maps TMDb provider IDs to a search URL on the provider's own
+// site, with %s for the query-escaped title. Where the app is installed, these
+// usually open in it. Providers without an entry use the TMDb watch page.
+var ProviderLinkMap = map[int]string{
+	8:    "https://www.netflix.com/search?q=%s",
+	9:    "https://www.amazon.com/s?k=%s&i=instant-video",
+	337:  "https://www.disneyplus.com/search?q=%s",
+	1899: "https://play.max.com/search?q=%s",
+	15:   "https://www.hulu.com/search?q=%s",
+	350:  "https://tv.apple.com/search?term=%s",
+	386:  "https://www.peacocktv.com/search?q=%s",
+	283:  "https://www.crunchyroll.com/search?q=%s",
+	192:  "https://www.youtube.com/results?search_query=%s",
+}
+
 // StudioMap maps common studio names to TMDb company IDs
 var StudioMap = map[string]int{
 	// Major Studios
@@ -103,6 +183,65 @@ var StudioMap = map[string]int{
 	"atomic monster": 76907,
 }
 
+// NetworkMap maps TV network and channel names to TMDb network IDs, for
+// "HBO shows" or "AMC dramas": the channel that made or aired a show, as
+// opposed to where it streams now (WatchProviderMap) or who produced it (StudioMap)
+var NetworkMap = map[string]int{
+	// US broadcast
+	"abc":    2,
+	"nbc":    6,
+	"cbs":    16,
+	"fox":    19,
+	"the cw": 71,
+	"cw":     71,
+	"pbs":    14,
+
+	// US cable
+	"hbo":             49,
+	"amc":             174,
+	"fx":              88,
+	"fxx":             1035,
+	"showtime":        67,
+	"starz":           318,
+	"cinemax":         359,
+	"tnt":             41,
+	"usa network":     30,
+	"syfy":            77,
+	"comedy central":  47,
+	"adult swim":      80,
+	"cartoon network": 56,
+	"nickelodeon":     13,
+	"mtv":             33,
+	"bravo":           74,
+	"a&e":             129,
+
+	// Streaming originals
+	"netflix":     213,
+	"hulu":        453,
+	"amazon":      1024,
+	"prime video": 1024,
+	"apple tv+":   2552,
+	"apple tv":    2552,
+	"disney+":     2739,
+	"disney plus": 2739,
+	"max":         3186,
+	"hbo max":     3186,
+	"peacock":     3353,
+	"paramount+":  4330,
+
+	// UK
+	"bbc one":      4,
+	"bbc two":      332,
+	"bbc":          4,
+	"itv":          9,
+	"channel 4":    26,
+	"sky atlantic": 1063,
+
+	// Korea
+	"tvn":  866,
+	"jtbc": 885,
+}
+
 // CertificationMap maps user-friendly names to TMDb certification values
 var CertificationMap = map[string]string{
 	// Movies (US)
@@ -129,6 +268,15 @@ var CertificationMap = map[string]string{
 	"tvma":   "TV-MA",
 }
 
+// TVCertificationMap maps US movie certifications to their TV equivalents
+var TVCertificationMap = map[string]string{
+	"G":     "TV-G",
+	"PG":    "TV-PG",
+	"PG-13": "TV-14",
+	"R":     "TV-MA",
+	"NC-17": "TV-MA",
+}
+
 // TVStatusMap maps user-friendly status names to TMDb status values
 var TVStatusMap = map[string]int{
 	"returning":        0, // Returning Series
@@ -159,6 +307,69 @@ var SortByMap = map[string]string{
 	"box office":    "revenue.desc",
 	"title":         "title.asc",
 	"alphabetical":  "title.asc",
+	"votes":         "vote_count.desc",
+	"vote_count":    "vote_count.desc",
+	"most voted":    "vote_count.desc",
+
+	// Ascending
+	"oldest":        "primary_release_date.asc",
+	"oldest first":  "primary_release_date.asc",
+	"lowest_rated":  "vote_average.asc",
+	"lowest rated":  "vote_average.asc",
+	"least popular": "popularity.asc",
+
+	// TMDb has no random order; Discover shuffles popular results instead
+	"random":  sortRandom,
+	"shuffle": sortRandom,
+}
+
+// sortRandom is the SortByMap value for shuffled results
+const sortRandom = "random"
+
+// ChronologyMap lists, per TMDb collection ID, the collection's movie IDs in
+// story (in-universe) order. TMDb has no chronology data; collections not
+// listed here can only be ordered by release date.
+var ChronologyMap = map[int][]int{
+	// Star Wars: Episodes I-IX
+	10: {1893, 1894, 1895, 11, 1891, 1892, 140607, 181808, 181812},
+	// Indiana Jones: Temple of Doom (1935) is a prequel to Raiders (1936)
+	84: {87, 85, 89, 217, 335977},
+	// X-Men: First Class, Days of Future Past, Apocalypse, Dark Phoenix, then the originals
+	748: {49538, 127585, 246655, 320288, 36657, 36658, 36668},
+	// Fast & Furious: Tokyo Drift takes place after Fast & Furious 6
+	9485: {9799, 584, 13804, 51497, 82992, 9615, 168259, 337339, 385128, 385687},
+	// The Hunger Games: The Ballad of Songbirds & Snakes is a prequel
+	131635: {695721, 70160, 101299, 131631, 131634},
+}
+
+// LanguageNameMap maps ISO 639-1 codes to language names for display
+var LanguageNameMap = map[string]string{
+	"en": "English",
+	"ko": "Korean",
+	"ja": "Japanese",
+	"zh": "Chinese",
+	"cn": "Cantonese", // TMDb uses "cn" for Cantonese
+	"fr": "French",
+	"es": "Spanish",
+	"de": "German",
+	"it": "Italian",
+	"pt": "Portuguese",
+	"hi": "Hindi",
+	"ta": "Tamil",
+	"te": "Telugu",
+	"ru": "Russian",
+	"sv": "Swedish",
+	"da": "Danish",
+	"no": "Norwegian",
+	"fi": "Finnish",
+	"nl": "Dutch",
+	"pl": "Polish",
+	"tr": "Turkish",
+	"th": "Thai",
+	"id": "Indonesian",
+	"ar": "Arabic",
+	"he": "Hebrew",
+	"fa": "Persian",
 }
 
 // MonetizationTypeMap maps user-friendly names to TMDb values
@@ -0,0 +1,125 @@
+package tmdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+)
+
+// Filmography orders
+const (
+	FilmographyByRelease = "release"
+	FilmographyByRating  = "rating"
+)
+
+// Filmography is the films a person directed
+type Filmography struct {
+	Person string  // name as TMDb has it
+	Films  []Media // movies only, in no particular order (see Sorted)
+}
+
+// personSearchResponse is the /search/person response
+type personSearchResponse struct {
+	Results []struct {
+		ID                 int     `json:"id"`
+		Name               string  `json:"name"`
+		KnownForDepartment string  `json:"known_for_department"`
+		Popularity         float64 `json:"popularity"`
+	} `json:"results"`
+}
+
+// movieCreditsResponse is the crew part of /person/{id}/movie_credits
+type movieCreditsResponse struct {
+	Crew []struct {
+		Media
+		Job string `json:"job"`
+	} `json:"crew"`
+}
+
+// GetDirectorFilmography fetches the films a director directed, by name.
+// Credits come from the person's own filmography rather than a discover
+// search, so every film is there; other crew jobs (producing, writing) are
+// left out.
+func (c *Client) GetDirectorFilmography(name string) (*Filmography, error) {
+	id, person, err := c.searchDirector(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := c.get(fmt.Sprintf("/person/%d/movie_credits", id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var credits movieCreditsResponse
+	if err := json.Unmarshal(data, &credits); err != nil {
+		return nil, fmt.Errorf("failed to parse movie credits response: %w", err)
+	}
+
+	films := make([]Media, 0)
+	seen := make(map[int]bool)
+	for _, credit := range credits.Crew {
+		m := credit.Media
+		if credit.Job != "Director" || seen[m.ID] {
+			continue // co-directed films can be listed twice
+		}
+		if (m.Adult && !c.includeAdult) || c.isBlocked(m) {
+			continue
+		}
+		seen[m.ID] = true
+		m.MediaType = "movie"
+		films = append(films, m)
+	}
+
+	return &Filmography{Person: person, Films: films}, nil
+}
+
+// searchDirector finds a person by name, preferring people known for
+// directing over an actor who shares the name
+func (c *Client) searchDirector(name string) (int, string, error) {
+	params := url.Values{}
+	params.Set("query", name)
+	params.Set("include_adult", c.adultParam())
+
+	data, err := c.get("/search/person", params)
+	if err != nil {
+		return 0, "", err
+	}
+
+	var resp personSearchResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return 0, "", fmt.Errorf("failed to parse person search response: %w", err)
+	}
+	if len(resp.Results) == 0 {
+		return 0, "", fmt.Errorf("no person found for %q", name)
+	}
+
+	for _, p := range resp.Results {
+		if p.KnownForDepartment == "Directing" {
+			return p.ID, p.Name, nil
+		}
+	}
+	return resp.Results[0].ID, resp.Results[0].Name, nil
+}
+
+// Sorted returns the films in order: FilmographyByRelease is oldest first,
+// with unreleased films last; FilmographyByRating is best rated first
+func (f *Filmography) Sorted(order string) []Media {
+	films := append([]Media(nil), f.Films...)
+	switch order {
+	case FilmographyByRating:
+		sort.SliceStable(films, func(i, j int) bool {
+			return films[i].VoteAverage > films[j].VoteAverage
+		})
+	default:
+		sort.SliceStable(films, func(i, j int) bool {
+			a, b := films[i].ReleaseDate, films[j].ReleaseDate
+			if a == "" || b == "" {
+				return a != "" // unreleased (no date yet) at the end
+			}
+			return a < b
+		})
+	}
+	return films
+}
@@ -0,0 +1,82 @@
+package tmdb
+
+import "strings"
+
+// kidsMaxCertification is the highest movie rating allowed in kids mode (TV-PG for shows)
+const kidsMaxCertification = "PG"
+
+// kidsExcludedGenres are genres never shown in kids mode
+var kidsExcludedGenres = []string{"horror", "thriller"}
+
+// ApplyKidsPreset restricts search params to family-appropriate content:
+// certification at most PG (TV-PG for shows) and no horror or thrillers
+func ApplyKidsPreset(sp *SearchParams) {
+	sp.Certification = ""
+	sp.MaxCertification = kidsMaxCertification
+
+	genres := make([]string, 0, len(sp.Genres))
+	for _, g := range sp.Genres {
+		if !isKidsExcludedGenre(g) {
+			genres = append(genres, g)
+		}
+	}
+	sp.Genres = genres
+
+	sp.ExcludeGenres = append(append([]string{}, sp.ExcludeGenres...), kidsExcludedGenres...)
+}
+
+func isKidsExcludedGenre(name string) bool {
+	for _, g := range kidsExcludedGenres {
+		if strings.EqualFold(NormalizeName(name), g) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasKidsExcludedGenre checks a search result's genre IDs against the kids mode exclusions
+func hasKidsExcludedGenre(m Media) bool {
+	for _, id := range m.GenreIDs {
+		for _, g := range kidsExcludedGenres {
+			if GenreMap[g] == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// QualityPreset bundles rating and vote-count floors under one name
+type QualityPreset struct {
+	MinRating    float64
+	MinVoteCount int
+	MaxVoteCount int // 0 = no ceiling
+}
+
+// QualityPresets are the choices for SearchParams.Quality (--quality)
+var QualityPresets = map[string]QualityPreset{
+	"mainstream": {MinVoteCount: 5000},                                    // widely seen, any rating
+	"critic":     {MinRating: 8, MinVoteCount: 500},                       // acclaimed, with enough votes to trust it
+	"hidden-gem": {MinRating: 7.5, MinVoteCount: 100, MaxVoteCount: 1000}, // well rated but little seen
+}
+
+// QualityNames lists the quality presets, for help and error messages
+var QualityNames = []string{"mainstream", "critic", "hidden-gem"}
+
+// ApplyQualityPreset raises search params' rating and vote floors to those of
+// the named preset, and sets its vote ceiling. Unknown names change nothing.
+func ApplyQualityPreset(sp *SearchParams, name string) {
+	preset, ok := QualityPresets[NormalizeName(name)]
+	if !ok {
+		return
+	}
+	if preset.MinRating > sp.MinRating {
+		sp.MinRating = preset.MinRating
+	}
+	if preset.MinVoteCount > sp.MinVoteCount {
+		sp.MinVoteCount = preset.MinVoteCount
+	}
+	if preset.MaxVoteCount > 0 {
+		sp.MaxVoteCount = preset.MaxVoteCount
+	}
+}
@@ -3,8 +3,36 @@ package tmdb
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
+// maxConcurrentLookups bounds parallel provider requests to stay within TMDb rate limits
+const maxConcurrentLookups = 8
+
+// Provider enrichment retries: total attempts per title, and the pause before
+// the second attempt (growing linearly after that)
+const (
+	maxEnrichAttempts = 3
+	enrichRetryDelay  = 500 * time.Millisecond
+)
+
+// providerNameLanguage is the language provider names are fetched in
+const providerNameLanguage = "en-US"
+
+// providerCacheTTL is how long watch provider lookups are cached on disk.
+// Availability rarely changes within a day.
+const providerCacheTTL = 24 * time.Hour
+
+// cachedProviders is a cached GetWatchProviders result
+type cachedProviders struct {
+	Providers []Provider `json:"providers"`
+	Link      string     `json:"link"`
+}
+
 // WatchProvidersResponse represents the watch providers API response
 type WatchProvidersResponse struct {
 	ID      int                        `json:"id"`
@@ -20,94 +48,374 @@ type CountryProvider struct {
 	Free     []Provider `json:"free"`     // Free with ads
 }
 
-// GetWatchProviders fetches streaming providers for a movie or TV show
+// GetWatchProviders fetches streaming providers for a movie or TV show.
+// Results are cached on disk for providerCacheTTL per title and region.
 func (c *Client) GetWatchProviders(mediaType string, id int) ([]Provider, string, error) {
-	endpoint := fmt.Sprintf("/%s/%d/watch/providers", mediaType, id)
+	// Get providers for the configured region
+	region := c.watchRegion()
+
+	key := c.providerKey(mediaType, id)
+	var cached cachedProviders
+	if c.providers != nil && c.providers.Get(key, &cached) {
+		return canonicalProviders(cached.Providers), cached.Link, nil
+	}
 
-	data, err := c.get(endpoint, nil)
+	providers, link, err := c.fetchWatchProviders(mediaType, id, region)
 	if err != nil {
 		return nil, "", err
 	}
+	if c.providers != nil {
+		c.providers.Set(key, cachedProviders{Providers: providers, Link: link})
+	}
+	c.recordAvailability(key, providers)
+	return providers, link, nil
+}
 
-	var resp WatchProvidersResponse
-	if err := json.Unmarshal(data, &resp); err != nil {
-		return nil, "", fmt.Errorf("failed to parse providers response: %w", err)
+// providerKey identifies a title's providers in the configured region, for
+// the provider cache and availability history
+func (c *Client) providerKey(mediaType string, id int) string {
+	return fmt.Sprintf("%s-%d-%s", mediaType, id, c.watchRegion())
+}
+
+// watchRegion is the region provider lookups are for: the configured one, or
+// defaultWatchRegion
+func (c *Client) watchRegion() string {
+	if c.region == "" {
+		return defaultWatchRegion
 	}
+	return c.region
+}
 
-	// Get providers for the configured region
-	region := c.region
-	if region == "" {
-		region = "US"
+// RefreshWatchProviders drops the cached providers for a title and fetches them again
+func (c *Client) RefreshWatchProviders(mediaType string, id int) ([]Provider, string, error) {
+	if c.providers != nil {
+		c.providers.Delete(c.providerKey(mediaType, id))
+	}
+	return c.GetWatchProviders(mediaType, id)
+}
+
+// fetchWatchProviders requests a title's providers in region from TMDb
+func (c *Client) fetchWatchProviders(mediaType string, id int, region string) ([]Provider, string, error) {
+	regions, err := c.fetchProviderRegions(mediaType, id)
+	if err != nil {
+		return nil, "", err
 	}
 
-	countryProviders, ok := resp.Results[region]
+	countryProviders, ok := regions[region]
 	if !ok {
 		return nil, "", nil // No providers in this region
 	}
+	return canonicalProviders(countryProviders.all()), countryProviders.Link, nil
+}
 
-	// Combine all provider types, prioritizing flatrate (streaming)
+// fetchProviderRegions requests a title's providers in every region TMDb has
+// data for, keyed by ISO 3166-1 code
+func (c *Client) fetchProviderRegions(mediaType string, id int) (map[string]CountryProvider, error) {
+	endpoint := fmt.Sprintf("/%s/%d/watch/providers", mediaType, id)
+
+	// Provider names are matched against the English names and aliases in
+	// WatchProviderMap (badges, links, preferences.providers), so they're
+	// always requested in English whatever preferences.language is
+	params := url.Values{}
+	params.Set("language", providerNameLanguage)
+
+	data, err := c.get(endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp WatchProvidersResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse providers response: %w", err)
+	}
+	return resp.Results, nil
+}
+
+// all combines the country's providers of every type, prioritizing flatrate (streaming)
+func (cp CountryProvider) all() []Provider {
 	var providers []Provider
 	seen := make(map[int]bool)
 
-	addProviders := func(list []Provider) {
+	addProviders := func(list []Provider, monetization string) {
 		for _, p := range list {
 			if !seen[p.ID] {
 				seen[p.ID] = true
+				p.Type = monetization
 				providers = append(providers, p)
 			}
 		}
 	}
 
-	addProviders(countryProviders.Flatrate)
-	addProviders(countryProviders.Free)
-	addProviders(countryProviders.Rent)
-	addProviders(countryProviders.Buy)
+	addProviders(cp.Flatrate, MonetizationFlatrate)
+	addProviders(cp.Free, MonetizationFree)
+	addProviders(cp.Rent, MonetizationRent)
+	addProviders(cp.Buy, MonetizationBuy)
+	return providers
+}
+
+// RegionProviders is a title's availability in one region
+type RegionProviders struct {
+	Region    string     // ISO 3166-1 code
+	Providers []Provider // empty when it isn't available there
+	Link      string
+}
+
+// cheapestOrder ranks monetization types from cheapest to most expensive
+var cheapestOrder = []string{MonetizationFree, MonetizationFlatrate, MonetizationRent, MonetizationBuy}
+
+// Cheapest returns the cheapest way to watch in the region: free, flatrate
+// (with a subscription), rent, or buy ("" when it isn't available)
+func (r RegionProviders) Cheapest() string {
+	for _, t := range cheapestOrder {
+		for _, p := range r.Providers {
+			if p.Type == t {
+				return t
+			}
+		}
+	}
+	return ""
+}
+
+// GetWatchProvidersByRegion returns a title's providers in each of regions
+// (ISO 3166-1 codes), from a single request. Regions where it isn't available
+// are included with no providers. With no regions, every region TMDb has data
+// for is returned, sorted by code.
+func (c *Client) GetWatchProvidersByRegion(mediaType string, id int, regions []string) ([]RegionProviders, error) {
+	all, err := c.fetchProviderRegions(mediaType, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(regions) == 0 {
+		for region := range all {
+			regions = append(regions, region)
+		}
+		sort.Strings(regions)
+	}
+
+	result := make([]RegionProviders, 0, len(regions))
+	for _, region := range regions {
+		region = strings.ToUpper(region)
+		cp := all[region]
+		result = append(result, RegionProviders{
+			Region:    region,
+			Providers: canonicalProviders(cp.all()),
+			Link:      cp.Link,
+		})
+	}
+	return result, nil
+}
+
+// canonicalProviders maps rebranded providers to their current ID and name,
+// dropping duplicates when a title lists both the old and new ID
+func canonicalProviders(providers []Provider) []Provider {
+	var result []Provider
+	seen := make(map[int]bool)
+	for _, p := range providers {
+		p.ID = CanonicalProviderID(p.ID)
+		if name, ok := ProviderNameMap[p.ID]; ok {
+			p.Name = name
+		}
+		if !seen[p.ID] {
+			seen[p.ID] = true
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// CanonicalProviderID returns the current ID for a provider that has been rebranded
+func CanonicalProviderID(id int) int {
+	if current, ok := ProviderRebrandMap[id]; ok {
+		return current
+	}
+	return id
+}
 
-	return providers, countryProviders.Link, nil
+// ProviderID resolves a provider name or alias ("HBO Max", "Disney Plus") to its current TMDb ID
+func ProviderID(name string) (int, bool) {
+	id, ok := WatchProviderMap[NormalizeName(name)]
+	if !ok {
+		return 0, false
+	}
+	return CanonicalProviderID(id), true
 }
 
-// EnrichWithProviders adds streaming provider info to media items
+// CanonicalProviderName returns the current display name for a provider name
+// or alias ("HBO Max" -> "Max"), or name unchanged if it isn't a rebrand
+func CanonicalProviderName(name string) string {
+	if id, ok := ProviderID(name); ok {
+		if current, ok := ProviderNameMap[id]; ok {
+			return current
+		}
+	}
+	return name
+}
+
+// EnrichWithProviders adds streaming provider info to media items.
+// Lookups run in parallel (bounded by maxConcurrentLookups). Titles whose
+// lookup fails are retried, up to maxEnrichAttempts in all, so a transient
+// error doesn't leave a title without "where to watch"; the provider cache
+// means titles that succeeded are never refetched.
 func (c *Client) EnrichWithProviders(results []Media) {
+	pending := make([]int, len(results))
 	for i := range results {
-		mediaType := results[i].MediaType
-		if mediaType == "" {
-			// Try to determine from available data
-			if results[i].Title != "" {
-				mediaType = "movie"
-			} else {
-				mediaType = "tv"
+		pending[i] = i
+	}
+
+	for attempt := 1; len(pending) > 0 && attempt <= maxEnrichAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(time.Duration(attempt-1) * enrichRetryDelay)
+		}
+		pending = c.enrichPass(results, pending)
+	}
+}
+
+// enrichPass looks up providers for results[i] for each i in indexes and
+// returns the indexes whose lookup failed
+func (c *Client) enrichPass(results []Media, indexes []int) []int {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var failed []int
+	sem := make(chan struct{}, maxConcurrentLookups)
+
+	for _, i := range indexes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			// Trust the media type the result came with; only guess when it's missing
+			m := &results[i]
+			m.MediaType = m.GetMediaType()
+
+			providers, link, err := c.GetWatchProviders(m.MediaType, m.ID)
+			if err != nil {
+				mu.Lock()
+				failed = append(failed, i)
+				mu.Unlock()
+				return
 			}
+			m.Providers = providers
+			m.WatchLink = link
+		}(i)
+	}
+
+	wg.Wait()
+	return failed
+}
+
+// ProviderAbbrev returns a short badge label for a provider name, or "" if unknown
+func ProviderAbbrev(name string) string {
+	id, ok := ProviderID(name)
+	if !ok {
+		return ""
+	}
+	return ProviderAbbrevMap[id]
+}
+
+// ProviderLink returns a link that finds title on a provider's own site or
+// app, or "" when there's no URL template for the provider (see ProviderLinkMap)
+func ProviderLink(provider, title string) string {
+	id, ok := ProviderID(provider)
+	if !ok {
+		return ""
+	}
+	template, ok := ProviderLinkMap[id]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf(template, url.QueryEscape(title))
+}
+
+// RotateProviders is the preferences.primary_provider value that spreads
+// results across services instead of favoring one
+const RotateProviders = "rotate"
+
+// RankByProviderPreference reorders enriched results for
+// preferences.primary_provider. Set to a service, titles on it come first
+// (otherwise in their existing order) and it leads each title's provider list.
+// Set to "rotate", titles are interleaved so each service gets used in turn,
+// the least-used first. Results without provider data keep their positions.
+func (c *Client) RankByProviderPreference(results []Media) {
+	switch {
+	case c.primaryProvider == "":
+		return
+	case strings.EqualFold(c.primaryProvider, RotateProviders):
+		c.spreadAcrossProviders(results)
+	default:
+		primary, ok := ProviderID(c.primaryProvider)
+		if !ok {
+			return
+		}
+		for i := range results {
+			sort.SliceStable(results[i].Providers, func(a, b int) bool {
+				return results[i].Providers[a].ID == primary && results[i].Providers[b].ID != primary
+			})
+		}
+		sort.SliceStable(results, func(a, b int) bool {
+			return hasProvider(results[a], primary) && !hasProvider(results[b], primary)
+		})
+	}
+}
+
+// spreadAcrossProviders reorders the results that have providers so that each
+// next title is on the service used least so far. Only streaming offers
+// (subscription or free) count, and only on the user's own services
+// (preferences.providers) when they're set.
+func (c *Client) spreadAcrossProviders(results []Media) {
+	mine := make(map[int]bool)
+	for _, name := range c.myProviders {
+		if id, ok := ProviderID(name); ok {
+			mine[id] = true
+		}
+	}
+	services := func(m Media) []int {
+		var ids []int
+		for _, p := range m.Providers {
+			if p.Type != MonetizationFlatrate && p.Type != MonetizationFree {
+				continue
+			}
+			if len(mine) == 0 || mine[p.ID] {
+				ids = append(ids, p.ID)
+			}
+		}
+		return ids
+	}
+
+	var slots []int // positions of the titles being reordered
+	var pending []Media
+	for i, m := range results {
+		if len(services(m)) > 0 {
+			slots = append(slots, i)
+			pending = append(pending, m)
 		}
+	}
 
-		providers, _, err := c.GetWatchProviders(mediaType, results[i].ID)
-		if err == nil {
-			results[i].Providers = providers
+	used := make(map[int]int)
+	for _, slot := range slots {
+		// The earliest title whose least-used service is used least overall
+		best, bestService, bestUses := 0, 0, -1
+		for i, m := range pending {
+			for _, id := range services(m) {
+				if bestUses < 0 || used[id] < bestUses {
+					best, bestService, bestUses = i, id, used[id]
+				}
+			}
 		}
+		used[bestService]++
+		results[slot] = pending[best]
+		pending = append(pending[:best], pending[best+1:]...)
 	}
 }
 
-// ProviderEmoji returns an emoji for common streaming providers
-func ProviderEmoji(name string) string {
-	switch name {
-	case "Netflix":
-		return "N"
-	case "Amazon Prime Video", "Prime Video":
-		return "P"
-	case "Disney Plus":
-		return "D+"
-	case "Hulu":
-		return "H"
-	case "HBO Max", "Max":
-		return "M"
-	case "Apple TV Plus", "Apple TV+":
-		return "A+"
-	case "Peacock", "Peacock Premium":
-		return "Pk"
-	case "Paramount Plus", "Paramount+":
-		return "P+"
-	case "Crunchyroll":
-		return "CR"
-	default:
-		return ""
+// hasProvider reports whether m is available on the provider with this ID
+func hasProvider(m Media, id int) bool {
+	for _, p := range m.Providers {
+		if p.ID == id {
+			return true
+		}
 	}
+	return false
 }
@@ -1,20 +1,44 @@
 package tmdb
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"math/rand"
 	"net/url"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 )
 
+// defaultWatchRegion is used for provider filters when no region is configured
+const defaultWatchRegion = "US"
+
 // Search performs a multi-search for movies and TV shows
 func (c *Client) Search(query string) (*SearchResponse, error) {
+	return c.search(context.Background(), query, true)
+}
+
+// SearchAsYouType is Search for autocomplete: the lookup can be abandoned
+// when the next keystroke supersedes it, and its responses aren't written to
+// the response cache, which would otherwise fill up with every partial query
+func (c *Client) SearchAsYouType(ctx context.Context, query string) (*SearchResponse, error) {
+	return c.search(ctx, query, false)
+}
+
+// search runs a multi-search, through the response cache when cached is set
+func (c *Client) search(ctx context.Context, query string, cached bool) (*SearchResponse, error) {
 	params := url.Values{}
 	params.Set("query", query)
-	params.Set("include_adult", "false")
+	params.Set("include_adult", c.adultParam())
 
-	data, err := c.get("/search/multi", params)
+	get := c.getContext
+	if cached {
+		get = c.getCachedContext
+	}
+	data, err := get(ctx, "/search/multi", params)
 	if err != nil {
 		return nil, err
 	}
@@ -27,9 +51,19 @@ func (c *Client) Search(query string) (*SearchResponse, error) {
 	// Filter to only movies and TV shows
 	filtered := make([]Media, 0)
 	for _, m := range resp.Results {
-		if m.MediaType == "movie" || m.MediaType == "tv" {
-			filtered = append(filtered, m)
+		if m.MediaType != "movie" && m.MediaType != "tv" {
+			continue
+		}
+		if m.Adult && !c.includeAdult {
+			continue
 		}
+		if c.kidsMode && hasKidsExcludedGenre(m) {
+			continue
+		}
+		if c.isBlocked(m) {
+			continue
+		}
+		filtered = append(filtered, m)
 	}
 	resp.Results = filtered
 
@@ -40,6 +74,42 @@ func (c *Client) Search(query string) (*SearchResponse, error) {
 func (c *Client) Discover(searchParams *SearchParams) (*SearchResponse, error) {
 	var allResults []Media
 
+	if c.kidsMode {
+		kidsParams := *searchParams
+		ApplyKidsPreset(&kidsParams)
+		searchParams = &kidsParams
+	}
+	if searchParams.Quality != "" {
+		qualityParams := *searchParams
+		ApplyQualityPreset(&qualityParams, searchParams.Quality)
+		searchParams = &qualityParams
+	}
+	if len(c.blockedGenres) > 0 {
+		blockedParams := *searchParams
+		applyBlockedGenres(&blockedParams, c.blockedGenres)
+		searchParams = &blockedParams
+	}
+
+	// Search the user's own services unless the query names providers
+	if len(searchParams.WatchProviders) == 0 && len(c.myProviders) > 0 && !searchParams.AllProviders {
+		providerParams := *searchParams
+		providerParams.WatchProviders = c.myProviders
+		providerParams.ProviderMatch = ProviderMatchAny // on any of them, always
+		searchParams = &providerParams
+	}
+	if searchParams.MinPopularity == 0 && c.minPopularity > 0 {
+		popularityParams := *searchParams
+		popularityParams.MinPopularity = c.minPopularity
+		searchParams = &popularityParams
+	}
+
+	// Networks only exist for TV, so a network search is a TV search
+	if len(searchParams.Networks) > 0 && searchParams.MediaType != "movie" {
+		tvParams := *searchParams
+		tvParams.MediaType = "tv"
+		searchParams = &tvParams
+	}
+
 	// Determine which endpoints to query
 	endpoints := []string{}
 	switch searchParams.MediaType {
@@ -53,7 +123,7 @@ func (c *Client) Discover(searchParams *SearchParams) (*SearchResponse, error) {
 
 	for _, endpoint := range endpoints {
 		params := c.buildDiscoverParams(searchParams, endpoint)
-		data, err := c.get(endpoint, params)
+		data, err := c.getCached(endpoint, params)
 		if err != nil {
 			continue // Try other endpoints on error
 		}
@@ -75,26 +145,44 @@ func (c *Client) Discover(searchParams *SearchParams) (*SearchResponse, error) {
 		allResults = append(allResults, resp.Results...)
 	}
 
+	// Similar-title and keyword searches can't filter by certification, so in
+	// kids mode only the (certified) discover results are used
+	mergeUncertified := !c.kidsMode
+
 	// If we have similar_to references, also search for those
-	if len(searchParams.SimilarTo) > 0 {
+	if mergeUncertified && len(searchParams.SimilarTo) > 0 {
 		similarResults := c.findSimilar(searchParams.SimilarTo, searchParams.MediaType)
 		allResults = append(allResults, similarResults...)
 	}
 
 	// If we have keywords, also do a keyword search
-	if len(searchParams.Keywords) > 0 {
+	if mergeUncertified && len(searchParams.Keywords) > 0 {
 		keywordQuery := strings.Join(searchParams.Keywords, " ")
 		searchResp, err := c.Search(keywordQuery)
 		if err == nil {
-			allResults = append(allResults, searchResp.Results...)
+			// /search/multi mixes movies and TV, so only keep the requested type
+			for _, m := range searchResp.Results {
+				if searchParams.MediaType == "movie" || searchParams.MediaType == "tv" {
+					if m.MediaType != searchParams.MediaType {
+						continue
+					}
+				}
+				allResults = append(allResults, m)
+			}
 		}
 	}
 
-	// Deduplicate and sort by relevance (vote_average * log(vote_count))
-	allResults = deduplicateAndSort(allResults, searchParams.MinRating)
+	// Deduplicate and sort by the requested order, or by relevance
+	allResults = deduplicateAndSort(c.removeBlocked(allResults), searchParams, resolveSortBy(searchParams.SortBy))
 
 	// Limit results
-	maxResults := 10
+	maxResults := c.maxResults
+	if searchParams.Limit > 0 {
+		maxResults = searchParams.Limit
+	}
+	if searchParams.Diverse || c.diverse {
+		allResults = c.diversify(allResults, maxResults)
+	}
 	if len(allResults) > maxResults {
 		allResults = allResults[:maxResults]
 	}
@@ -107,16 +195,32 @@ func (c *Client) Discover(searchParams *SearchParams) (*SearchResponse, error) {
 	}, nil
 }
 
+// Provider match modes for SearchParams.ProviderMatch
+const (
+	ProviderMatchAny = "any" // on at least one of the providers
+	ProviderMatchAll = "all" // on every one of the providers at once
+)
+
 func (c *Client) buildDiscoverParams(sp *SearchParams, endpoint string) url.Values {
 	params := url.Values{}
 	isMovie := strings.Contains(endpoint, "/movie")
+	params.Set("include_adult", c.adultParam())
 
 	// Sorting
 	sortBy := "vote_average.desc" // default
-	if sp.SortBy != "" {
-		if mapped, ok := SortByMap[strings.ToLower(sp.SortBy)]; ok {
-			sortBy = mapped
-		}
+	if mapped := resolveSortBy(sp.SortBy); mapped != "" {
+		sortBy = mapped
+	}
+	if sortBy == sortRandom {
+		sortBy = "popularity.desc" // shuffled after fetching
+	}
+	// Discover has no popularity filter, so fetch the most popular matches and
+	// apply the floor afterwards (see deduplicateAndSort)
+	if sp.MinPopularity > 0 && sp.SortBy == "" {
+		sortBy = "popularity.desc"
+	}
+	if !isMovie {
+		sortBy = tvSortBy(sortBy)
 	}
 	params.Set("sort_by", sortBy)
 
@@ -126,12 +230,15 @@ func (c *Client) buildDiscoverParams(sp *SearchParams, endpoint string) url.Valu
 		minVotes = sp.MinVoteCount
 	}
 	params.Set("vote_count.gte", strconv.Itoa(minVotes))
+	if sp.MaxVoteCount > 0 {
+		params.Set("vote_count.lte", strconv.Itoa(sp.MaxVoteCount))
+	}
 
 	// Genre filtering
 	if len(sp.Genres) > 0 {
 		genreIDs := []string{}
 		for _, genre := range sp.Genres {
-			if id, ok := GenreMap[strings.ToLower(genre)]; ok {
+			if id, ok := GenreMap[NormalizeName(genre)]; ok {
 				genreIDs = append(genreIDs, strconv.Itoa(id))
 			}
 		}
@@ -139,20 +246,35 @@ func (c *Client) buildDiscoverParams(sp *SearchParams, endpoint string) url.Valu
 			params.Set("with_genres", strings.Join(genreIDs, ","))
 		}
 	}
+	if len(sp.ExcludeGenres) > 0 {
+		genreIDs := []string{}
+		for _, genre := range sp.ExcludeGenres {
+			if id, ok := GenreMap[NormalizeName(genre)]; ok {
+				genreIDs = append(genreIDs, strconv.Itoa(id))
+			}
+		}
+		if len(genreIDs) > 0 {
+			params.Set("without_genres", strings.Join(genreIDs, ","))
+		}
+	}
 
 	// Year filtering
-	if sp.YearFrom > 0 {
+	yearFrom, yearTo := sp.YearFrom, sp.YearTo
+	if sp.Year > 0 {
+		yearFrom, yearTo = sp.Year, sp.Year
+	}
+	if yearFrom > 0 {
 		if isMovie {
-			params.Set("primary_release_date.gte", fmt.Sprintf("%d-01-01", sp.YearFrom))
+			params.Set("primary_release_date.gte", fmt.Sprintf("%d-01-01", yearFrom))
 		} else {
-			params.Set("first_air_date.gte", fmt.Sprintf("%d-01-01", sp.YearFrom))
+			params.Set("first_air_date.gte", fmt.Sprintf("%d-01-01", yearFrom))
 		}
 	}
-	if sp.YearTo > 0 {
+	if yearTo > 0 {
 		if isMovie {
-			params.Set("primary_release_date.lte", fmt.Sprintf("%d-12-31", sp.YearTo))
+			params.Set("primary_release_date.lte", fmt.Sprintf("%d-12-31", yearTo))
 		} else {
-			params.Set("first_air_date.lte", fmt.Sprintf("%d-12-31", sp.YearTo))
+			params.Set("first_air_date.lte", fmt.Sprintf("%d-12-31", yearTo))
 		}
 	}
 
@@ -175,7 +297,7 @@ func (c *Client) buildDiscoverParams(sp *SearchParams, endpoint string) url.Valu
 	if len(sp.Studios) > 0 {
 		companyIDs := []string{}
 		for _, studio := range sp.Studios {
-			if id, ok := StudioMap[strings.ToLower(studio)]; ok {
+			if id, ok := StudioMap[NormalizeName(studio)]; ok {
 				companyIDs = append(companyIDs, strconv.Itoa(id))
 			}
 		}
@@ -184,6 +306,19 @@ func (c *Client) buildDiscoverParams(sp *SearchParams, endpoint string) url.Valu
 		}
 	}
 
+	// Network filtering (TV only)
+	if len(sp.Networks) > 0 && !isMovie {
+		networkIDs := []string{}
+		for _, network := range sp.Networks {
+			if id, ok := NetworkMap[NormalizeName(network)]; ok {
+				networkIDs = append(networkIDs, strconv.Itoa(id))
+			}
+		}
+		if len(networkIDs) > 0 {
+			params.Set("with_networks", strings.Join(networkIDs, "|")) // OR logic
+		}
+	}
+
 	// Actor/People filtering
 	if len(sp.Actors) > 0 || len(sp.Directors) > 0 {
 		peopleIDs := []string{}
@@ -206,44 +341,79 @@ func (c *Client) buildDiscoverParams(sp *SearchParams, endpoint string) url.Valu
 	if len(sp.WatchProviders) > 0 {
 		providerIDs := []string{}
 		for _, provider := range sp.WatchProviders {
-			if id, ok := WatchProviderMap[strings.ToLower(provider)]; ok {
+			if id, ok := ProviderID(provider); ok {
 				providerIDs = append(providerIDs, strconv.Itoa(id))
 			}
 		}
 		if len(providerIDs) > 0 {
-			params.Set("with_watch_providers", strings.Join(providerIDs, "|")) // OR logic
+			// TMDb joins IDs with | for any of them, and with , for all of them
+			sep := "|"
+			if strings.EqualFold(sp.ProviderMatch, ProviderMatchAll) {
+				sep = ","
+			}
+			params.Set("with_watch_providers", strings.Join(providerIDs, sep))
 		}
 	}
 
-	// Monetization type
-	if sp.MonetizationType != "" {
-		if mapped, ok := MonetizationTypeMap[strings.ToLower(sp.MonetizationType)]; ok {
-			params.Set("with_watch_monetization_types", mapped)
+	// Monetization types
+	if len(sp.MonetizationTypes) > 0 {
+		types := []string{}
+		seen := make(map[string]bool)
+		for _, t := range sp.MonetizationTypes {
+			if mapped, ok := MonetizationTypeMap[NormalizeName(t)]; ok && !seen[mapped] {
+				seen[mapped] = true
+				types = append(types, mapped)
+			}
+		}
+		if len(types) > 0 {
+			params.Set("with_watch_monetization_types", strings.Join(types, "|")) // OR logic
 		}
 	}
 
 	// Certification filtering
 	if sp.Certification != "" {
-		cert := strings.ToUpper(sp.Certification)
-		if mapped, ok := CertificationMap[strings.ToLower(sp.Certification)]; ok {
+		cert := strings.ToUpper(NormalizeName(sp.Certification))
+		if mapped, ok := CertificationMap[NormalizeName(sp.Certification)]; ok {
 			cert = mapped
 		}
 		params.Set("certification_country", "US")
 		params.Set("certification", cert)
 	}
+	if sp.MaxCertification != "" {
+		cert := strings.ToUpper(NormalizeName(sp.MaxCertification))
+		if mapped, ok := CertificationMap[NormalizeName(sp.MaxCertification)]; ok {
+			cert = mapped
+		}
+		if !isMovie {
+			if tvCert, ok := TVCertificationMap[cert]; ok {
+				cert = tvCert
+			}
+		}
+		params.Set("certification_country", "US")
+		params.Set("certification.lte", cert)
+	}
 
 	// TV Status filtering
 	if sp.TVStatus != "" && !isMovie {
-		if status, ok := TVStatusMap[strings.ToLower(sp.TVStatus)]; ok {
+		if status, ok := TVStatusMap[NormalizeName(sp.TVStatus)]; ok {
 			params.Set("with_status", strconv.Itoa(status))
 		}
 	}
 
-	// Region for watch providers
+	// Region for watch providers. TMDb ignores with_watch_providers and
+	// with_watch_monetization_types entirely unless watch_region is set.
 	region := c.region
 	if sp.AvailableInRegion != "" {
 		region = sp.AvailableInRegion
 	}
+	if region == "" && (params.Has("with_watch_providers") || params.Has("with_watch_monetization_types")) {
+		region = defaultWatchRegion
+		if c.diagnostics != nil {
+			c.regionWarning.Do(func() {
+				fmt.Fprintf(c.diagnostics, "Warning: provider filters need a region; using %s (set one with: wtfsiw config set preferences.region GB)\n", region)
+			})
+		}
+	}
 	if region != "" {
 		params.Set("watch_region", region)
 	}
@@ -251,10 +421,52 @@ func (c *Client) buildDiscoverParams(sp *SearchParams, endpoint string) url.Valu
 	return params
 }
 
+// FillMissingOverviews fetches English overviews for results whose localized
+// overview is empty. Only applies when a non-English language is configured.
+// It costs a request per result missing one, so call it on the results being
+// shown, not on everything a search returned.
+func (c *Client) FillMissingOverviews(results []Media) {
+	if c.language == "" || strings.HasPrefix(strings.ToLower(c.language), "en") {
+		return
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentLookups)
+
+	for i := range results {
+		if results[i].Overview != "" || (results[i].MediaType != "movie" && results[i].MediaType != "tv") {
+			continue
+		}
+		wg.Add(1)
+		go func(m *Media) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			params := url.Values{}
+			params.Set("language", "en-US")
+			data, err := c.get(fmt.Sprintf("/%s/%d", m.MediaType, m.ID), params)
+			if err != nil {
+				return
+			}
+
+			var details struct {
+				Overview string `json:"overview"`
+			}
+			if err := json.Unmarshal(data, &details); err == nil {
+				m.Overview = details.Overview
+			}
+		}(&results[i])
+	}
+
+	wg.Wait()
+}
+
 // searchPersonID searches for a person by name and returns their TMDb ID
 func (c *Client) searchPersonID(name string) int {
 	params := url.Values{}
 	params.Set("query", name)
+	params.Set("include_adult", c.adultParam())
 
 	data, err := c.get("/search/person", params)
 	if err != nil {
@@ -274,6 +486,44 @@ func (c *Client) searchPersonID(name string) int {
 	return resp.Results[0].ID
 }
 
+// GetSimilar returns titles similar to the given movie or TV show
+func (c *Client) GetSimilar(mediaType string, id int) (*SearchResponse, error) {
+	if mediaType != "movie" && mediaType != "tv" {
+		return nil, fmt.Errorf("invalid media type: %s", mediaType)
+	}
+
+	endpoint := fmt.Sprintf("/%s/%d/similar", mediaType, id)
+	data, err := c.get(endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.parseSearchResponse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Similar results are always the same media type as the source.
+	// The endpoint has no include_adult parameter, so filter here.
+	filtered := make([]Media, 0, len(resp.Results))
+	for _, m := range resp.Results {
+		if m.Adult && !c.includeAdult {
+			continue
+		}
+		if c.kidsMode && hasKidsExcludedGenre(m) {
+			continue
+		}
+		if c.isBlocked(m) {
+			continue
+		}
+		m.MediaType = mediaType
+		filtered = append(filtered, m)
+	}
+	resp.Results = filtered
+
+	return resp, nil
+}
+
 func (c *Client) findSimilar(titles []string, mediaType string) []Media {
 	var results []Media
 
@@ -284,66 +534,163 @@ func (c *Client) findSimilar(titles []string, mediaType string) []Media {
 			continue
 		}
 
-		// Get the first result's ID
+		// Fetch similar titles for the first result
 		first := searchResp.Results[0]
-
-		// Fetch similar titles
-		var endpoint string
-		if first.MediaType == "movie" {
-			endpoint = fmt.Sprintf("/movie/%d/similar", first.ID)
-		} else if first.MediaType == "tv" {
-			endpoint = fmt.Sprintf("/tv/%d/similar", first.ID)
-		} else {
-			continue
-		}
-
-		data, err := c.get(endpoint, nil)
+		resp, err := c.GetSimilar(first.MediaType, first.ID)
 		if err != nil {
 			continue
 		}
 
-		resp, err := c.parseSearchResponse(data)
-		if err != nil {
-			continue
-		}
-
-		// Set media type
-		for i := range resp.Results {
-			resp.Results[i].MediaType = first.MediaType
-		}
-
 		results = append(results, resp.Results...)
 	}
 
 	return results
 }
 
-func deduplicateAndSort(results []Media, minRating float64) []Media {
-	seen := make(map[string]bool)
-	unique := make([]Media, 0)
+// resolveSortBy maps a user-friendly sort name to a TMDb sort value ("" if unknown)
+func resolveSortBy(name string) string {
+	if name == "" {
+		return ""
+	}
+	return SortByMap[NormalizeName(name)]
+}
 
+// tvSortBy translates a movie sort value to its /discover/tv equivalent
+func tvSortBy(sortBy string) string {
+	field, dir, _ := strings.Cut(sortBy, ".")
+	switch field {
+	case "primary_release_date":
+		field = "first_air_date"
+	case "title":
+		field = "name"
+	case "revenue":
+		field = "popularity" // TV has no revenue
+	}
+	return field + "." + dir
+}
+
+// deduplicateAndSort merges duplicates and removes results outside sp's rating,
+// popularity and vote-count bounds, then orders them by sortBy (a SortByMap
+// value). Results come from several endpoints, and keyword and similar-title
+// searches don't apply Discover's filters, so both the filters and TMDb's own
+// ordering have to be re-applied across the merged list.
+func deduplicateAndSort(results []Media, sp *SearchParams, sortBy string) []Media {
+	index := make(map[string]int)
+	merged := make([]Media, 0, len(results))
 	for _, r := range results {
 		key := fmt.Sprintf("%s-%d", r.MediaType, r.ID)
-		if seen[key] {
+		if i, ok := index[key]; ok {
+			merged[i] = mergeDuplicate(merged[i], r)
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, r)
+	}
+
+	unique := make([]Media, 0, len(merged))
+	for _, r := range merged {
+		if sp.MinRating > 0 && r.VoteAverage < sp.MinRating {
+			continue
+		}
+		if sp.MinPopularity > 0 && r.Popularity < sp.MinPopularity {
+			continue
+		}
+		if sp.MinVoteCount > 0 && r.VoteCount < sp.MinVoteCount {
 			continue
 		}
-		if minRating > 0 && r.VoteAverage < minRating {
+		if sp.MaxVoteCount > 0 && r.VoteCount > sp.MaxVoteCount {
 			continue
 		}
-		seen[key] = true
 		unique = append(unique, r)
 	}
 
-	// Sort by score (vote_average weighted by popularity)
-	for i := 0; i < len(unique)-1; i++ {
-		for j := i + 1; j < len(unique); j++ {
+	switch sortBy {
+	case "":
+		// Sort by score (vote_average weighted by popularity)
+		sort.SliceStable(unique, func(i, j int) bool {
 			scoreI := unique[i].VoteAverage * (1 + unique[i].Popularity/100)
 			scoreJ := unique[j].VoteAverage * (1 + unique[j].Popularity/100)
-			if scoreJ > scoreI {
-				unique[i], unique[j] = unique[j], unique[i]
-			}
-		}
+			return scoreI > scoreJ
+		})
+	case sortRandom:
+		rand.Shuffle(len(unique), func(i, j int) {
+			unique[i], unique[j] = unique[j], unique[i]
+		})
+	default:
+		field, dir, _ := strings.Cut(sortBy, ".")
+		sort.SliceStable(unique, func(i, j int) bool {
+			return lessBy(unique[i], unique[j], field, dir == "asc")
+		})
 	}
 
 	return unique
 }
+
+// mergeDuplicate combines two copies of a title from different endpoints. The
+// copy with more votes has the more current scores, so it's kept; fields it's
+// missing are filled from the other, and their provider lists are combined.
+func mergeDuplicate(a, b Media) Media {
+	if b.VoteCount > a.VoteCount {
+		a, b = b, a
+	}
+	if a.Overview == "" {
+		a.Overview = b.Overview
+	}
+	if a.PosterPath == "" {
+		a.PosterPath = b.PosterPath
+	}
+	if a.BackdropPath == "" {
+		a.BackdropPath = b.BackdropPath
+	}
+	if len(a.GenreIDs) == 0 {
+		a.GenreIDs = b.GenreIDs
+	}
+	if a.Runtime == 0 {
+		a.Runtime = b.Runtime
+	}
+	if a.WatchLink == "" {
+		a.WatchLink = b.WatchLink
+	}
+	for _, p := range b.Providers {
+		if !slices.ContainsFunc(a.Providers, func(q Provider) bool { return q.ID == p.ID && q.Type == p.Type }) {
+			a.Providers = append(a.Providers, p)
+		}
+	}
+	return a
+}
+
+// lessBy reports whether a sorts before b on a TMDb sort field.
+// Titles without a date always sort last.
+func lessBy(a, b Media, field string, asc bool) bool {
+	switch field {
+	case "primary_release_date", "first_air_date":
+		dateA, dateB := a.ReleaseDate+a.FirstAirDate, b.ReleaseDate+b.FirstAirDate
+		if dateA == "" || dateB == "" {
+			return dateB == "" && dateA != ""
+		}
+		if asc {
+			return dateA < dateB
+		}
+		return dateA > dateB
+	case "title", "name":
+		titleA, titleB := strings.ToLower(a.GetDisplayTitle()), strings.ToLower(b.GetDisplayTitle())
+		if asc {
+			return titleA < titleB
+		}
+		return titleA > titleB
+	}
+
+	var valA, valB float64
+	switch field {
+	case "vote_average":
+		valA, valB = a.VoteAverage, b.VoteAverage
+	case "vote_count":
+		valA, valB = float64(a.VoteCount), float64(b.VoteCount)
+	default: // popularity (revenue isn't in discover results)
+		valA, valB = a.Popularity, b.Popularity
+	}
+	if asc {
+		return valA < valB
+	}
+	return valA > valB
+}
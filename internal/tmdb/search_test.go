@@ -0,0 +1,39 @@
+package tmdb
+
+import "testing"
+
+func TestDeduplicateAndSortVoteCounts(t *testing.T) {
+	sp := &SearchParams{}
+	ApplyQualityPreset(sp, "hidden-gem")
+
+	// As merged from discover, keyword and similar-title searches, which
+	// don't all apply the preset's vote bounds
+	results := []Media{
+		{ID: 1, MediaType: "movie", Title: "Gem", VoteAverage: 8, VoteCount: 400},
+		{ID: 2, MediaType: "movie", Title: "Blockbuster", VoteAverage: 8.5, VoteCount: 30000},
+		{ID: 3, MediaType: "movie", Title: "Unseen", VoteAverage: 9, VoteCount: 12},
+		{ID: 4, MediaType: "tv", Name: "Cult Show", VoteAverage: 7.8, VoteCount: 1000},
+		{ID: 1, MediaType: "movie", Title: "Gem", VoteAverage: 8, VoteCount: 400},
+	}
+
+	got := deduplicateAndSort(results, sp, "")
+	want := map[string]bool{"Gem": true, "Cult Show": true}
+	if len(got) != len(want) {
+		t.Fatalf("deduplicateAndSort kept %d results, want %d: %v", len(got), len(want), got)
+	}
+	for _, m := range got {
+		if !want[m.GetDisplayTitle()] {
+			t.Errorf("deduplicateAndSort kept %q (%d votes), outside the hidden-gem bounds", m.GetDisplayTitle(), m.VoteCount)
+		}
+	}
+}
+
+func TestDeduplicateAndSortNoVoteBounds(t *testing.T) {
+	results := []Media{
+		{ID: 1, MediaType: "movie", Title: "Few Votes", VoteAverage: 7, VoteCount: 3},
+		{ID: 2, MediaType: "movie", Title: "Many Votes", VoteAverage: 7, VoteCount: 50000},
+	}
+	if got := deduplicateAndSort(results, &SearchParams{}, ""); len(got) != 2 {
+		t.Errorf("deduplicateAndSort without bounds kept %d results, want 2", len(got))
+	}
+}
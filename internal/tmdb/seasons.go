@@ -0,0 +1,45 @@
+package tmdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Season is one season of a TV show and its episodes
+type Season struct {
+	Name         string    `json:"name"`
+	Overview     string    `json:"overview"`
+	AirDate      string    `json:"air_date"`
+	SeasonNumber int       `json:"season_number"`
+	Episodes     []Episode `json:"episodes"`
+}
+
+// Episode is one episode in a Season
+type Episode struct {
+	EpisodeNumber int     `json:"episode_number"`
+	Name          string  `json:"name"`
+	Overview      string  `json:"overview"`
+	AirDate       string  `json:"air_date"`
+	Runtime       int     `json:"runtime"` // minutes, 0 if unknown
+	VoteAverage   float64 `json:"vote_average"`
+	VoteCount     int     `json:"vote_count"`
+}
+
+// GetSeason fetches a TV show's season (0 is specials on most shows)
+func (c *Client) GetSeason(tvID, seasonNumber int) (*Season, error) {
+	data, err := c.get(fmt.Sprintf("/tv/%d/season/%d", tvID, seasonNumber), nil)
+	if err != nil {
+		if strings.Contains(err.Error(), fmt.Sprintf("status %d", http.StatusNotFound)) {
+			return nil, fmt.Errorf("show %d has no season %d on TMDb", tvID, seasonNumber)
+		}
+		return nil, err
+	}
+
+	var season Season
+	if err := json.Unmarshal(data, &season); err != nil {
+		return nil, fmt.Errorf("failed to parse season response: %w", err)
+	}
+	return &season, nil
+}
@@ -0,0 +1,91 @@
+package trakt
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// HistoryItem represents a single play in the user's watch history
+type HistoryItem struct {
+	ID        int64    `json:"id"`
+	WatchedAt string   `json:"watched_at"`
+	Action    string   `json:"action"` // scrobble, checkin, watch
+	Type      string   `json:"type"`   // movie or episode
+	Movie     *Movie   `json:"movie,omitempty"`
+	Show      *Show    `json:"show,omitempty"`
+	Episode   *Episode `json:"episode,omitempty"`
+}
+
+// Episode represents a TV episode in Trakt
+type Episode struct {
+	Season int    `json:"season"`
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	IDs    IDs    `json:"ids"`
+}
+
+// GetDisplayTitle returns the movie or show title of the history item
+func (h *HistoryItem) GetDisplayTitle() string {
+	if h.Movie != nil {
+		return h.Movie.Title
+	}
+	if h.Show != nil {
+		return h.Show.Title
+	}
+	return ""
+}
+
+// GetDisplayYear returns the year of the movie or show
+func (h *HistoryItem) GetDisplayYear() int {
+	if h.Movie != nil {
+		return h.Movie.Year
+	}
+	if h.Show != nil {
+		return h.Show.Year
+	}
+	return 0
+}
+
+// GetTMDBID returns the TMDb ID of the movie or show (not the episode)
+func (h *HistoryItem) GetTMDBID() int {
+	if h.Movie != nil {
+		return h.Movie.IDs.TMDB
+	}
+	if h.Show != nil {
+		return h.Show.IDs.TMDB
+	}
+	return 0
+}
+
+// GetTMDBMediaType returns the TMDb media type ("movie" or "tv")
+func (h *HistoryItem) GetTMDBMediaType() string {
+	if h.Movie != nil {
+		return "movie"
+	}
+	return "tv"
+}
+
+// GetHistory returns the user's most recently watched items
+// mediaType can be "movies", "shows", or empty for all items
+func (c *Client) GetHistory(mediaType string, limit int) ([]HistoryItem, error) {
+	endpoint := "/users/me/history"
+	if mediaType != "" {
+		endpoint += "/" + mediaType
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	endpoint += fmt.Sprintf("?limit=%d&extended=full", limit)
+
+	data, err := c.get(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history: %w", err)
+	}
+
+	var items []HistoryItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("failed to parse history: %w", err)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,100 @@
+package trakt
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ShowProgress is the user's watched progress through a show
+type ShowProgress struct {
+	Aired         int      `json:"aired"`     // episodes aired so far
+	Completed     int      `json:"completed"` // of those, episodes watched
+	LastWatchedAt string   `json:"last_watched_at"`
+	NextEpisode   *Episode `json:"next_episode"` // nil when caught up
+	LastEpisode   *Episode `json:"last_episode"`
+}
+
+// Label formats an episode as "S02E05 Title"
+func (e *Episode) Label() string {
+	label := fmt.Sprintf("S%02dE%02d", e.Season, e.Number)
+	if e.Title != "" {
+		label += " " + e.Title
+	}
+	return label
+}
+
+// GetShowProgress returns the user's progress through a show, by Trakt ID
+func (c *Client) GetShowProgress(traktID int) (*ShowProgress, error) {
+	data, err := c.get("/shows/" + strconv.Itoa(traktID) + "/progress/watched")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get show progress: %w", err)
+	}
+
+	var progress ShowProgress
+	if err := json.Unmarshal(data, &progress); err != nil {
+		return nil, fmt.Errorf("failed to parse show progress: %w", err)
+	}
+	return &progress, nil
+}
+
+// historyShowLimit is how many recent plays FindShow searches for a title
+const historyShowLimit = 100
+
+// FindShow finds a show the user has been watching (from their history) or
+// plans to watch (from their watchlist) by title. An empty title returns the
+// most recently watched show.
+func (c *Client) FindShow(title string) (*Show, error) {
+	history, err := c.GetHistory("shows", historyShowLimit)
+	if err != nil {
+		return nil, err
+	}
+	var shows []*Show
+	for _, item := range history {
+		if item.Show != nil {
+			shows = append(shows, item.Show)
+		}
+	}
+	if title == "" {
+		if len(shows) == 0 {
+			return nil, fmt.Errorf("no shows in your Trakt history yet")
+		}
+		return shows[0], nil
+	}
+	if show := matchShow(shows, title); show != nil {
+		return show, nil
+	}
+
+	watchlist, err := c.GetWatchlist("shows")
+	if err != nil {
+		return nil, err
+	}
+	shows = shows[:0]
+	for _, item := range watchlist {
+		if item.Show != nil {
+			shows = append(shows, item.Show)
+		}
+	}
+	if show := matchShow(shows, title); show != nil {
+		return show, nil
+	}
+	return nil, fmt.Errorf("%q isn't in your Trakt history or watchlist", title)
+}
+
+// matchShow returns the first show titled exactly title, ignoring case, or
+// failing that the first whose title contains it
+func matchShow(shows []*Show, title string) *Show {
+	want := strings.ToLower(strings.TrimSpace(title))
+	for _, show := range shows {
+		if strings.ToLower(show.Title) == want {
+			return show
+		}
+	}
+	for _, show := range shows {
+		if strings.Contains(strings.ToLower(show.Title), want) {
+			return show
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package trakt
+
+import "sort"
+
+// WatchlistStats summarizes a watchlist
+type WatchlistStats struct {
+	Total          int
+	Movies         int
+	Shows          int
+	RuntimeMinutes int            // movie runtimes plus, for shows, episode runtime × aired episodes
+	AverageRating  float64        // over items that have a rating (0 if none do)
+	Genres         []GenreCount   // most common first
+	Oldest         *WatchlistItem // nil if no item has a year
+	Newest         *WatchlistItem
+}
+
+// GenreCount is the number of watchlist items in a genre
+type GenreCount struct {
+	Genre string
+	Count int
+}
+
+// ComputeWatchlistStats aggregates runtime, genres, ratings, and years over a watchlist
+func ComputeWatchlistStats(items []WatchlistItem) WatchlistStats {
+	stats := WatchlistStats{Total: len(items)}
+	genreCounts := make(map[string]int)
+	var ratingSum float64
+	var rated int
+
+	for i := range items {
+		item := &items[i]
+
+		switch {
+		case item.Movie != nil:
+			stats.Movies++
+			stats.RuntimeMinutes += item.GetRuntime()
+		case item.Show != nil:
+			stats.Shows++
+			episodes := item.Show.AiredEpisodes
+			if episodes == 0 {
+				episodes = 1 // episode count unknown; count one episode rather than none
+			}
+			stats.RuntimeMinutes += item.GetRuntime() * episodes
+		}
+
+		if rating := item.GetRating(); rating > 0 {
+			ratingSum += rating
+			rated++
+		}
+
+		for _, g := range item.GetGenres() {
+			genreCounts[g]++
+		}
+
+		if year := item.GetDisplayYear(); year > 0 {
+			if stats.Oldest == nil || year < stats.Oldest.GetDisplayYear() {
+				stats.Oldest = item
+			}
+			if stats.Newest == nil || year > stats.Newest.GetDisplayYear() {
+				stats.Newest = item
+			}
+		}
+	}
+
+	if rated > 0 {
+		stats.AverageRating = ratingSum / float64(rated)
+	}
+
+	for genre, count := range genreCounts {
+		stats.Genres = append(stats.Genres, GenreCount{Genre: genre, Count: count})
+	}
+	sort.Slice(stats.Genres, func(i, j int) bool {
+		if stats.Genres[i].Count != stats.Genres[j].Count {
+			return stats.Genres[i].Count > stats.Genres[j].Count
+		}
+		return stats.Genres[i].Genre < stats.Genres[j].Genre
+	})
+
+	return stats
+}
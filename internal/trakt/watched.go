@@ -0,0 +1,50 @@
+package trakt
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// watchedItem is one entry of /users/me/watched: a movie or show with at least one play
+type watchedItem struct {
+	Plays int    `json:"plays"`
+	Movie *Movie `json:"movie,omitempty"`
+	Show  *Show  `json:"show,omitempty"`
+}
+
+// GetWatchedIDs returns the Trakt IDs of every movie and show the user has
+// played, keyed like watchedKey. A show counts once any episode is watched.
+// mediaType can be "movies", "shows", or empty for both.
+func (c *Client) GetWatchedIDs(mediaType string) (map[string]bool, error) {
+	types := []string{"movies", "shows"}
+	if mediaType != "" {
+		types = []string{mediaType}
+	}
+
+	watched := make(map[string]bool)
+	for _, t := range types {
+		data, err := c.get("/users/me/watched/" + t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get watched %s: %w", t, err)
+		}
+
+		var items []watchedItem
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, fmt.Errorf("failed to parse watched %s: %w", t, err)
+		}
+		for _, item := range items {
+			if item.Movie != nil {
+				watched[watchedKey("movie", item.Movie.IDs.Trakt)] = true
+			}
+			if item.Show != nil {
+				watched[watchedKey("show", item.Show.IDs.Trakt)] = true
+			}
+		}
+	}
+	return watched, nil
+}
+
+// watchedKey identifies a movie or show by type, since Trakt IDs are per type
+func watchedKey(itemType string, traktID int) string {
+	return fmt.Sprintf("%s:%d", itemType, traktID)
+}
@@ -3,6 +3,7 @@ package trakt
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 // WatchlistItem represents an item in the user's watchlist
@@ -94,6 +95,36 @@ func (w *WatchlistItem) GetDisplayYear() int {
 	return 0
 }
 
+// GetTMDBID returns the TMDb ID of the watched movie or show
+func (w *WatchlistItem) GetTMDBID() int {
+	if w.Movie != nil {
+		return w.Movie.IDs.TMDB
+	}
+	if w.Show != nil {
+		return w.Show.IDs.TMDB
+	}
+	return 0
+}
+
+// GetTraktID returns the Trakt ID of the movie or show
+func (w *WatchlistItem) GetTraktID() int {
+	if w.Movie != nil {
+		return w.Movie.IDs.Trakt
+	}
+	if w.Show != nil {
+		return w.Show.IDs.Trakt
+	}
+	return 0
+}
+
+// GetTMDBMediaType returns the TMDb media type ("movie" or "tv")
+func (w *WatchlistItem) GetTMDBMediaType() string {
+	if w.Movie != nil {
+		return "movie"
+	}
+	return "tv"
+}
+
 // GetOverview returns the overview of the watchlist item
 func (w *WatchlistItem) GetOverview() string {
 	if w.Movie != nil {
@@ -138,6 +169,58 @@ func (w *WatchlistItem) GetRuntime() int {
 	return 0
 }
 
+// WatchlistFilter narrows a watchlist. Zero values don't filter.
+type WatchlistFilter struct {
+	Genre      string          // Trakt genre slug or name ("comedy", "science fiction")
+	MaxRuntime int             // minutes; items with an unknown runtime are kept
+	Watched    map[string]bool // from GetWatchedIDs; these items are dropped
+}
+
+// FilterWatchlist returns the items that pass every filter, in their original order
+func FilterWatchlist(items []WatchlistItem, filter WatchlistFilter) []WatchlistItem {
+	genre := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(filter.Genre)), " ", "-")
+
+	var kept []WatchlistItem
+	for _, item := range items {
+		if genre != "" && !hasGenre(item.GetGenres(), genre) {
+			continue
+		}
+		if filter.MaxRuntime > 0 && item.GetRuntime() > filter.MaxRuntime {
+			continue
+		}
+		if filter.Watched != nil && filter.Watched[item.watchedKey()] {
+			continue
+		}
+		kept = append(kept, item)
+	}
+	return kept
+}
+
+// watchedKey matches the keys returned by GetWatchedIDs
+func (w *WatchlistItem) watchedKey() string {
+	if w.Movie != nil {
+		return watchedKey("movie", w.Movie.IDs.Trakt)
+	}
+	if w.Show != nil {
+		return watchedKey("show", w.Show.IDs.Trakt)
+	}
+	return ""
+}
+
+// hasGenre reports whether genres contains slug. "sci-fi" also matches
+// Trakt's "science-fiction".
+func hasGenre(genres []string, slug string) bool {
+	if slug == "sci-fi" || slug == "scifi" {
+		slug = "science-fiction"
+	}
+	for _, g := range genres {
+		if strings.EqualFold(g, slug) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetWatchlist returns items from the user's watchlist
 // mediaType can be "movies", "shows", or empty for all items
 func (c *Client) GetWatchlist(mediaType string) ([]WatchlistItem, error) {
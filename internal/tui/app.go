@@ -4,12 +4,20 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
 	"wtfsiw/internal/ai"
+	"wtfsiw/internal/config"
+	"wtfsiw/internal/library"
+	"wtfsiw/internal/netutil"
+	"wtfsiw/internal/omdb"
+	"wtfsiw/internal/picks"
+	"wtfsiw/internal/textutil"
 	"wtfsiw/internal/tmdb"
 )
 
@@ -26,19 +34,30 @@ const (
 
 // Model is the main Bubble Tea model
 type Model struct {
-	state       State
-	input       textinput.Model
-	spinner     spinner.Model
-	results     []ai.Recommendation
-	summary     string // AI summary of what was searched for
-	selected    int
-	err         error
-	statusMsg   string
-	width       int
-	height      int
-	aiProvider  ai.Provider
-	tmdbClient  *tmdb.Client // nil if TMDb not configured
-	query       string
+	state      State
+	input      textinput.Model
+	spinner    spinner.Model
+	results    []ai.Recommendation
+	summary    string // AI summary of what was searched for
+	selected   int
+	err        error
+	statusMsg  string
+	width      int
+	height     int
+	aiProvider ai.Provider
+	tmdbClient *tmdb.Client // nil if TMDb not configured
+	omdbClient *omdb.Client // nil if OMDb not configured
+	query      string
+	explaining string // title waiting for an "explain this pick" reply ("" = none)
+	explainErr string // why the last explanation failed, shown in the detail view
+	linkStatus string // result of opening a provider link, shown in the detail view
+	pickStatus string // result of the last "p" (add to my picks), shown in the results and detail views
+
+	// Title autocomplete while typing (preferences.title_suggestions)
+	suggestions   []tmdb.Media
+	suggestIdx    int                // suggestion the next Tab completes
+	suggestSeq    int                // bumped on every edit so stale lookups are dropped
+	suggestCancel context.CancelFunc // abandons the in-flight lookup
 }
 
 // Messages
@@ -53,6 +72,32 @@ type searchErrorMsg struct {
 
 type statusMsg string
 
+// suggestTickMsg fires once typing has paused; seq identifies the edit it's for
+type suggestTickMsg struct {
+	seq int
+}
+
+// suggestionsMsg carries title matches for the input as it was at edit seq
+type suggestionsMsg struct {
+	seq     int
+	results []tmdb.Media
+}
+
+// Title autocomplete tuning
+const (
+	suggestDebounce = 300 * time.Millisecond
+	suggestMinChars = 3
+	maxSuggestions  = 5
+)
+
+// explainMsg carries the AI's explanation of why results[index] fits the query
+type explainMsg struct {
+	index int
+	title string // guards against the results changing while waiting
+	why   string
+	err   error
+}
+
 // NewModel creates a new TUI model
 func NewModel(aiProvider ai.Provider, tmdbClient *tmdb.Client) Model {
 	ti := textinput.New()
@@ -65,16 +110,26 @@ func NewModel(aiProvider ai.Provider, tmdbClient *tmdb.Client) Model {
 	s.Spinner = spinner.Dot
 	s.Style = spinnerStyle
 
+	// External ratings are optional
+	omdbClient, err := omdb.NewClient()
+	if err != nil {
+		omdbClient = nil
+	}
+
 	return Model{
 		state:      StateInput,
 		input:      ti,
 		spinner:    s,
 		aiProvider: aiProvider,
 		tmdbClient: tmdbClient,
+		omdbClient: omdbClient,
 	}
 }
 
 func (m Model) Init() tea.Cmd {
+	if m.state == StateLoading {
+		return tea.Batch(m.spinner.Tick, m.performSearch())
+	}
 	return textinput.Blink
 }
 
@@ -95,9 +150,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 
 	case searchCompleteMsg:
+		lib := library.Get()
+		for i := range msg.results {
+			msg.results[i].InLibrary = lib.Has(msg.results[i].Title, msg.results[i].Year)
+		}
 		m.results = msg.results
 		m.summary = msg.summary
 		m.selected = 0
+		m.pickStatus = ""
 		if len(msg.results) == 0 {
 			m.state = StateError
 			m.err = fmt.Errorf("no results found for your query")
@@ -108,12 +168,42 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case searchErrorMsg:
 		m.state = StateError
-		m.err = msg.err
+		m.err = netutil.Friendly(msg.err)
 		return m, nil
 
 	case statusMsg:
 		m.statusMsg = string(msg)
 		return m, nil
+
+	case suggestTickMsg:
+		if msg.seq != m.suggestSeq || m.state != StateInput {
+			return m, nil // typed again since
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		m.suggestCancel = cancel
+		return m, m.fetchSuggestions(ctx, msg.seq, m.input.Value())
+
+	case suggestionsMsg:
+		if msg.seq == m.suggestSeq && m.state == StateInput {
+			m.suggestions = msg.results
+			m.suggestIdx = 0
+		}
+		return m, nil
+
+	case explainMsg:
+		m.explaining = ""
+		if msg.index >= len(m.results) || m.results[msg.index].Title != msg.title {
+			return m, nil // a new search replaced the results
+		}
+		if msg.err != nil {
+			if msg.index == m.selected {
+				m.explainErr = netutil.Friendly(msg.err).Error()
+			}
+			return m, nil
+		}
+		m.explainErr = ""
+		m.results[msg.index].WhyWatch = msg.why // kept, so re-entering detail doesn't refetch
+		return m, nil
 	}
 
 	// Update text input
@@ -159,8 +249,19 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case "tab":
+		if m.state == StateInput && len(m.suggestions) > 0 {
+			// Complete to the suggestion; Tab again cycles to the next one
+			m.input.SetValue(m.suggestions[m.suggestIdx].GetDisplayTitle())
+			m.input.CursorEnd()
+			m.suggestIdx = (m.suggestIdx + 1) % len(m.suggestions)
+			return m, nil
+		}
+		return m, nil
+
 	case "enter":
 		if m.state == StateInput && m.input.Value() != "" {
+			m.clearSuggestions()
 			m.query = m.input.Value()
 			m.state = StateLoading
 			m.statusMsg = "Analyzing your request..."
@@ -168,48 +269,189 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		if m.state == StateResults && len(m.results) > 0 {
 			m.state = StateDetail
+			m.explainErr = ""
+			m.linkStatus = ""
 		}
 		return m, nil
 
+	case "r":
+		// Retry the same query, e.g. after a timeout (Esc edits it instead)
+		if m.state == StateError && m.query != "" {
+			m.err = nil
+			m.state = StateLoading
+			m.statusMsg = "Retrying..."
+			return m, tea.Batch(m.spinner.Tick, m.performSearch())
+		}
+
+	case "e":
+		if m.state == StateDetail {
+			if m.explaining == "" && m.results[m.selected].WhyWatch == "" {
+				m.explaining = m.results[m.selected].Title
+				m.explainErr = ""
+				return m, tea.Batch(m.spinner.Tick, m.explainPick(m.selected))
+			}
+			return m, nil
+		}
+
+	case "o", "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		if m.state == StateDetail {
+			// o opens the first provider, a number that provider
+			index := 0
+			if msg.String() != "o" {
+				index = int(msg.String()[0] - '1')
+			}
+			m.linkStatus = m.openProvider(m.results[m.selected], index)
+			return m, nil
+		}
+
+	case "p":
+		if (m.state == StateResults || m.state == StateDetail) && len(m.results) > 0 {
+			rec := m.results[m.selected]
+			m.pickStatus = savePick(picks.Entry{TMDBID: rec.TMDBID, MediaType: rec.MediaType, Title: rec.Title, Year: rec.Year})
+			return m, nil
+		}
+
 	case "up", "k":
 		if m.state == StateResults && m.selected > 0 {
 			m.selected--
+			m.pickStatus = ""
 		}
 		return m, nil
 
 	case "down", "j":
 		if m.state == StateResults && m.selected < len(m.results)-1 {
 			m.selected++
+			m.pickStatus = ""
 		}
 		return m, nil
 	}
 
 	// Pass to text input if in input state
 	if m.state == StateInput {
+		before := m.input.Value()
 		var cmd tea.Cmd
 		m.input, cmd = m.input.Update(msg)
+		if m.input.Value() != before {
+			return m, tea.Batch(cmd, m.inputChanged())
+		}
 		return m, cmd
 	}
 
 	return m, nil
 }
 
+// inputChanged drops suggestions for the old input and, when title
+// suggestions are on, schedules a lookup for the new one once typing pauses
+func (m *Model) inputChanged() tea.Cmd {
+	m.clearSuggestions()
+	if m.tmdbClient == nil || !config.Get().Preferences.TitleSuggestions {
+		return nil
+	}
+	if len([]rune(strings.TrimSpace(m.input.Value()))) < suggestMinChars {
+		return nil
+	}
+	seq := m.suggestSeq
+	return tea.Tick(suggestDebounce, func(time.Time) tea.Msg {
+		return suggestTickMsg{seq: seq}
+	})
+}
+
+// clearSuggestions hides the suggestions, cancels any lookup in flight, and
+// invalidates pending ones
+func (m *Model) clearSuggestions() {
+	m.suggestSeq++
+	m.suggestions = nil
+	m.suggestIdx = 0
+	if m.suggestCancel != nil {
+		m.suggestCancel()
+		m.suggestCancel = nil
+	}
+}
+
+// fetchSuggestions looks up titles matching the input for autocomplete.
+// Failures (including cancellation) just show no suggestions.
+func (m Model) fetchSuggestions(ctx context.Context, seq int, query string) tea.Cmd {
+	return func() tea.Msg {
+		resp, err := m.tmdbClient.SearchAsYouType(ctx, strings.TrimSpace(query))
+		if err != nil {
+			return suggestionsMsg{seq: seq}
+		}
+		results := resp.Results
+		if len(results) > maxSuggestions {
+			results = results[:maxSuggestions]
+		}
+		return suggestionsMsg{seq: seq, results: results}
+	}
+}
+
+// openProvider opens the link for watching rec on its index'th provider (the
+// TMDb watch page for "o" when no providers are listed) and returns a status
+// line saying what happened
+func (m Model) openProvider(rec ai.Recommendation, index int) string {
+	var name, link string
+	switch {
+	case index < len(rec.Providers):
+		name = rec.Providers[index]
+		link = rec.ProviderURL(name)
+		if link == "" {
+			return "No link for " + name
+		}
+	case index == 0 && rec.WatchLink != "":
+		name, link = "the TMDb watch page", rec.WatchLink
+	default:
+		return ""
+	}
+
+	if err := netutil.OpenBrowser(link); err != nil {
+		return "Couldn't open a browser: " + err.Error()
+	}
+	return "Opened " + name
+}
+
+// savePick adds a title to "my picks" and returns a status line saying what happened
+func savePick(e picks.Entry) string {
+	added, err := picks.Pick(e)
+	switch {
+	case err != nil:
+		return "Couldn't save to your picks: " + err.Error()
+	case !added:
+		return e.Title + " is already in your picks"
+	default:
+		return "Added " + e.Title + " to your picks (see wtfsiw picks list)"
+	}
+}
+
 func (m Model) performSearch() tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
 
-		// If TMDb is not configured, use AI directly
+		// If TMDb is not configured, use AI directly, otherwise use TMDb
+		// with AI for search params
+		var msg tea.Msg
 		if m.tmdbClient == nil {
-			return m.searchWithAI(ctx)
+			msg = m.searchWithAI(ctx)
+		} else {
+			msg = m.searchWithTMDb(ctx)
 		}
 
-		// Otherwise, use TMDb with AI for search params
-		return m.searchWithTMDb(ctx)
+		if done, ok := msg.(searchCompleteMsg); ok {
+			ai.AddOMDbRatings(m.omdbClient, done.results)
+		}
+		return msg
+	}
+}
+
+// explainPick asks the AI why results[index] fits the search query
+func (m Model) explainPick(index int) tea.Cmd {
+	rec := m.results[index]
+	return func() tea.Msg {
+		why, err := ai.ExplainPick(context.Background(), m.aiProvider, m.query, rec)
+		return explainMsg{index: index, title: rec.Title, why: why, err: err}
 	}
 }
 
 func (m Model) searchWithAI(ctx context.Context) tea.Msg {
-	resp, err := m.aiProvider.GetRecommendations(ctx, m.query, 10)
+	resp, err := m.aiProvider.GetRecommendations(ctx, m.query, config.Get().Preferences.GetAICount())
 	if err != nil {
 		return searchErrorMsg{err: fmt.Errorf("AI recommendation failed: %w", err)}
 	}
@@ -233,28 +475,26 @@ func (m Model) searchWithTMDb(ctx context.Context) tea.Msg {
 		return searchErrorMsg{err: fmt.Errorf("search failed: %w", err)}
 	}
 
-	// Enrich with streaming providers
+	// Nothing on TMDb: ask the AI directly rather than dead-ending (results are marked [AI])
+	if len(resp.Results) == 0 && config.Get().Preferences.AIFallback {
+		msg := m.searchWithAI(ctx)
+		if done, ok := msg.(searchCompleteMsg); ok {
+			done.summary = "No TMDb matches, AI suggestions: " + done.summary
+			return done
+		}
+		return msg
+	}
+
+	// Enrich with overviews and streaming providers
+	m.tmdbClient.FillMissingOverviews(resp.Results)
 	m.tmdbClient.EnrichWithProviders(resp.Results)
+	m.tmdbClient.RankByProviderPreference(resp.Results)
+	m.tmdbClient.EnrichWithBingeTime(resp.Results)
 
 	// Convert TMDb results to Recommendations
 	recommendations := make([]ai.Recommendation, len(resp.Results))
 	for i, media := range resp.Results {
-		// Get provider names
-		providers := make([]string, len(media.Providers))
-		for j, p := range media.Providers {
-			providers[j] = p.Name
-		}
-
-		recommendations[i] = ai.Recommendation{
-			Title:     media.GetDisplayTitle(),
-			Year:      media.GetDisplayYear(),
-			MediaType: media.MediaType,
-			Rating:    media.VoteAverage,
-			Overview:  media.Overview,
-			Providers: providers,
-			VoteCount: media.VoteCount,
-			FromAI:    false,
-		}
+		recommendations[i] = ai.RecommendationFromMedia(media)
 	}
 
 	summary := fmt.Sprintf("Searched for: %s", strings.Join(params.Keywords, ", "))
@@ -304,7 +544,24 @@ func (m Model) viewInput() string {
 	sb.WriteString(inputPromptStyle.Render("What are you in the mood for?"))
 	sb.WriteString("\n")
 	sb.WriteString(inputStyle.Render(m.input.View()))
-	sb.WriteString("\n\n")
+	sb.WriteString("\n")
+	if len(m.suggestions) > 0 {
+		for i, media := range m.suggestions {
+			line := "  " + media.GetDisplayTitle()
+			if year := media.GetDisplayYear(); year != "" {
+				line += " (" + year + ")"
+			}
+			if i == m.suggestIdx {
+				sb.WriteString(mediaTitleStyle.Render(line))
+			} else {
+				sb.WriteString(helpStyle.Render(line))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString(helpStyle.Render("  Tab to complete"))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
 
 	sb.WriteString(helpStyle.Render("Examples:"))
 	sb.WriteString("\n")
@@ -358,7 +615,11 @@ func (m Model) viewResults() string {
 	}
 
 	sb.WriteString("\n")
-	sb.WriteString(helpStyle.Render("↑/↓ navigate • Enter view details • Esc back • q quit"))
+	if m.pickStatus != "" {
+		sb.WriteString(statusStyle.Render(m.pickStatus))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(helpStyle.Render("↑/↓ navigate • Enter view details • p add to my picks • Esc back • q quit"))
 
 	return sb.String()
 }
@@ -374,13 +635,13 @@ func (m Model) renderResultLine(rec ai.Recommendation, selected bool) string {
 	// Provider badges
 	var providerBadges string
 	for _, p := range rec.Providers {
-		if abbr := providerEmoji(p); abbr != "" {
+		if abbr := tmdb.ProviderAbbrev(p); abbr != "" {
 			providerBadges += providerStyle.Render(abbr) + " "
 		}
 	}
 	if len(rec.Providers) > 0 && providerBadges == "" {
 		// Show first provider name if no emoji match
-		providerBadges = providerStyle.Render(truncate(rec.Providers[0], 8)) + " "
+		providerBadges = providerStyle.Render(textutil.Truncate(rec.Providers[0], 8)) + " "
 	}
 
 	// AI indicator
@@ -388,10 +649,13 @@ func (m Model) renderResultLine(rec ai.Recommendation, selected bool) string {
 	if rec.FromAI {
 		aiIndicator = statusStyle.Render(" [AI]")
 	}
+	if rec.InLibrary {
+		aiIndicator += " 💾"
+	}
 
 	line := fmt.Sprintf("%s %s (%s) %s %s%s",
 		badge,
-		mediaTitleStyle.Render(truncate(rec.Title, 35)),
+		mediaTitleStyle.Render(textutil.Truncate(rec.Title, 35)),
 		mediaYearStyle.Render(rec.Year),
 		RenderRatingCompact(rec.Rating),
 		providerBadges,
@@ -423,6 +687,14 @@ func (m Model) viewDetail() string {
 	sb.WriteString(mediaYearStyle.Render("(" + rec.Year + ")"))
 	sb.WriteString("\n")
 	sb.WriteString(mediaTypeStyle.Render(mediaType))
+	if rec.BingeTime > 0 {
+		sb.WriteString(" ")
+		sb.WriteString(subtitleStyle.Render(tmdb.BingeLabel(rec.BingeTime)))
+	}
+	if note := tmdb.LanguageNote(rec.Language); note != "" {
+		sb.WriteString(" ")
+		sb.WriteString(subtitleStyle.Render("🗣 " + note))
+	}
 	if rec.FromAI {
 		sb.WriteString(" ")
 		sb.WriteString(statusStyle.Render("[AI Recommendation]"))
@@ -436,6 +708,10 @@ func (m Model) viewDetail() string {
 	} else if rec.FromAI {
 		sb.WriteString(subtitleStyle.Render(" (estimated rating)"))
 	}
+	if len(rec.OMDbRatings) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(subtitleStyle.Render(rec.RatingSummary()))
+	}
 	sb.WriteString("\n\n")
 
 	// Genres
@@ -449,8 +725,12 @@ func (m Model) viewDetail() string {
 	if len(rec.Providers) > 0 {
 		sb.WriteString(inputPromptStyle.Render("Where to Watch:"))
 		sb.WriteString("\n")
-		for _, p := range rec.Providers {
-			sb.WriteString("  • ")
+		for i, p := range rec.Providers {
+			if i < 9 {
+				sb.WriteString(fmt.Sprintf("  %d. ", i+1))
+			} else {
+				sb.WriteString("  • ")
+			}
 			sb.WriteString(p)
 			sb.WriteString("\n")
 		}
@@ -476,9 +756,34 @@ func (m Model) viewDetail() string {
 		wrapped := wordWrap(rec.WhyWatch, min(70, m.width-10))
 		sb.WriteString(overviewStyle.Render(wrapped))
 		sb.WriteString("\n\n")
+	} else if m.explaining == rec.Title {
+		sb.WriteString(m.spinner.View() + " " + statusStyle.Render("Asking the AI why this fits your search..."))
+		sb.WriteString("\n\n")
+	} else if m.explainErr != "" {
+		sb.WriteString(errorStyle.Render("Couldn't explain this pick: "))
+		sb.WriteString(m.explainErr)
+		sb.WriteString("\n\n")
 	}
 
-	sb.WriteString(helpStyle.Render("Esc back to results • q quit"))
+	if m.linkStatus != "" {
+		sb.WriteString(statusStyle.Render(m.linkStatus))
+		sb.WriteString("\n\n")
+	}
+	if m.pickStatus != "" {
+		sb.WriteString(statusStyle.Render(m.pickStatus))
+		sb.WriteString("\n\n")
+	}
+
+	help := "p add to my picks • Esc back to results • q quit"
+	if len(rec.Providers) > 0 {
+		help = "1-9 watch on provider • " + help
+	} else if rec.WatchLink != "" {
+		help = "o open watch page • " + help
+	}
+	if rec.WhyWatch == "" && m.explaining == "" {
+		help = "e explain this pick • " + help
+	}
+	sb.WriteString(helpStyle.Render(help))
 
 	return cardStyle.Render(sb.String())
 }
@@ -493,19 +798,15 @@ func (m Model) viewError() string {
 	sb.WriteString(m.err.Error())
 	sb.WriteString("\n\n")
 
-	sb.WriteString(helpStyle.Render("Press Esc to try again • q to quit"))
+	sb.WriteString(helpStyle.Render("r retry • Esc edit the search • q quit"))
 
 	return sb.String()
 }
 
 // Helper functions
-func truncate(s string, max int) string {
-	if len(s) <= max {
-		return s
-	}
-	return s[:max-3] + "..."
-}
-
+// wordWrap wraps s to width terminal cells. Widths are measured as displayed
+// (wide CJK characters count double, styling is ignored), and words wider than
+// a line, such as unspaced CJK text, are broken across lines.
 func wordWrap(s string, width int) string {
 	if width <= 0 {
 		width = 70
@@ -517,16 +818,25 @@ func wordWrap(s string, width int) string {
 
 	var lines []string
 	var current string
+	currentWidth := 0
 
 	for _, word := range words {
-		if current == "" {
-			current = word
-		} else if len(current)+1+len(word) <= width {
+		wordWidth := lipgloss.Width(word)
+		if currentWidth > 0 && currentWidth+1+wordWidth <= width {
 			current += " " + word
-		} else {
+			currentWidth += 1 + wordWidth
+			continue
+		}
+		if currentWidth > 0 {
 			lines = append(lines, current)
-			current = word
 		}
+		for wordWidth > width {
+			var head string
+			head, word = splitAtWidth(word, width)
+			lines = append(lines, head)
+			wordWidth = lipgloss.Width(word)
+		}
+		current, currentWidth = word, wordWidth
 	}
 	if current != "" {
 		lines = append(lines, current)
@@ -535,6 +845,19 @@ func wordWrap(s string, width int) string {
 	return strings.Join(lines, "\n")
 }
 
+// splitAtWidth splits s after as many runes as fit in width cells (at least one)
+func splitAtWidth(s string, width int) (string, string) {
+	used := 0
+	for i, r := range s {
+		w := lipgloss.Width(string(r))
+		if used+w > width && i > 0 {
+			return s[:i], s[i:]
+		}
+		used += w
+	}
+	return s, ""
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -542,36 +865,20 @@ func min(a, b int) int {
 	return b
 }
 
-// providerEmoji returns a short abbreviation for common streaming providers
-func providerEmoji(name string) string {
-	switch name {
-	case "Netflix":
-		return "N"
-	case "Amazon Prime Video", "Prime Video":
-		return "P"
-	case "Disney Plus", "Disney+":
-		return "D+"
-	case "Hulu":
-		return "H"
-	case "HBO Max", "Max":
-		return "M"
-	case "Apple TV Plus", "Apple TV+":
-		return "A+"
-	case "Peacock", "Peacock Premium":
-		return "Pk"
-	case "Paramount Plus", "Paramount+":
-		return "P+"
-	case "Crunchyroll":
-		return "CR"
-	default:
-		return ""
+// Run starts the TUI application. With a query it skips the input screen and
+// opens straight into the search for it.
+func Run(aiProvider ai.Provider, tmdbClient *tmdb.Client, query string) error {
+	model := NewModel(aiProvider, tmdbClient)
+	if query != "" {
+		model.query = query
+		model.input.SetValue(query)
+		model.input.Blur()
+		model.state = StateLoading
+		model.statusMsg = "Analyzing your request..."
 	}
-}
 
-// Run starts the TUI application
-func Run(aiProvider ai.Provider, tmdbClient *tmdb.Client) error {
 	p := tea.NewProgram(
-		NewModel(aiProvider, tmdbClient),
+		model,
 		tea.WithAltScreen(),
 	)
 
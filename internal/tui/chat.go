@@ -3,16 +3,25 @@ package tui
 import (
 	"context"
 	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"slices"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
 	"wtfsiw/internal/ai"
 	"wtfsiw/internal/ai/tools"
+	"wtfsiw/internal/config"
+	"wtfsiw/internal/netutil"
+	"wtfsiw/internal/picks"
 	"wtfsiw/internal/session"
 	"wtfsiw/internal/tmdb"
 	"wtfsiw/internal/trakt"
@@ -39,20 +48,35 @@ const (
 // ChatModel is the Bubble Tea model for chat mode
 type ChatModel struct {
 	state            ChatState
-	focus            FocusArea           // Current focus area
+	focus            FocusArea // Current focus area
 	textarea         textarea.Model
 	viewport         viewport.Model
 	spinner          spinner.Model
 	chatProvider     ai.ChatProvider
 	executor         *ai.ToolExecutor
+	toolDefs         []tools.ToolDefinition // tools.Catalog minus ai.disabled_tools
 	session          *session.Session
-	displayItems     []DisplayItem       // Display items (text or cards)
-	pendingToolCalls []tools.ToolCall    // Tool calls being executed
-	cardSelection    *CardSelection      // Current card selection (nil if none)
+	displayItems     []DisplayItem    // Display items (text or cards)
+	pendingToolCalls []tools.ToolCall // Tool calls being executed
+	toolRounds       [][]string       // Tool names of each round since the last user message
+	cardSelection    *CardSelection   // Current card selection (nil if none)
+	pickingProvider  bool             // "f" was pressed; the next number key picks a provider filter
+	numberedCards    []MediaCard      // Latest card group, as numbered on screen (for "#2" references)
+	lastUserItem     int              // Display item count right after the last user message
+	turns            []chatTurn       // Each user message sent this session, for undo
+	truncated        bool             // Last reply was cut off at the length limit (Enter continues it)
+	rateLimitedUntil time.Time        // TMDb requests are held back by the rate limit until then
+	compacting       bool             // older messages are being summarized
 	width            int
 	height           int
-	ready            bool                // viewport ready
-	err              error
+	ready            bool // viewport ready
+}
+
+// chatTurn is where a user message starts on screen, and what was typed
+// ("" for "Continuing..."), so it can be undone
+type chatTurn struct {
+	displayStart int
+	input        string
 }
 
 // Chat messages
@@ -68,6 +92,35 @@ type chatErrorMsg struct {
 	err error
 }
 
+// rateLimitMsg reports that tool calls are waiting on the TMDb rate limit
+type rateLimitMsg struct {
+	wait time.Duration
+}
+
+// verbose adds diagnostics to the transcript, such as tool calls the model got
+// wrong (--verbose)
+var verbose bool
+
+// SetVerbose turns chat diagnostics on or off
+func SetVerbose(on bool) {
+	verbose = on
+}
+
+// chatRequestTimeout bounds a single chat provider request
+const chatRequestTimeout = 2 * time.Minute
+
+type sessionTitleMsg struct {
+	title string
+}
+
+// compactedMsg carries a summary of session.Messages[:through]
+type compactedMsg struct {
+	summary string
+	through int
+	undos   int // session.Undos() when the messages were read
+	err     error
+}
+
 // NewChatModel creates a new chat TUI model
 func NewChatModel(chatProvider ai.ChatProvider, tmdbClient *tmdb.Client, traktClient *trakt.Client, aiProvider ai.Provider) ChatModel {
 	// Create text area for input
@@ -97,6 +150,7 @@ func NewChatModel(chatProvider ai.ChatProvider, tmdbClient *tmdb.Client, traktCl
 		spinner:      s,
 		chatProvider: chatProvider,
 		executor:     executor,
+		toolDefs:     tools.Without(config.Get().AI.DisabledTools),
 		session:      sess,
 		displayItems: []DisplayItem{},
 	}
@@ -137,6 +191,7 @@ func (m ChatModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			m.viewport.Width = msg.Width - 6
 			m.viewport.Height = viewportHeight
+			m.updateViewportContent() // re-wrap for the new width
 		}
 
 		m.textarea.SetWidth(msg.Width - 8)
@@ -151,12 +206,38 @@ func (m ChatModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleChatResponse(msg.response)
 
 	case toolResultsMsg:
+		m.rateLimitedUntil = time.Time{}
 		return m.handleToolResults(msg.results)
 
+	case rateLimitMsg:
+		if until := time.Now().Add(msg.wait); until.After(m.rateLimitedUntil) {
+			m.rateLimitedUntil = until
+		}
+		return m, nil
+
+	case sessionTitleMsg:
+		m.session.SetGeneratedTitle(msg.title)
+		m.session.Save()
+		return m, nil
+
+	case compactedMsg:
+		m.compacting = false
+		if msg.err != nil {
+			return m, nil // the full history is still sent; try again after the next reply
+		}
+		m.session.Compact(msg.summary, msg.through, msg.undos)
+		m.session.Save()
+		return m, nil
+
 	case chatErrorMsg:
+		// Errors are shown once, inline in the transcript, and not kept
+		// as state: the next turn starts clean
 		m.state = ChatStateReady
-		m.err = msg.err
-		m.addSystemMessage(fmt.Sprintf("Error: %s", msg.err.Error()))
+		if netutil.IsOffline(msg.err) {
+			m.addSystemMessage("You appear to be offline. Check your connection and try again.")
+		} else {
+			m.addSystemMessage(fmt.Sprintf("Error: %s", msg.err.Error()))
+		}
 		return m, nil
 	}
 
@@ -184,6 +265,20 @@ func (m ChatModel) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.session.Save()
 		return m, tea.Quit
 
+	case "ctrl+r":
+		// Regenerate the last response (only when idle)
+		if m.state == ChatStateReady {
+			return m.regenerateResponse()
+		}
+		return m, nil
+
+	case "ctrl+z":
+		// Undo the last message and its replies (only when idle)
+		if m.state == ChatStateReady {
+			return m.undoLastMessage()
+		}
+		return m, nil
+
 	case "tab":
 		// Cycle focus: Input -> Viewport -> Cards (if any) -> Input
 		if m.state == ChatStateReady {
@@ -213,6 +308,16 @@ func (m ChatModel) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "esc":
+		// Cancel picking a provider, then clear the filter, before leaving card selection
+		if m.focus == FocusCards && m.pickingProvider {
+			m.pickingProvider = false
+			return m, nil
+		}
+		if m.focus == FocusCards && m.cardSelection != nil && m.cardSelection.Filter != "" {
+			m.filterCards("")
+			m.updateViewportContent()
+			return m, nil
+		}
 		// If in card selection, go back to viewport
 		if m.focus == FocusCards {
 			m.focus = FocusViewport
@@ -257,6 +362,22 @@ func (m ChatModel) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.state == ChatStateReady && strings.TrimSpace(m.textarea.Value()) != "" {
 			return m.sendMessage()
 		}
+		if m.state == ChatStateReady && m.truncated {
+			return m.continueResponse()
+		}
+		return m, nil
+	}
+
+	// A number key after "f" filters the card group to that provider
+	if m.focus == FocusCards && m.cardSelection != nil && m.pickingProvider {
+		m.pickingProvider = false
+		providers := m.groupProviders()
+		if key := msg.String(); len(key) == 1 && key[0] >= '1' && key[0] <= '9' {
+			if idx := int(key[0] - '1'); idx < len(providers) {
+				m.filterCards(providers[idx])
+				m.updateViewportContent()
+			}
+		}
 		return m, nil
 	}
 
@@ -272,16 +393,39 @@ func (m ChatModel) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.updateViewportContent()
 			return m, nil
 		case "home", "g":
-			m.cardSelection.CardIndex = 0
+			m.cardSelection.CardIndex = m.cardSelection.VisibleIndices()[0]
 			m.updateViewportContent()
 			return m, nil
 		case "end", "G":
-			m.cardSelection.CardIndex = m.cardSelection.TotalCards - 1
+			visible := m.cardSelection.VisibleIndices()
+			m.cardSelection.CardIndex = visible[len(visible)-1]
+			m.updateViewportContent()
+			return m, nil
+		case "pgup", "shift+tab", "[":
+			// Older card group
+			m.moveCardGroup(-1)
 			m.updateViewportContent()
 			return m, nil
+		case "pgdown", "]":
+			// Newer card group
+			m.moveCardGroup(1)
+			m.updateViewportContent()
+			return m, nil
+		case " ":
+			m.cardSelection.ToggleMarked(m.cardSelection.CardIndex)
+			m.updateViewportContent()
+			return m, nil
+		case "p":
+			m.pickSelectedCards()
+			return m, nil
+		case "f":
+			if len(m.groupProviders()) > 0 {
+				m.pickingProvider = true
+			}
+			return m, nil
 		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
 			idx := int(msg.String()[0] - '1')
-			if idx < m.cardSelection.TotalCards {
+			if idx < m.cardSelection.TotalCards && m.cardSelection.IsVisible(idx) {
 				m.cardSelection.CardIndex = idx
 				m.updateViewportContent()
 			}
@@ -329,29 +473,182 @@ func (m ChatModel) sendMessage() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	// Add user message to session
+	// "/context" commands manage the taste document and aren't sent
+	if arg, ok := contextCommand(content); ok {
+		m.textarea.Reset()
+		m.runContextCommand(arg)
+		return m, nil
+	}
+
+	// Add user message to session. "#N" references to displayed cards are
+	// resolved here so the model gets the IDs without searching again.
+	sent := content
+	if note := CardReferenceNote(content, m.numberedCards); note != "" {
+		sent += "\n\n" + note
+	}
 	userMsg := ai.ChatMessage{
 		Role:      "user",
-		Content:   content,
+		Content:   sent,
 		Timestamp: time.Now(),
 	}
 	m.session.AddMessage(userMsg)
 
-	// Add to display
+	// Add to display, jumping back down if history was being read
+	m.turns = append(m.turns, chatTurn{displayStart: len(m.displayItems), input: content})
 	m.addDisplayMessage(FormatUserMessage(content))
+	m.viewport.GotoBottom()
+	m.lastUserItem = len(m.displayItems)
 
 	// Clear input
 	m.textarea.Reset()
 
 	// Start AI response
+	m.truncated = false
+	m.toolRounds = nil
 	m.state = ChatStateWaitingAI
 	return m, m.callChatProvider()
 }
 
+// contextCommand returns the argument of a "/context [file|off]" message
+func contextCommand(content string) (arg string, ok bool) {
+	rest, ok := strings.CutPrefix(content, "/context")
+	if !ok || (rest != "" && rest[0] != ' ') {
+		return "", false
+	}
+	return strings.TrimSpace(rest), true
+}
+
+// runContextCommand loads a taste document to send with every request (like
+// --context), clears it with "off", or reports what's loaded. The document
+// lives in the system prompt, never in the saved session.
+func (m *ChatModel) runContextCommand(arg string) {
+	switch arg {
+	case "":
+		if text := ai.UserContext(); text != "" {
+			m.addSystemMessage(fmt.Sprintf("A taste context of %d characters is being sent with each message. /context off stops it.", len(text)))
+		} else {
+			m.addSystemMessage("No taste context loaded. /context FILE sends a text file (e.g. a list of favorites) with each message.")
+		}
+	case "off":
+		ai.SetUserContext("")
+		m.addSystemMessage("Taste context cleared.")
+	default:
+		text, truncated, err := ai.LoadContextFile(arg)
+		if err != nil {
+			m.addSystemMessage(fmt.Sprintf("Error: %s", err.Error()))
+			return
+		}
+		ai.SetUserContext(text)
+		msg := fmt.Sprintf("Loaded taste context from %s. It's sent with each message from now on.", arg)
+		if truncated {
+			msg += fmt.Sprintf(" It's over %d KB, so only the start of it is used.", ai.MaxContextBytes/1024)
+		}
+		m.addSystemMessage(msg)
+	}
+	m.viewport.GotoBottom()
+}
+
+// continueResponse asks the model to pick up a reply that was cut off at the length limit
+func (m ChatModel) continueResponse() (tea.Model, tea.Cmd) {
+	m.session.AddMessage(ai.ChatMessage{
+		Role:      "user",
+		Content:   "Continue exactly where you left off.",
+		Timestamp: time.Now(),
+	})
+	m.turns = append(m.turns, chatTurn{displayStart: len(m.displayItems)})
+	m.addSystemMessage("Continuing...")
+	// The continue prompt is now the last user message, so Ctrl+R redoes just
+	// the continuation and keeps the first part on screen, as in the session
+	m.lastUserItem = len(m.displayItems)
+	m.viewport.GotoBottom()
+
+	m.truncated = false
+	m.toolRounds = nil
+	m.state = ChatStateWaitingAI
+	return m, m.callChatProvider()
+}
+
+// regenerateResponse discards the reply to the last user message (including any
+// tool calls and results) and asks the provider again
+func (m ChatModel) regenerateResponse() (tea.Model, tea.Cmd) {
+	if !m.session.RewindToLastUserMessage() {
+		return m, nil
+	}
+
+	// Drop everything displayed after the last user message
+	if m.lastUserItem <= len(m.displayItems) {
+		m.displayItems = m.displayItems[:m.lastUserItem]
+	}
+	m.cardSelection = nil
+	if m.focus == FocusCards {
+		m.focus = FocusInput
+		m.textarea.Focus()
+	}
+	m.addSystemMessage("Regenerating response...")
+	m.viewport.GotoBottom()
+
+	m.truncated = false
+	m.toolRounds = nil
+	m.state = ChatStateWaitingAI
+	return m, m.callChatProvider()
+}
+
+// undoLastMessage removes the last user message and everything it led to
+// (replies, tool calls and results) from the session and the screen, and puts
+// what was typed back in the input to edit
+func (m ChatModel) undoLastMessage() (tea.Model, tea.Cmd) {
+	// Only turns sent since the chat opened are tracked, so a resumed
+	// session's earlier messages can't be undone; say so rather than do nothing
+	if len(m.turns) == 0 || !m.session.UndoLastUserMessage() {
+		m.addSystemMessage("Nothing to undo in this session.")
+		return m, nil
+	}
+	turn := m.turns[len(m.turns)-1]
+	m.turns = m.turns[:len(m.turns)-1]
+
+	if turn.displayStart <= len(m.displayItems) {
+		m.displayItems = m.displayItems[:turn.displayStart]
+	}
+	m.lastUserItem = 0
+	if len(m.turns) > 0 {
+		m.lastUserItem = m.turns[len(m.turns)-1].displayStart + 1
+	}
+
+	// "#N" references go back to the latest card group still on screen
+	m.numberedCards = nil
+	for i := len(m.displayItems) - 1; i >= 0; i-- {
+		if m.displayItems[i].Type == DisplayItemCards {
+			m.numberedCards = m.displayItems[i].MediaCards
+			break
+		}
+	}
+	m.cardSelection = nil
+	if m.focus == FocusCards {
+		m.focus = FocusInput
+		m.textarea.Focus()
+	}
+
+	m.truncated = false
+	m.toolRounds = nil
+	if turn.input != "" && m.textarea.Value() == "" {
+		m.textarea.SetValue(turn.input)
+	}
+	m.session.Save()
+	m.updateViewportContent()
+	return m, nil
+}
+
 func (m ChatModel) callChatProvider() tea.Cmd {
+	// Read here, not in the goroutine: a compaction finishing meanwhile
+	// changes the session's summary, and undo rewrites its messages
+	messages := append([]ai.ChatMessage(nil), m.session.APIMessages()...)
+	provider, toolDefs := m.chatProvider, m.toolDefs
+
 	return func() tea.Msg {
-		ctx := context.Background()
-		response, err := m.chatProvider.SendMessage(ctx, m.session.Messages, tools.Catalog)
+		// Bounded so a connection that drops mid-request can't leave the chat stuck waiting
+		ctx, cancel := context.WithTimeout(context.Background(), chatRequestTimeout)
+		defer cancel()
+		response, err := provider.SendMessage(ctx, messages, toolDefs)
 		if err != nil {
 			return chatErrorMsg{err: err}
 		}
@@ -381,10 +678,17 @@ func (m ChatModel) handleChatResponse(response *ai.ChatResponse) (tea.Model, tea
 		m.pendingToolCalls = response.ToolCalls
 
 		// Show tool usage
+		var names []string
 		for _, tc := range response.ToolCalls {
+			names = append(names, tc.Name)
 			m.addDisplayMessage(FormatToolCall(tc.Name))
+			if verbose && !tools.IsKnown(tc.Name) {
+				m.addSystemMessage(fmt.Sprintf("The model called an unknown tool %q; it's been sent the list of real ones", tc.Name))
+			}
 		}
 
+		m.toolRounds = append(m.toolRounds, names)
+
 		// Execute all tools
 		return m, m.executeTools(response.ToolCalls)
 	}
@@ -399,12 +703,63 @@ func (m ChatModel) handleChatResponse(response *ai.ChatResponse) (tea.Model, tea
 
 	// Add to display
 	m.addDisplayMessage(FormatAssistantMessage(response.Content))
+	if response.StopReason == "max_tokens" {
+		m.truncated = true
+		m.addSystemMessage("⚠ This response was cut off at the length limit. Press Enter to continue it.")
+	}
 
 	// Save session
 	m.session.Save()
 
 	m.state = ChatStateReady
-	return m, nil
+	// Before the return: maybeCompact sets m.compacting, which must be in the model returned
+	compact := m.maybeCompact()
+	return m, tea.Batch(m.maybeGenerateTitle(), compact)
+}
+
+// maybeCompact summarizes the oldest exchanges once the conversation sent to
+// the provider grows past preferences.chat_max_messages, keeping the recent
+// turns verbatim. It runs between turns, and the full history stays on disk.
+func (m *ChatModel) maybeCompact() tea.Cmd {
+	if m.compacting {
+		return nil
+	}
+	through, ok := m.session.CompactionPoint(config.Get().Preferences.ChatMaxMessages)
+	if !ok {
+		return nil
+	}
+	m.compacting = true
+	previous := m.session.Summary
+	// A copy, like maybeGenerateTitle's: the session keeps changing while this runs
+	older := append([]ai.ChatMessage(nil), m.session.Messages[m.session.SummarizedThrough:through]...)
+	provider := m.chatProvider
+	undos := m.session.Undos()
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), chatRequestTimeout)
+		defer cancel()
+		summary, err := ai.SummarizeConversation(ctx, provider, previous, older)
+		return compactedMsg{summary: summary, through: through, undos: undos, err: err}
+	}
+}
+
+// maybeGenerateTitle asks the model to name the session once it has a couple of
+// exchanges. It's marked as done up front so the request is only ever made once.
+func (m ChatModel) maybeGenerateTitle() tea.Cmd {
+	if !config.Get().Preferences.AutoTitle || !m.session.NeedsTitle() {
+		return nil
+	}
+	m.session.TitleGenerated = true
+	// A copy: the session keeps appending to its slice while the title is generated
+	messages := append([]ai.ChatMessage(nil), m.session.Messages...)
+
+	return func() tea.Msg {
+		title, err := ai.GenerateSessionTitle(context.Background(), m.chatProvider, messages)
+		if err != nil {
+			return nil // keep the truncated first-message title
+		}
+		return sessionTitleMsg{title: title}
+	}
 }
 
 func (m ChatModel) executeTools(toolCalls []tools.ToolCall) tea.Cmd {
@@ -423,22 +778,30 @@ func (m ChatModel) executeTools(toolCalls []tools.ToolCall) tea.Cmd {
 }
 
 func (m ChatModel) handleToolResults(results []tools.ToolResult) (tea.Model, tea.Cmd) {
+	calls, unanswered := matchToolResults(m.pendingToolCalls, results)
+
 	// Add ALL tool results to session before calling API again
-	for _, result := range results {
+	for i, result := range results {
+		call := calls[i]
+		if call == nil {
+			// Sending it would fail: the provider only accepts results for its own calls
+			m.addSystemMessage(fmt.Sprintf("⚠ Ignored a tool result (id %q) that doesn't match any pending tool call", result.ToolCallID))
+			continue
+		}
+		toolName := call.Name
+
 		toolMsg := ai.ChatMessage{
 			Role:       "tool",
 			Content:    result.Content,
-			ToolCallID: result.ToolCallID,
+			ToolCallID: call.ID, // the matched call's, in case the result's ID was empty or reused
 			Timestamp:  time.Now(),
 		}
 		m.session.AddMessage(toolMsg)
 
-		// Find the tool name from pending tool calls
-		toolName := result.ToolCallID
-		for _, tc := range m.pendingToolCalls {
-			if tc.ID == result.ToolCallID {
-				toolName = tc.Name
-				break
+		// Show what a search filtered on, above its results
+		if call != nil && toolName == "search_media" && config.Get().Preferences.ShowSearchFilters {
+			if filters := FormatSearchFilters(*call); filters != "" {
+				m.addDisplayMessage(filters)
 			}
 		}
 
@@ -456,6 +819,17 @@ func (m ChatModel) handleToolResults(results []tools.ToolResult) (tea.Model, tea
 		m.addDisplayMessage(FormatToolResult(toolName, !result.IsError))
 	}
 
+	// Every call needs a result, or the provider rejects the conversation
+	for _, tc := range unanswered {
+		m.addSystemMessage(fmt.Sprintf("⚠ No result came back for the %s call", tc.Name))
+		m.session.AddMessage(ai.ChatMessage{
+			Role:       "tool",
+			Content:    "Error: the tool returned no result for this call",
+			ToolCallID: tc.ID,
+			Timestamp:  time.Now(),
+		})
+	}
+
 	// Clear pending tool calls
 	m.pendingToolCalls = nil
 
@@ -464,13 +838,54 @@ func (m ChatModel) handleToolResults(results []tools.ToolResult) (tea.Model, tea
 	return m, m.callChatProvider()
 }
 
+// matchToolResults pairs each result with the pending call it answers, so
+// every result maps to exactly one call. A result claims the first unclaimed
+// call with its ID; one whose ID is empty, unknown, or already claimed falls
+// back to the unclaimed call at its own position (the executor answers calls
+// in order). calls[i] is nil for a result that matches nothing, and
+// unanswered lists the calls no result claimed.
+func matchToolResults(pending []tools.ToolCall, results []tools.ToolResult) (calls []*tools.ToolCall, unanswered []tools.ToolCall) {
+	claimed := make([]bool, len(pending))
+	calls = make([]*tools.ToolCall, len(results))
+
+	for i, result := range results {
+		if result.ToolCallID == "" {
+			continue
+		}
+		for j := range pending {
+			if !claimed[j] && pending[j].ID == result.ToolCallID {
+				claimed[j] = true
+				calls[i] = &pending[j]
+				break
+			}
+		}
+	}
+	for i := range results {
+		if calls[i] == nil && i < len(pending) && !claimed[i] {
+			claimed[i] = true
+			calls[i] = &pending[i]
+		}
+	}
+
+	for j, tc := range pending {
+		if !claimed[j] {
+			unanswered = append(unanswered, tc)
+		}
+	}
+	return calls, unanswered
+}
+
 func (m *ChatModel) addDisplayMessage(msg string) {
 	m.displayItems = append(m.displayItems, NewTextDisplayItem(msg))
 	m.updateViewportContent()
 }
 
 func (m *ChatModel) addMediaCards(cards []MediaCard, toolName string) {
+	if config.Get().Preferences.CollapseDuplicates {
+		cards = CollapseDuplicateCards(cards)
+	}
 	m.displayItems = append(m.displayItems, NewCardsDisplayItem(cards, toolName))
+	m.numberedCards = cards
 	m.updateViewportContent()
 }
 
@@ -478,26 +893,73 @@ func (m *ChatModel) addSystemMessage(msg string) {
 	m.addDisplayMessage(FormatSystemMessage(msg))
 }
 
+// updateViewportContent re-renders the transcript. It only follows new content
+// to the bottom when the view was already there (or cards are being picked),
+// so a reply or tool result arriving doesn't yank away history being read.
 func (m *ChatModel) updateViewportContent() {
 	if m.ready {
+		follow := m.viewport.AtBottom() || m.cardSelection != nil
 		m.viewport.SetContent(m.renderDisplayItems())
-		m.viewport.GotoBottom()
+		if follow {
+			m.viewport.GotoBottom()
+		}
+		if m.cardSelection != nil {
+			m.scrollToCardGroup()
+		}
+	}
+}
+
+// scrollToCardGroup scrolls up to the selected card group when it's above the
+// bottom of the viewport (i.e. an older group was selected)
+func (m *ChatModel) scrollToCardGroup() {
+	var before []string
+	for i := 0; i < m.cardSelection.ItemIndex && i < len(m.displayItems); i++ {
+		before = append(before, m.renderItem(i))
+	}
+	offset := 0
+	if len(before) > 0 {
+		// +2 for the blank line separating items
+		offset = strings.Count(strings.Join(before, "\n\n"), "\n") + 2
+	}
+	if offset < m.viewport.YOffset {
+		m.viewport.SetYOffset(offset)
 	}
 }
 
 func (m *ChatModel) renderDisplayItems() string {
-	var parts []string
-	for i, item := range m.displayItems {
-		switch item.Type {
-		case DisplayItemText:
-			parts = append(parts, item.Text)
-		case DisplayItemCards:
-			parts = append(parts, RenderMediaCardGroup(item.MediaCards, m.cardSelection, i, m.width))
-		}
+	parts := make([]string, len(m.displayItems))
+	for i := range m.displayItems {
+		parts[i] = m.renderItem(i)
 	}
 	return strings.Join(parts, "\n\n")
 }
 
+// renderItem renders one display item, wrapping text to the viewport width.
+// Renders are cached on the item until the width changes, so a long session
+// doesn't re-wrap its whole history on every update. The card group holding
+// the selection is always rendered fresh, since it changes as cards are picked.
+func (m *ChatModel) renderItem(i int) string {
+	item := &m.displayItems[i]
+	if item.Type == DisplayItemCards && m.cardSelection != nil && m.cardSelection.ItemIndex == i {
+		return RenderMediaCardGroup(item.MediaCards, m.cardSelection, i, m.width)
+	}
+	if item.renderedWidth > 0 && item.renderedWidth == m.width {
+		return item.rendered
+	}
+
+	switch item.Type {
+	case DisplayItemText:
+		item.rendered = item.Text
+		if m.viewport.Width > 0 {
+			item.rendered = lipgloss.NewStyle().Width(m.viewport.Width).Render(item.Text)
+		}
+	case DisplayItemCards:
+		item.rendered = RenderMediaCardGroup(item.MediaCards, nil, i, m.width)
+	}
+	item.renderedWidth = m.width
+	return item.rendered
+}
+
 func (m *ChatModel) hasCards() bool {
 	for _, item := range m.displayItems {
 		if item.Type == DisplayItemCards && len(item.MediaCards) > 0 {
@@ -509,29 +971,95 @@ func (m *ChatModel) hasCards() bool {
 
 func (m *ChatModel) initCardSelection() {
 	// Find the last card group and select the first card
-	for i := len(m.displayItems) - 1; i >= 0; i-- {
-		if m.displayItems[i].Type == DisplayItemCards && len(m.displayItems[i].MediaCards) > 0 {
-			m.cardSelection = &CardSelection{
-				ItemIndex:  i,
-				CardIndex:  0,
-				TotalCards: len(m.displayItems[i].MediaCards),
-			}
-			return
+	groups := m.cardGroupIndices()
+	if len(groups) == 0 {
+		return
+	}
+	m.selectCardGroup(groups[len(groups)-1])
+}
+
+// cardGroupIndices returns the display item indices of all non-empty card groups, oldest first
+func (m *ChatModel) cardGroupIndices() []int {
+	var indices []int
+	for i, item := range m.displayItems {
+		if item.Type == DisplayItemCards && len(item.MediaCards) > 0 {
+			indices = append(indices, i)
 		}
 	}
+	return indices
+}
+
+// selectCardGroup selects the first card of the card group at itemIndex (marks
+// are per group). A provider filter carries over if any of the group's cards match it.
+func (m *ChatModel) selectCardGroup(itemIndex int) {
+	filter := ""
+	if m.cardSelection != nil {
+		filter = m.cardSelection.Filter
+	}
+	m.pickingProvider = false
+	m.cardSelection = &CardSelection{
+		ItemIndex:  itemIndex,
+		CardIndex:  0,
+		TotalCards: len(m.displayItems[itemIndex].MediaCards),
+	}
+	if filter != "" {
+		m.filterCards(filter)
+		if len(m.cardSelection.Visible) == 0 {
+			m.filterCards("")
+		}
+	}
+}
+
+// groupProviders returns the providers offered by the selected card group, up
+// to the nine that can be picked with a number key
+func (m *ChatModel) groupProviders() []string {
+	if m.cardSelection == nil {
+		return nil
+	}
+	providers := CardProviders(m.displayItems[m.cardSelection.ItemIndex].MediaCards)
+	if len(providers) > 9 {
+		providers = providers[:9]
+	}
+	return providers
+}
+
+// filterCards narrows the selected card group to cards available on provider
+// ("" shows them all again). It's display-only: no new search is made.
+func (m *ChatModel) filterCards(provider string) {
+	m.cardSelection.SetFilter(m.displayItems[m.cardSelection.ItemIndex].MediaCards, provider)
+}
+
+// moveCardGroup moves the selection to an older (delta < 0) or newer card group
+func (m *ChatModel) moveCardGroup(delta int) {
+	if m.cardSelection == nil {
+		return
+	}
+	groups := m.cardGroupIndices()
+	for pos, idx := range groups {
+		if idx != m.cardSelection.ItemIndex {
+			continue
+		}
+		newPos := pos + delta
+		if newPos >= 0 && newPos < len(groups) {
+			m.selectCardGroup(groups[newPos])
+		}
+		return
+	}
 }
 
 func (m *ChatModel) moveCardSelection(delta int) {
 	if m.cardSelection == nil {
 		return
 	}
-	newIdx := m.cardSelection.CardIndex + delta
-	if newIdx < 0 {
-		newIdx = 0
-	} else if newIdx >= m.cardSelection.TotalCards {
-		newIdx = m.cardSelection.TotalCards - 1
+	// Step through the shown cards only, skipping any filtered out
+	visible := m.cardSelection.VisibleIndices()
+	newPos := slices.Index(visible, m.cardSelection.CardIndex) + delta
+	if newPos < 0 {
+		newPos = 0
+	} else if newPos >= len(visible) {
+		newPos = len(visible) - 1
 	}
-	m.cardSelection.CardIndex = newIdx
+	m.cardSelection.CardIndex = visible[newPos]
 }
 
 func (m ChatModel) expandSelectedCard() (tea.Model, tea.Cmd) {
@@ -539,37 +1067,86 @@ func (m ChatModel) expandSelectedCard() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	// Get the selected card
+	// Get the selected card group
 	item := m.displayItems[m.cardSelection.ItemIndex]
 	if item.Type != DisplayItemCards || m.cardSelection.CardIndex >= len(item.MediaCards) {
 		return m, nil
 	}
 
-	card := item.MediaCards[m.cardSelection.CardIndex]
+	// Expand all marked cards, or just the highlighted one if none are marked
+	indices := m.cardSelection.MarkedIndices()
+	if len(indices) == 0 {
+		indices = []int{m.cardSelection.CardIndex}
+	}
+
+	for _, idx := range indices {
+		if idx >= len(item.MediaCards) {
+			continue
+		}
+		m.addDisplayMessage(FormatSystemMessage(formatExpandedCard(item.MediaCards[idx])))
+	}
 
-	// Show expanded card info as a system message
+	// Return to input mode for follow-up
+	m.focus = FocusInput
+	m.cardSelection = nil
+	m.textarea.Focus()
+	m.updateViewportContent()
+
+	return m, textarea.Blink
+}
+
+// pickSelectedCards adds the marked cards, or just the highlighted one if none
+// are marked, to "my picks". Card selection stays open to pick more.
+func (m *ChatModel) pickSelectedCards() {
+	item := m.displayItems[m.cardSelection.ItemIndex]
+	indices := m.cardSelection.MarkedIndices()
+	if len(indices) == 0 {
+		indices = []int{m.cardSelection.CardIndex}
+	}
+
+	var statuses []string
+	for _, idx := range indices {
+		if idx >= len(item.MediaCards) {
+			continue
+		}
+		card := item.MediaCards[idx]
+		statuses = append(statuses, savePick(picks.Entry{TMDBID: card.ID, MediaType: card.MediaType, Title: card.Title, Year: card.Year}))
+	}
+	m.addSystemMessage(strings.Join(statuses, "\n"))
+}
+
+// formatExpandedCard formats full card info for display as a system message
+func formatExpandedCard(card MediaCard) string {
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("📋 %s (%s)\n", card.Title, card.Year))
-	sb.WriteString(fmt.Sprintf("   Rating: %s %.1f/10\n", renderStars(card.Rating), card.Rating))
+	if card.Rating > 0 {
+		sb.WriteString(fmt.Sprintf("   Rating: %s %.1f/10\n", renderStars(card.Rating), card.Rating))
+	} else {
+		sb.WriteString("   Rating: N/A\n")
+	}
 	if len(card.Providers) > 0 {
 		sb.WriteString(fmt.Sprintf("   Watch on: %s\n", strings.Join(card.Providers, ", ")))
 	}
+	if note := tmdb.LanguageNote(card.Language); note != "" {
+		sb.WriteString(fmt.Sprintf("   Language: %s\n", note))
+	}
 	if card.Overview != "" {
 		sb.WriteString(fmt.Sprintf("   %s", card.Overview))
 	}
 	if card.WhyWatch != "" {
 		sb.WriteString(fmt.Sprintf("\n   💡 %s", card.WhyWatch))
 	}
-
-	m.addDisplayMessage(FormatSystemMessage(sb.String()))
-
-	// Return to input mode for follow-up
-	m.focus = FocusInput
-	m.cardSelection = nil
-	m.textarea.Focus()
-	m.updateViewportContent()
-
-	return m, textarea.Blink
+	for _, alt := range card.Alternates {
+		mediaType := "Movie"
+		if alt.MediaType == "tv" {
+			mediaType = "TV Show"
+		}
+		sb.WriteString(fmt.Sprintf("\n   Also a %s: %s (%s) %.1f/10", mediaType, alt.Title, alt.Year, alt.Rating))
+		if len(alt.Providers) > 0 {
+			sb.WriteString(fmt.Sprintf(" on %s", strings.Join(alt.Providers, ", ")))
+		}
+	}
+	return sb.String()
 }
 
 func (m ChatModel) View() string {
@@ -581,12 +1158,18 @@ func (m ChatModel) View() string {
 
 	// Header with focus indicator and scroll position
 	headerText := "wtfsiw - Chat Mode"
+	if config.Get().Preferences.KidsMode {
+		headerText += " [KIDS]"
+	}
 	switch m.focus {
 	case FocusViewport:
 		scrollPercent := m.viewport.ScrollPercent() * 100
 		headerText += fmt.Sprintf(" [SCROLL %.0f%%]", scrollPercent)
 	case FocusCards:
 		headerText += " [SELECT CARD]"
+		if m.cardSelection != nil && m.cardSelection.Filter != "" {
+			headerText += " [FILTER: " + m.cardSelection.Filter + "]"
+		}
 	}
 	sb.WriteString(chatHeaderStyle.Render(headerText))
 	sb.WriteString("\n")
@@ -604,14 +1187,12 @@ func (m ChatModel) View() string {
 	case ChatStateExecutingTool:
 		sb.WriteString(m.spinner.View())
 		sb.WriteString(" ")
-		toolNames := ""
-		for i, tc := range m.pendingToolCalls {
-			if i > 0 {
-				toolNames += ", "
-			}
-			toolNames += tc.Name
+		progress := FormatToolProgress(m.toolRounds)
+		if wait := time.Until(m.rateLimitedUntil); wait > 0 {
+			sb.WriteString(toolExecutingStyle.Render(fmt.Sprintf("%s (waiting %.0fs for the TMDb rate limit)...", progress, math.Ceil(wait.Seconds()))))
+		} else {
+			sb.WriteString(toolExecutingStyle.Render(progress + "..."))
 		}
-		sb.WriteString(toolExecutingStyle.Render("Executing: " + toolNames + "..."))
 	}
 	sb.WriteString("\n")
 
@@ -624,16 +1205,40 @@ func (m ChatModel) View() string {
 	switch {
 	case m.state != ChatStateReady:
 		help = "Processing..."
+	case m.focus == FocusCards && m.pickingProvider:
+		var choices []string
+		for i, p := range m.groupProviders() {
+			choices = append(choices, fmt.Sprintf("%d %s", i+1, p))
+		}
+		help = "Show only: " + strings.Join(choices, " • ") + " • Esc cancel"
 	case m.focus == FocusCards:
 		sel := ""
 		if m.cardSelection != nil {
-			sel = fmt.Sprintf(" [%d/%d]", m.cardSelection.CardIndex+1, m.cardSelection.TotalCards)
+			visible := m.cardSelection.VisibleIndices()
+			sel = fmt.Sprintf(" [%d/%d]", slices.Index(visible, m.cardSelection.CardIndex)+1, len(visible))
+			groups := m.cardGroupIndices()
+			if len(groups) > 1 {
+				for pos, idx := range groups {
+					if idx == m.cardSelection.ItemIndex {
+						sel += fmt.Sprintf(" group %d/%d", pos+1, len(groups))
+					}
+				}
+			}
+			if marked := len(m.cardSelection.MarkedIndices()); marked > 0 {
+				sel += fmt.Sprintf(" (%d marked)", marked)
+			}
+		}
+		if m.cardSelection != nil && m.cardSelection.Filter != "" {
+			help = fmt.Sprintf("↑/k ↓/j select • PgUp/PgDn group • 1-9 quick select • Space mark • p pick • f filter • Enter expand • Esc clear filter%s", sel)
+		} else {
+			help = fmt.Sprintf("↑/k ↓/j select • PgUp/PgDn group • 1-9 quick select • Space mark • p pick • f filter • Enter expand • Esc back%s", sel)
 		}
-		help = fmt.Sprintf("↑/k ↓/j select • 1-9 quick select • Enter expand • Esc back%s", sel)
 	case m.focus == FocusViewport:
 		help = "↑/k ↓/j scroll • Ctrl+u/d page • g/G top/bottom • Tab cards • Esc → input"
+	case m.truncated && m.textarea.Value() == "":
+		help = "Enter continue • Tab scroll history • Ctrl+r regenerate • Ctrl+z undo • Esc quit"
 	default:
-		help = "Enter send • Tab scroll history • Esc quit"
+		help = "Enter send • Tab scroll history • Ctrl+r regenerate • Ctrl+z undo • Esc quit"
 	}
 	sb.WriteString(chatHelpStyle.Render(help))
 
@@ -647,6 +1252,32 @@ func RunChat(chatProvider ai.ChatProvider, tmdbClient *tmdb.Client, traktClient
 		tea.WithAltScreen(),
 	)
 
-	_, err := p.Run()
+	// Tool calls share the TMDb client's rate limiter; say when it's holding
+	// them back so a busy turn doesn't look frozen
+	if tmdbClient != nil {
+		tmdbClient.OnRateLimitWait(func(wait time.Duration) {
+			p.Send(rateLimitMsg{wait: wait})
+		})
+	}
+
+	// Closing the terminal (SIGHUP) or a kill (SIGTERM, which Bubble Tea turns
+	// into a quit) ends the program without Esc or Ctrl+C, so the session is
+	// also saved from the final model
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer func() {
+		signal.Stop(hangup)
+		close(hangup)
+	}()
+	go func() {
+		if _, ok := <-hangup; ok {
+			p.Quit()
+		}
+	}()
+
+	final, err := p.Run()
+	if chat, ok := final.(ChatModel); ok && chat.session != nil {
+		chat.session.Save()
+	}
 	return err
 }
@@ -2,7 +2,13 @@ package tui
 
 import (
 	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode"
 )
 
 // DisplayItemType represents the type of display item
@@ -19,6 +25,9 @@ type DisplayItem struct {
 	Text       string      // For text messages
 	MediaCards []MediaCard // For card groups from tool results
 	ToolName   string      // Which tool produced these cards
+
+	rendered      string // Cached render at renderedWidth (see ChatModel.renderItem)
+	renderedWidth int    // Terminal width of the cached render (0 = not rendered)
 }
 
 // MediaCard represents a single movie/TV show card
@@ -32,19 +41,134 @@ type MediaCard struct {
 	Providers []string `json:"providers"`
 	WhyWatch  string   `json:"why_watch"`
 	Overview  string   `json:"overview"`
+	InLibrary bool     `json:"in_library"`
+	BingeTime int      `json:"binge_minutes"` // TV only, 0 if unknown
+	Language  string   `json:"original_language"`
+
+	// Same-titled entries of the other media type, collapsed into this card
+	Alternates []MediaCard `json:"-"`
 }
 
 // CardSelection tracks which card is currently selected
 type CardSelection struct {
-	ItemIndex  int // Which DisplayItem contains the cards
-	CardIndex  int // Which card within that group is selected
-	TotalCards int // Total cards in current group
+	ItemIndex  int          // Which DisplayItem contains the cards
+	CardIndex  int          // Which card within that group is selected
+	TotalCards int          // Total cards in current group
+	Marked     map[int]bool // Cards toggled for batch actions (multi-select)
+	Filter     string       // Provider the group is narrowed to ("" = all cards shown)
+	Visible    []int        // Indices of the cards available on Filter, in display order
+}
+
+// SetFilter shows only the cards available on provider ("" shows them all).
+// A selected card that gets hidden moves to the first one still shown.
+func (s *CardSelection) SetFilter(cards []MediaCard, provider string) {
+	s.Filter = provider
+	s.Visible = nil
+	if provider == "" {
+		return
+	}
+	for i, card := range cards {
+		if cardHasProvider(card, provider) {
+			s.Visible = append(s.Visible, i)
+		}
+	}
+	if !s.IsVisible(s.CardIndex) && len(s.Visible) > 0 {
+		s.CardIndex = s.Visible[0]
+	}
+}
+
+// IsVisible checks if a card is shown under the current filter
+func (s *CardSelection) IsVisible(idx int) bool {
+	return s.Filter == "" || slices.Contains(s.Visible, idx)
+}
+
+// VisibleIndices returns the indices of the shown cards in display order
+func (s *CardSelection) VisibleIndices() []int {
+	if s.Filter != "" {
+		return s.Visible
+	}
+	indices := make([]int, s.TotalCards)
+	for i := range indices {
+		indices[i] = i
+	}
+	return indices
+}
+
+// ToggleMarked toggles the multi-select mark on a card
+func (s *CardSelection) ToggleMarked(idx int) {
+	if s.Marked == nil {
+		s.Marked = make(map[int]bool)
+	}
+	if s.Marked[idx] {
+		delete(s.Marked, idx)
+	} else {
+		s.Marked[idx] = true
+	}
+}
+
+// IsMarked checks if a card is marked for batch actions
+func (s *CardSelection) IsMarked(idx int) bool {
+	return s.Marked[idx]
+}
+
+// MarkedIndices returns the marked card indices in display order
+func (s *CardSelection) MarkedIndices() []int {
+	indices := make([]int, 0, len(s.Marked))
+	for i := 0; i < s.TotalCards; i++ {
+		if s.Marked[i] && s.IsVisible(i) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// cardHasProvider checks if a card, or a title collapsed into it, is available on provider
+func cardHasProvider(card MediaCard, provider string) bool {
+	if slices.Contains(card.Providers, provider) {
+		return true
+	}
+	for _, alt := range card.Alternates {
+		if slices.Contains(alt.Providers, provider) {
+			return true
+		}
+	}
+	return false
+}
+
+// CardProviders returns the providers offered across a card group, most common first
+func CardProviders(cards []MediaCard) []string {
+	counts := make(map[string]int)
+	var providers []string
+	for _, card := range cards {
+		seen := make(map[string]bool)
+		for _, c := range append([]MediaCard{card}, card.Alternates...) {
+			for _, p := range c.Providers {
+				if seen[p] {
+					continue
+				}
+				seen[p] = true
+				if counts[p] == 0 {
+					providers = append(providers, p)
+				}
+				counts[p]++
+			}
+		}
+	}
+	sort.SliceStable(providers, func(i, j int) bool {
+		return counts[providers[i]] > counts[providers[j]]
+	})
+	return providers
 }
 
 // MediaTools lists tools that return media results
 var MediaTools = map[string]bool{
 	"search_media":             true,
 	"get_similar":              true,
+	"blend_tastes":             true,
+	"get_franchise":            true,
+	"get_director_films":       true,
+	"get_trakt_watchlist":      true,
+	"get_trakt_history":        true,
 	"search_by_title":          true,
 	"generate_recommendations": true,
 }
@@ -65,6 +189,30 @@ type tmdbMediaResult struct {
 	VoteCount int      `json:"vote_count"`
 	Overview  string   `json:"overview"`
 	Providers []string `json:"providers"`
+	InLibrary bool     `json:"in_library"`
+	BingeTime int      `json:"binge_minutes"`
+	Language  string   `json:"original_language"`
+}
+
+// franchiseResult represents the JSON format from the get_franchise tool. The
+// get_director_films result has the same titles list and parses as one too.
+type franchiseResult struct {
+	Collection string            `json:"collection"`
+	WatchOrder string            `json:"watch_order"`
+	Titles     []tmdbMediaResult `json:"titles"`
+}
+
+// traktItemResult represents the JSON format from the Trakt watchlist and history tools
+type traktItemResult struct {
+	Type      string   `json:"type"` // "movie", "show", or "episode"
+	Title     string   `json:"title"`
+	Year      int      `json:"year"`
+	Rating    float64  `json:"rating"`
+	Overview  string   `json:"overview"`
+	Genres    []string `json:"genres"`
+	TMDBID    int      `json:"tmdb_id"`
+	MediaType string   `json:"media_type"`
+	Episode   string   `json:"episode"` // history only, e.g. "S01E02 Pilot"
 }
 
 // aiRecommendationResult represents the JSON format from AI recommendation tool
@@ -79,35 +227,31 @@ type aiRecommendationResult struct {
 		Overview  string   `json:"overview"`
 		WhyWatch  string   `json:"why_watch"`
 		Providers []string `json:"providers"`
+		Language  string   `json:"language"`
 	} `json:"recommendations"`
 }
 
 // ParseMediaCards attempts to parse JSON tool result into MediaCards
-// It handles both TMDb array format and AI recommendation format
+// It handles TMDb, Trakt watchlist/history, franchise, and AI recommendation formats
 func ParseMediaCards(jsonStr string) ([]MediaCard, error) {
 	jsonStr = strings.TrimSpace(jsonStr)
 
 	// Try parsing as TMDb array format first
 	var tmdbResults []tmdbMediaResult
 	if err := json.Unmarshal([]byte(jsonStr), &tmdbResults); err == nil && len(tmdbResults) > 0 {
-		cards := make([]MediaCard, 0, len(tmdbResults))
-		for _, r := range tmdbResults {
-			title := r.Title
-			if title == "" {
-				title = r.Name // Use Name for TV shows
-			}
-			cards = append(cards, MediaCard{
-				ID:        r.ID,
-				Title:     title,
-				Year:      r.Year,
-				MediaType: r.MediaType,
-				Rating:    r.Rating,
-				VoteCount: r.VoteCount,
-				Overview:  r.Overview,
-				Providers: r.Providers,
-			})
-		}
-		return cards, nil
+		return tmdbCards(tmdbResults), nil
+	}
+
+	// Try parsing as Trakt watchlist/history format (integer years, "type" field)
+	var traktResults []traktItemResult
+	if err := json.Unmarshal([]byte(jsonStr), &traktResults); err == nil && len(traktResults) > 0 && traktResults[0].Type != "" {
+		return traktCards(traktResults), nil
+	}
+
+	// Try parsing as franchise format (films already in watch order)
+	var franchise franchiseResult
+	if err := json.Unmarshal([]byte(jsonStr), &franchise); err == nil && len(franchise.Titles) > 0 {
+		return tmdbCards(franchise.Titles), nil
 	}
 
 	// Try parsing as AI recommendation format
@@ -123,6 +267,7 @@ func ParseMediaCards(jsonStr string) ([]MediaCard, error) {
 				Overview:  r.Overview,
 				WhyWatch:  r.WhyWatch,
 				Providers: r.Providers,
+				Language:  r.Language,
 			})
 		}
 		return cards, nil
@@ -132,6 +277,152 @@ func ParseMediaCards(jsonStr string) ([]MediaCard, error) {
 	return nil, nil
 }
 
+// tmdbCards converts TMDb-format tool results into MediaCards
+func tmdbCards(results []tmdbMediaResult) []MediaCard {
+	cards := make([]MediaCard, 0, len(results))
+	for _, r := range results {
+		title := r.Title
+		if title == "" {
+			title = r.Name // Use Name for TV shows
+		}
+		cards = append(cards, MediaCard{
+			ID:        r.ID,
+			Title:     title,
+			Year:      r.Year,
+			MediaType: r.MediaType,
+			Rating:    r.Rating,
+			VoteCount: r.VoteCount,
+			Overview:  r.Overview,
+			Providers: r.Providers,
+			InLibrary: r.InLibrary,
+			BingeTime: r.BingeTime,
+			Language:  r.Language,
+		})
+	}
+	return cards
+}
+
+// traktCards converts Trakt watchlist/history results into MediaCards. History
+// lists every episode watched, so a show appears once, with its latest episode.
+func traktCards(results []traktItemResult) []MediaCard {
+	cards := make([]MediaCard, 0, len(results))
+	seen := make(map[string]bool)
+	for _, r := range results {
+		mediaType := r.MediaType
+		if mediaType == "" {
+			mediaType = "movie"
+			if r.Type != "movie" {
+				mediaType = "tv"
+			}
+		}
+		key := fmt.Sprintf("%s-%s-%d", mediaType, r.Title, r.Year)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		card := MediaCard{
+			ID:        r.TMDBID,
+			Title:     r.Title,
+			MediaType: mediaType,
+			Rating:    r.Rating,
+			Overview:  r.Overview,
+		}
+		if r.Year > 0 {
+			card.Year = strconv.Itoa(r.Year)
+		}
+		if card.Overview == "" && r.Episode != "" {
+			card.Overview = "Last watched: " + r.Episode
+		}
+		cards = append(cards, card)
+	}
+	return cards
+}
+
+// cardReferencePattern matches "#2"-style references to numbered cards
+var cardReferencePattern = regexp.MustCompile(`#(\d+)\b`)
+
+// CardReferenceNote describes the cards a message refers to by number ("tell me
+// more about #2"), so the model can call tools with their IDs directly instead
+// of searching again. cards is the latest card group, numbered from 1 as shown.
+// Returns "" if the message references no displayed card.
+func CardReferenceNote(content string, cards []MediaCard) string {
+	seen := make(map[int]bool)
+	var refs []string
+	for _, match := range cardReferencePattern.FindAllStringSubmatch(content, -1) {
+		n, err := strconv.Atoi(match[1])
+		if err != nil || n < 1 || n > len(cards) || seen[n] {
+			continue
+		}
+		seen[n] = true
+
+		refs = append(refs, describeCardReference(n, cards[n-1]))
+		for _, alt := range cards[n-1].Alternates {
+			refs = append(refs, "  also "+describeCardReference(n, alt))
+		}
+	}
+	if len(refs) == 0 {
+		return ""
+	}
+	return "[Numbered cards referenced above, from the most recent results:\n" + strings.Join(refs, "\n") + "]"
+}
+
+// describeCardReference formats one card for CardReferenceNote
+func describeCardReference(n int, card MediaCard) string {
+	desc := fmt.Sprintf("#%d = %s", n, card.Title)
+	if card.Year != "" {
+		desc += " (" + card.Year + ")"
+	}
+	if card.ID == 0 {
+		return desc + ", no TMDb ID (use search_by_title to look it up)"
+	}
+	return fmt.Sprintf("%s, media_type %s, TMDb ID %d", desc, card.MediaType, card.ID)
+}
+
+// CollapseDuplicateCards merges cards that share a title but differ in media type
+// (e.g. a movie and its TV adaptation) into the first occurrence's Alternates
+func CollapseDuplicateCards(cards []MediaCard) []MediaCard {
+	result := make([]MediaCard, 0, len(cards))
+	byTitle := make(map[string]int)
+
+	for _, card := range cards {
+		key := normalizeTitle(card.Title)
+		if idx, ok := byTitle[key]; ok && key != "" && canCollapse(result[idx], card) {
+			result[idx].Alternates = append(result[idx].Alternates, card)
+			continue
+		}
+		byTitle[key] = len(result)
+		result = append(result, card)
+	}
+
+	return result
+}
+
+// canCollapse only merges across media types; same-type entries (remakes) stay separate
+func canCollapse(primary, card MediaCard) bool {
+	if primary.MediaType == card.MediaType {
+		return false
+	}
+	for _, alt := range primary.Alternates {
+		if alt.MediaType == card.MediaType {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeTitle lowercases a title and strips punctuation and a leading "the"
+func normalizeTitle(title string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == ' ' {
+			sb.WriteRune(r)
+		}
+	}
+	normalized := strings.Join(strings.Fields(sb.String()), " ")
+	return strings.TrimPrefix(normalized, "the ")
+}
+
 // NewTextDisplayItem creates a DisplayItem for plain text
 func NewTextDisplayItem(text string) DisplayItem {
 	return DisplayItem{
@@ -1,136 +1,179 @@
 package tui
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/charmbracelet/lipgloss"
+
+	"wtfsiw/internal/ai/tools"
+	"wtfsiw/internal/textutil"
+	"wtfsiw/internal/tmdb"
 )
 
+// Chat styles, built from the active palette
 var (
+	chatContainerStyle      lipgloss.Style
+	chatHeaderStyle         lipgloss.Style
+	userMsgStyle            lipgloss.Style
+	userLabelStyle          lipgloss.Style
+	assistantMsgStyle       lipgloss.Style
+	assistantLabelStyle     lipgloss.Style
+	toolMsgStyle            lipgloss.Style
+	toolLabelStyle          lipgloss.Style
+	systemMsgStyle          lipgloss.Style
+	chatInputContainerStyle lipgloss.Style
+	chatInputStyle          lipgloss.Style
+	thinkingStyle           lipgloss.Style
+	toolExecutingStyle      lipgloss.Style
+	chatHelpStyle           lipgloss.Style
+	scrollIndicatorStyle    lipgloss.Style
+	viewportFocusStyle      lipgloss.Style
+	cardContainerStyle      lipgloss.Style
+	cardSelectedStyle       lipgloss.Style
+	cardTitleStyle          lipgloss.Style
+	cardYearStyle           lipgloss.Style
+	cardRatingStyle         lipgloss.Style
+	cardProviderStyle       lipgloss.Style
+	cardWhyWatchStyle       lipgloss.Style
+	cardMarkedStyle         lipgloss.Style
+	cardIndexStyle          lipgloss.Style
+	cardHeaderStyle         lipgloss.Style
+)
+
+// buildChatStyles (re)creates the chat styles from the active palette
+func buildChatStyles() {
 	// Chat container
 	chatContainerStyle = lipgloss.NewStyle().
-				Padding(1, 2)
+		Padding(1, 2)
 
 	// Chat header
 	chatHeaderStyle = lipgloss.NewStyle().
-			Foreground(mauve).
-			Bold(true).
-			Border(lipgloss.NormalBorder(), false, false, true, false).
-			BorderForeground(surface1).
-			PaddingBottom(1).
-			MarginBottom(1)
+		Foreground(mauve).
+		Bold(true).
+		Border(lipgloss.NormalBorder(), false, false, true, false).
+		BorderForeground(surface1).
+		PaddingBottom(1).
+		MarginBottom(1)
 
 	// Message styles
 	userMsgStyle = lipgloss.NewStyle().
-			Foreground(text).
-			PaddingLeft(2)
+		Foreground(text).
+		PaddingLeft(2)
 
 	userLabelStyle = lipgloss.NewStyle().
-			Foreground(sapphire).
-			Bold(true)
+		Foreground(sapphire).
+		Bold(true)
 
 	assistantMsgStyle = lipgloss.NewStyle().
-				Foreground(text).
-				PaddingLeft(2)
+		Foreground(text).
+		PaddingLeft(2)
 
 	assistantLabelStyle = lipgloss.NewStyle().
-				Foreground(lavender).
-				Bold(true)
+		Foreground(lavender).
+		Bold(true)
 
 	toolMsgStyle = lipgloss.NewStyle().
-			Foreground(subtext0).
-			Italic(true).
-			PaddingLeft(4)
+		Foreground(subtext0).
+		Italic(true).
+		PaddingLeft(4)
 
 	toolLabelStyle = lipgloss.NewStyle().
-			Foreground(peach).
-			Bold(true)
+		Foreground(peach).
+		Bold(true)
 
 	systemMsgStyle = lipgloss.NewStyle().
-			Foreground(overlay1).
-			Italic(true).
-			Align(lipgloss.Center)
+		Foreground(overlay1).
+		Italic(true).
+		Align(lipgloss.Center)
 
 	// Input area
 	chatInputContainerStyle = lipgloss.NewStyle().
-				Border(lipgloss.NormalBorder(), true, false, false, false).
-				BorderForeground(surface1).
-				PaddingTop(1).
-				MarginTop(1)
+		Border(lipgloss.NormalBorder(), true, false, false, false).
+		BorderForeground(surface1).
+		PaddingTop(1).
+		MarginTop(1)
 
 	chatInputStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(surface2).
-			Padding(0, 1)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(surface2).
+		Padding(0, 1)
 
 	// Thinking/loading indicator
 	thinkingStyle = lipgloss.NewStyle().
-			Foreground(lavender).
-			Italic(true).
-			PaddingLeft(2)
+		Foreground(lavender).
+		Italic(true).
+		PaddingLeft(2)
 
 	// Tool execution indicator
 	toolExecutingStyle = lipgloss.NewStyle().
-				Foreground(peach).
-				Bold(true).
-				PaddingLeft(4)
+		Foreground(peach).
+		Bold(true).
+		PaddingLeft(4)
 
 	// Chat footer/help
 	chatHelpStyle = lipgloss.NewStyle().
-			Foreground(overlay1).
-			MarginTop(1).
-			Align(lipgloss.Center)
+		Foreground(overlay1).
+		MarginTop(1).
+		Align(lipgloss.Center)
 
 	// Scroll indicator
 	scrollIndicatorStyle = lipgloss.NewStyle().
-				Foreground(overlay0).
-				Align(lipgloss.Right)
+		Foreground(overlay0).
+		Align(lipgloss.Right)
 
 	// Viewport focus style (highlighted border when scrolling)
 	viewportFocusStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(mauve)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(mauve)
 
 	// Media card styles
 	cardContainerStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(surface2).
-				Padding(0, 1).
-				MarginLeft(2)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(surface2).
+		Padding(0, 1).
+		MarginLeft(2)
 
 	cardSelectedStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(mauve).
-				Padding(0, 1).
-				MarginLeft(2)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(mauve).
+		Padding(0, 1).
+		MarginLeft(2)
 
 	cardTitleStyle = lipgloss.NewStyle().
-			Foreground(yellow).
-			Bold(true)
+		Foreground(yellow).
+		Bold(true)
 
 	cardYearStyle = lipgloss.NewStyle().
-			Foreground(subtext0)
+		Foreground(subtext0)
 
 	cardRatingStyle = lipgloss.NewStyle().
-			Foreground(yellow)
+		Foreground(yellow)
 
 	cardProviderStyle = lipgloss.NewStyle().
-				Foreground(base).
-				Background(teal).
-				Padding(0, 1).
-				MarginRight(1)
+		Foreground(base).
+		Background(teal).
+		Padding(0, 1).
+		MarginRight(1)
 
 	cardWhyWatchStyle = lipgloss.NewStyle().
-				Foreground(green).
-				Italic(true)
+		Foreground(green).
+		Italic(true)
+
+	cardMarkedStyle = lipgloss.NewStyle().
+		Foreground(green).
+		Bold(true)
 
 	cardIndexStyle = lipgloss.NewStyle().
-			Foreground(mauve).
-			Bold(true)
+		Foreground(mauve).
+		Bold(true)
 
 	cardHeaderStyle = lipgloss.NewStyle().
-			Foreground(lavender).
-			Italic(true).
-			MarginBottom(1)
-)
+		Foreground(lavender).
+		Italic(true).
+		MarginBottom(1)
+}
 
 // FormatUserMessage formats a user message for display
 func FormatUserMessage(content string) string {
@@ -147,6 +190,145 @@ func FormatToolCall(name string) string {
 	return toolLabelStyle.Render("  → ") + toolMsgStyle.Render(name)
 }
 
+// toolActivities describe what each tool is doing, for the progress line
+var toolActivities = map[string]string{
+	"search_media":                      "searching TMDb",
+	"blend_tastes":                      "blending tastes",
+	"get_media_details":                 "fetching details",
+	"get_streaming_providers":           "fetching providers",
+	"get_streaming_providers_batch":     "fetching providers",
+	"get_streaming_providers_by_region": "comparing regions",
+	"get_certifications":                "fetching age ratings",
+	"get_season":                        "fetching episodes",
+	"get_similar":                       "finding similar titles",
+	"search_by_title":                   "looking up titles",
+	"get_franchise":                     "fetching the franchise",
+	"get_director_films":                "fetching the filmography",
+	"list_filters":                      "listing filters",
+	"get_trakt_watchlist":               "reading your watchlist",
+	"get_trakt_history":                 "reading your history",
+	"get_next_episode":                  "checking your progress",
+	"generate_recommendations":          "asking the AI for ideas",
+}
+
+// toolActivity describes one round of tool calls, e.g. "fetching details + fetching providers"
+func toolActivity(names []string) string {
+	var activities []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		activity, ok := toolActivities[name]
+		if !ok {
+			activity = "running " + name
+		}
+		if !seen[activity] {
+			seen[activity] = true
+			activities = append(activities, activity)
+		}
+	}
+	return strings.Join(activities, " + ")
+}
+
+// maxProgressSteps caps how many earlier steps the progress line lists
+const maxProgressSteps = 3
+
+// FormatToolProgress describes the tool rounds of the current reply, the last
+// one being in progress: "Step 2: fetching providers (after searching TMDb)".
+// Tool rounds chained by the model can take a while, so earlier steps are
+// kept as a breadcrumb.
+func FormatToolProgress(rounds [][]string) string {
+	if len(rounds) == 0 {
+		return "Working"
+	}
+	current := fmt.Sprintf("Step %d: %s", len(rounds), toolActivity(rounds[len(rounds)-1]))
+	if len(rounds) == 1 {
+		return current
+	}
+
+	var previous []string
+	earlier := rounds[:len(rounds)-1]
+	if len(earlier) > maxProgressSteps {
+		earlier = earlier[len(earlier)-maxProgressSteps:]
+		previous = append(previous, "…")
+	}
+	for _, names := range earlier {
+		previous = append(previous, toolActivity(names))
+	}
+	return fmt.Sprintf("%s (after %s)", current, strings.Join(previous, " → "))
+}
+
+// FormatSearchFilters summarizes the filters a search_media call asked for,
+// e.g. "Genre: thriller · 2015-2024 · ≥7.5 · Netflix". Returns "" when the
+// call has no filters.
+func FormatSearchFilters(tc tools.ToolCall) string {
+	var parts []string
+	list := func(label, key string) {
+		if values := tc.GetStringArray(key); len(values) > 0 {
+			parts = append(parts, label+strings.Join(values, ", "))
+		}
+	}
+
+	list("", "keywords")
+	switch tc.GetString("media_type") {
+	case "movie":
+		parts = append(parts, "Movies")
+	case "tv":
+		parts = append(parts, "TV")
+	}
+	list("Genre: ", "genres")
+	list("Not: ", "exclude_genres")
+
+	from, to := tc.GetInt("year_from"), tc.GetInt("year_to")
+	if year := tc.GetInt("year"); year > 0 {
+		from, to = year, year
+	}
+	switch {
+	case from > 0 && to > 0 && from == to:
+		parts = append(parts, strconv.Itoa(from))
+	case from > 0 && to > 0:
+		parts = append(parts, strconv.Itoa(from)+"-"+strconv.Itoa(to))
+	case from > 0:
+		parts = append(parts, "from "+strconv.Itoa(from))
+	case to > 0:
+		parts = append(parts, "up to "+strconv.Itoa(to))
+	}
+
+	if rating := tc.GetFloat("min_rating"); rating > 0 {
+		parts = append(parts, "≥"+strconv.FormatFloat(rating, 'g', -1, 64))
+	}
+	if popularity := tc.GetFloat("min_popularity"); popularity > 0 {
+		parts = append(parts, "popularity ≥"+strconv.FormatFloat(popularity, 'g', -1, 64))
+	}
+	if quality := tc.GetString("quality"); quality != "" {
+		parts = append(parts, "Quality: "+quality)
+	}
+	if lang := tc.GetString("language"); lang != "" {
+		parts = append(parts, "Language: "+lang)
+	}
+	list("With: ", "actors")
+	list("Studio: ", "studios")
+	list("Network: ", "networks")
+	if providers := tc.GetStringArray("providers"); len(providers) > 0 && tc.GetString("provider_match") == tmdb.ProviderMatchAll {
+		parts = append(parts, "On all of: "+strings.Join(providers, ", "))
+	} else {
+		list("", "providers")
+	}
+	list("", "monetization_types")
+	switch sortBy := tc.GetString("sort_by"); sortBy {
+	case "":
+	case "release_date":
+		parts = append(parts, "newest first")
+	case "oldest":
+		parts = append(parts, "oldest first")
+	default:
+		parts = append(parts, "by "+strings.ReplaceAll(sortBy, "_", " "))
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+	return toolLabelStyle.Render("  ⌕ ") + toolMsgStyle.Render(strings.Join(parts, " · "))
+}
+
 // FormatToolResult formats a tool result summary for display
 func FormatToolResult(name string, success bool) string {
 	if success {
@@ -169,10 +351,11 @@ func FormatSystemMessage(content string) string {
 
 // RenderMediaCard renders a single media card in compact format
 // Format:
-//   [idx] 🎬 Title (Year)  ★★★★☆ 8.2
-//        Netflix  Prime
-//        💡 Why watch text...
-func RenderMediaCard(card MediaCard, index int, selected bool, width int) string {
+//
+//	[idx] 🎬 Title (Year)  ★★★★☆ 8.2
+//	     Netflix  Prime
+//	     💡 Why watch text...
+func RenderMediaCard(card MediaCard, index int, selected bool, marked bool, width int) string {
 	// Media type emoji
 	emoji := "🎬"
 	if card.MediaType == "tv" {
@@ -183,11 +366,28 @@ func RenderMediaCard(card MediaCard, index int, selected bool, width int) string
 	indexStr := cardIndexStyle.Render(intToStr(index) + ".")
 	title := cardTitleStyle.Render(card.Title)
 	year := cardYearStyle.Render("(" + card.Year + ")")
-	rating := cardRatingStyle.Render(renderStars(card.Rating) + " " + formatFloat(card.Rating))
+	rating := cardRatingStyle.Render(renderStars(card.Rating))
+	if card.Rating > 0 {
+		rating = cardRatingStyle.Render(renderStars(card.Rating) + " " + formatFloat(card.Rating))
+	}
 
 	line1 := indexStr + " " + emoji + " " + title + " " + year + "  " + rating
+	if card.InLibrary {
+		line1 += "  💾"
+	}
+	for _, alt := range card.Alternates {
+		// Collapsed duplicate of the other media type
+		altEmoji := "🎬"
+		if alt.MediaType == "tv" {
+			altEmoji = "📺"
+		}
+		line1 += "  " + cardYearStyle.Render("+ "+altEmoji+" ("+alt.Year+")")
+	}
+	if marked {
+		line1 = cardMarkedStyle.Render("✓") + " " + line1
+	}
 
-	// Line 2: Providers (if any)
+	// Line 2: Providers, binge time, and original language (if any)
 	var line2 string
 	if len(card.Providers) > 0 {
 		line2 = "   "
@@ -199,6 +399,20 @@ func RenderMediaCard(card MediaCard, index int, selected bool, width int) string
 			line2 += cardProviderStyle.Render(p) + " "
 		}
 	}
+	if card.BingeTime > 0 {
+		if line2 == "" {
+			line2 = "   "
+		}
+		line2 += cardYearStyle.Render("⏱ " + tmdb.BingeLabel(card.BingeTime))
+	}
+	if note := tmdb.LanguageNote(card.Language); note != "" {
+		if line2 == "" {
+			line2 = "   "
+		} else {
+			line2 += " "
+		}
+		line2 += cardYearStyle.Render("🗣 " + note)
+	}
 
 	// Line 3: Why watch (if present, truncated)
 	var line3 string
@@ -208,9 +422,7 @@ func RenderMediaCard(card MediaCard, index int, selected bool, width int) string
 		if maxLen < 30 {
 			maxLen = 30
 		}
-		if len(why) > maxLen {
-			why = why[:maxLen-3] + "..."
-		}
+		why = textutil.Truncate(why, maxLen)
 		line3 = "   " + cardWhyWatchStyle.Render("💡 "+why)
 	}
 
@@ -237,23 +449,27 @@ func RenderMediaCardGroup(cards []MediaCard, selection *CardSelection, itemIndex
 	}
 
 	var result string
+	inGroup := selection != nil && selection.ItemIndex == itemIndex
 
 	// Header
 	countText := intToStr(len(cards))
-	if len(cards) == 1 {
+	switch {
+	case inGroup && selection.Filter != "":
+		result = cardHeaderStyle.Render("Showing " + intToStr(len(selection.Visible)) + " of " + countText + " on " + selection.Filter + ":")
+	case len(cards) == 1:
 		result = cardHeaderStyle.Render("Found " + countText + " result:")
-	} else {
+	default:
 		result = cardHeaderStyle.Render("Found " + countText + " results:")
 	}
-	result += "\n"
 
-	// Render each card
+	// Render each card, keeping its number when others are filtered out
 	for i, card := range cards {
-		isSelected := selection != nil && selection.ItemIndex == itemIndex && selection.CardIndex == i
-		result += RenderMediaCard(card, i+1, isSelected, width)
-		if i < len(cards)-1 {
-			result += "\n"
+		if inGroup && !selection.IsVisible(i) {
+			continue
 		}
+		isSelected := inGroup && selection.CardIndex == i
+		isMarked := inGroup && selection.IsMarked(i)
+		result += "\n" + RenderMediaCard(card, i+1, isSelected, isMarked, width)
 	}
 
 	return result
@@ -1,160 +1,244 @@
 package tui
 
 import (
+	"strconv"
+
 	"github.com/charmbracelet/lipgloss"
+
+	"wtfsiw/internal/theme"
 )
 
-// Catppuccin Mocha color palette
+// Active color palette (Catppuccin Mocha by default, see ApplyTheme)
 var (
 	// Accent colors
-	rosewater = lipgloss.Color("#f5e0dc")
-	flamingo  = lipgloss.Color("#f2cdcd")
-	pink      = lipgloss.Color("#f5c2e7")
-	mauve     = lipgloss.Color("#cba6f7")
-	red       = lipgloss.Color("#f38ba8")
-	maroon    = lipgloss.Color("#eba0ac")
-	peach     = lipgloss.Color("#fab387")
-	yellow    = lipgloss.Color("#f9e2af")
-	green     = lipgloss.Color("#a6e3a1")
-	teal      = lipgloss.Color("#94e2d5")
-	sky       = lipgloss.Color("#89dceb")
-	sapphire  = lipgloss.Color("#74c7ec")
-	blue      = lipgloss.Color("#89b4fa")
-	lavender  = lipgloss.Color("#b4befe")
+	rosewater lipgloss.TerminalColor
+	flamingo  lipgloss.TerminalColor
+	pink      lipgloss.TerminalColor
+	mauve     lipgloss.TerminalColor
+	red       lipgloss.TerminalColor
+	maroon    lipgloss.TerminalColor
+	peach     lipgloss.TerminalColor
+	yellow    lipgloss.TerminalColor
+	green     lipgloss.TerminalColor
+	teal      lipgloss.TerminalColor
+	sky       lipgloss.TerminalColor
+	sapphire  lipgloss.TerminalColor
+	blue      lipgloss.TerminalColor
+	lavender  lipgloss.TerminalColor
 
 	// Text colors
-	text     = lipgloss.Color("#cdd6f4")
-	subtext1 = lipgloss.Color("#bac2de")
-	subtext0 = lipgloss.Color("#a6adc8")
+	text     lipgloss.TerminalColor
+	subtext1 lipgloss.TerminalColor
+	subtext0 lipgloss.TerminalColor
 
 	// Overlay colors
-	overlay2 = lipgloss.Color("#9399b2")
-	overlay1 = lipgloss.Color("#7f849c")
-	overlay0 = lipgloss.Color("#6c7086")
+	overlay2 lipgloss.TerminalColor
+	overlay1 lipgloss.TerminalColor
+	overlay0 lipgloss.TerminalColor
 
 	// Surface colors
-	surface2 = lipgloss.Color("#585b70")
-	surface1 = lipgloss.Color("#45475a")
-	surface0 = lipgloss.Color("#313244")
+	surface2 lipgloss.TerminalColor
+	surface1 lipgloss.TerminalColor
+	surface0 lipgloss.TerminalColor
 
 	// Base colors
-	base   = lipgloss.Color("#1e1e2e")
-	mantle = lipgloss.Color("#181825")
-	crust  = lipgloss.Color("#11111b")
+	base   lipgloss.TerminalColor
+	mantle lipgloss.TerminalColor
+	crust  lipgloss.TerminalColor
 )
 
-// Semantic color aliases
+// Semantic color aliases and styles, built from the active palette
 var (
-	primaryColor   = mauve
+	primaryColor   lipgloss.TerminalColor
+	secondaryColor lipgloss.TerminalColor
+	accentColor    lipgloss.TerminalColor
+	mutedColor     lipgloss.TerminalColor
+	bgColor        lipgloss.TerminalColor
+	cardBgColor    lipgloss.TerminalColor
+
+	appStyle          lipgloss.Style
+	titleStyle        lipgloss.Style
+	subtitleStyle     lipgloss.Style
+	inputStyle        lipgloss.Style
+	inputPromptStyle  lipgloss.Style
+	listItemStyle     lipgloss.Style
+	selectedItemStyle lipgloss.Style
+	cardStyle         lipgloss.Style
+	mediaTitleStyle   lipgloss.Style
+	mediaYearStyle    lipgloss.Style
+	mediaTypeStyle    lipgloss.Style
+	ratingStyle       lipgloss.Style
+	overviewStyle     lipgloss.Style
+	providerStyle     lipgloss.Style
+	spinnerStyle      lipgloss.Style
+	statusStyle       lipgloss.Style
+	errorStyle        lipgloss.Style
+	helpStyle         lipgloss.Style
+)
+
+// buildStyles (re)creates the shared styles from the active palette
+func buildStyles() {
+	primaryColor = mauve
 	secondaryColor = teal
-	accentColor    = yellow
-	mutedColor     = overlay1
-	bgColor        = base
-	cardBgColor    = surface0
+	accentColor = yellow
+	mutedColor = overlay1
+	bgColor = base
+	cardBgColor = surface0
 
 	// App container
 	appStyle = lipgloss.NewStyle().
-			Padding(1, 2)
+		Padding(1, 2)
 
 	// Title/header
 	titleStyle = lipgloss.NewStyle().
-			Foreground(mauve).
-			Bold(true).
-			MarginBottom(1)
+		Foreground(mauve).
+		Bold(true).
+		MarginBottom(1)
 
 	// Subtitle
 	subtitleStyle = lipgloss.NewStyle().
-			Foreground(subtext0).
-			Italic(true)
+		Foreground(subtext0).
+		Italic(true)
 
 	// Input
 	inputStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(surface2).
-			Padding(0, 1).
-			MarginBottom(1)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(surface2).
+		Padding(0, 1).
+		MarginBottom(1)
 
 	inputPromptStyle = lipgloss.NewStyle().
-				Foreground(teal).
-				Bold(true)
+		Foreground(teal).
+		Bold(true)
 
 	// Results list
 	listItemStyle = lipgloss.NewStyle().
-			PaddingLeft(2)
+		PaddingLeft(2)
 
 	selectedItemStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder(), false, false, false, true).
-				BorderForeground(mauve).
-				PaddingLeft(1).
-				Foreground(lavender)
+		Border(lipgloss.RoundedBorder(), false, false, false, true).
+		BorderForeground(mauve).
+		PaddingLeft(1).
+		Foreground(lavender)
 
 	// Media card
 	cardStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(surface2).
-			Padding(1, 2).
-			MarginBottom(1)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(surface2).
+		Padding(1, 2).
+		MarginBottom(1)
 
 	mediaTitleStyle = lipgloss.NewStyle().
-			Foreground(yellow).
-			Bold(true)
+		Foreground(yellow).
+		Bold(true)
 
 	mediaYearStyle = lipgloss.NewStyle().
-			Foreground(subtext0)
+		Foreground(subtext0)
 
 	mediaTypeStyle = lipgloss.NewStyle().
-			Foreground(base).
-			Background(mauve).
-			Padding(0, 1)
+		Foreground(base).
+		Background(mauve).
+		Padding(0, 1)
 
 	ratingStyle = lipgloss.NewStyle().
-			Foreground(yellow).
-			Bold(true)
+		Foreground(yellow).
+		Bold(true)
 
 	overviewStyle = lipgloss.NewStyle().
-			Foreground(text).
-			MarginTop(1)
+		Foreground(text).
+		MarginTop(1)
 
 	// Providers
 	providerStyle = lipgloss.NewStyle().
-			Foreground(base).
-			Background(teal).
-			Padding(0, 1).
-			MarginRight(1)
+		Foreground(base).
+		Background(teal).
+		Padding(0, 1).
+		MarginRight(1)
 
 	// Status/loading
 	spinnerStyle = lipgloss.NewStyle().
-			Foreground(mauve)
+		Foreground(mauve)
 
 	statusStyle = lipgloss.NewStyle().
-			Foreground(subtext0).
-			Italic(true)
+		Foreground(subtext0).
+		Italic(true)
 
 	// Error
 	errorStyle = lipgloss.NewStyle().
-			Foreground(red).
-			Bold(true)
+		Foreground(red).
+		Bold(true)
 
 	// Help
 	helpStyle = lipgloss.NewStyle().
-			Foreground(overlay1).
-			MarginTop(1)
-)
+		Foreground(overlay1).
+		MarginTop(1)
+}
+
+// ApplyTheme sets the active color palette and rebuilds all TUI styles
+func ApplyTheme(p theme.Palette) {
+	rosewater = p.Rosewater
+	flamingo = p.Flamingo
+	pink = p.Pink
+	mauve = p.Mauve
+	red = p.Red
+	maroon = p.Maroon
+	peach = p.Peach
+	yellow = p.Yellow
+	green = p.Green
+	teal = p.Teal
+	sky = p.Sky
+	sapphire = p.Sapphire
+	blue = p.Blue
+	lavender = p.Lavender
+	text = p.Text
+	subtext1 = p.Subtext1
+	subtext0 = p.Subtext0
+	overlay2 = p.Overlay2
+	overlay1 = p.Overlay1
+	overlay0 = p.Overlay0
+	surface2 = p.Surface2
+	surface1 = p.Surface1
+	surface0 = p.Surface0
+	base = p.Base
+	mantle = p.Mantle
+	crust = p.Crust
+
+	buildStyles()
+	buildChatStyles()
+}
+
+func init() {
+	ApplyTheme(theme.Mocha)
+}
 
 // RenderRating returns a formatted rating string with stars for detail view
 func RenderRating(rating float64) string {
+	if !(rating > 0) {
+		return ratingStyle.Render(renderStars(rating))
+	}
 	return ratingStyle.Render(renderStars(rating) + " " + formatRating(rating))
 }
 
 // RenderRatingCompact returns a compact rating for list view (stars + number)
 func RenderRatingCompact(rating float64) string {
-	if rating == 0 {
-		return ratingStyle.Render("☆ N/A")
+	if !(rating > 0) {
+		return ratingStyle.Render(renderStars(rating))
 	}
 	return ratingStyle.Render(renderStars(rating) + " " + formatFloat(rating))
 }
 
+// noRatingStars stands in for the stars of an unrated title (0, negative, or
+// NaN), the same width so rating columns stay aligned
+const noRatingStars = " N/A "
+
 func renderStars(rating float64) string {
+	if !(rating > 0) {
+		return noRatingStars
+	}
+	if rating > 10 {
+		rating = 10 // some sources and AI output go past 10
+	}
+
 	// Convert 0-10 scale to 0-5 stars
 	stars := int(rating / 2)
 	halfStar := (rating/2 - float64(stars)) >= 0.5
@@ -179,21 +263,11 @@ func formatRating(r float64) string {
 	return formatFloat(r) + "/10"
 }
 
+// formatFloat formats a rating with one decimal, rounded like the CLI's %.1f
 func formatFloat(f float64) string {
-	whole := int(f)
-	frac := int((f - float64(whole)) * 10)
-	return intToStr(whole) + "." + intToStr(frac)
+	return strconv.FormatFloat(f, 'f', 1, 64)
 }
 
 func intToStr(n int) string {
-	if n == 0 {
-		return "0"
-	}
-	digits := []byte{'0', '1', '2', '3', '4', '5', '6', '7', '8', '9'}
-	result := ""
-	for n > 0 {
-		result = string(digits[n%10]) + result
-		n /= 10
-	}
-	return result
+	return strconv.Itoa(n)
 }
@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFormatFloat(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want string
+	}{
+		{0, "0.0"},
+		{7, "7.0"},
+		{7.89, "7.9"},
+		{7.84, "7.8"},
+		{8.25, "8.2"}, // exact halves round to even, as with %.1f
+		{9.96, "10.0"},
+		{-1.5, "-1.5"},
+		{-0.04, "-0.0"},
+		{12345.67, "12345.7"},
+	}
+	for _, tt := range tests {
+		if got := formatFloat(tt.in); got != tt.want {
+			t.Errorf("formatFloat(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIntToStr(t *testing.T) {
+	tests := []struct {
+		in   int
+		want string
+	}{
+		{0, "0"},
+		{7, "7"},
+		{42, "42"},
+		{-3, "-3"},
+		{1000000, "1000000"},
+	}
+	for _, tt := range tests {
+		if got := intToStr(tt.in); got != tt.want {
+			t.Errorf("intToStr(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRenderStars(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want string
+	}{
+		{8, "★★★★☆"},
+		{7, "★★★✦☆"},
+		{10, "★★★★★"},
+		{14.2, "★★★★★"}, // clamped to 10
+		{0.5, "☆☆☆☆☆"},
+		{0, " N/A "},
+		{-3, " N/A "},
+		{math.NaN(), " N/A "},
+	}
+	for _, tt := range tests {
+		if got := renderStars(tt.in); got != tt.want {
+			t.Errorf("renderStars(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
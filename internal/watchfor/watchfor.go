@@ -0,0 +1,164 @@
+package watchfor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"wtfsiw/internal/config"
+	"wtfsiw/internal/tmdb"
+)
+
+// CheckInterval is how often a watched-for title is re-checked on TMDb
+const CheckInterval = 24 * time.Hour
+
+// Entry is a title the user wants to be told about once it can be watched
+type Entry struct {
+	TMDBID      int        `json:"tmdb_id"`
+	MediaType   string     `json:"media_type"`
+	Title       string     `json:"title"`
+	Year        string     `json:"year,omitempty"`
+	Providers   []string   `json:"providers,omitempty"` // only notify for these (empty = any)
+	AddedAt     time.Time  `json:"added_at"`
+	LastChecked time.Time  `json:"last_checked,omitempty"`
+	NotifiedAt  *time.Time `json:"notified_at,omitempty"` // set once available; no further checks
+}
+
+// Notice reports that a watched-for title has become available
+type Notice struct {
+	Entry     Entry
+	Providers []string
+	Link      string
+}
+
+// List is the persisted set of watched-for titles
+type List struct {
+	Entries []Entry `json:"entries"`
+}
+
+// Load reads the watch-for list from disk (an empty list if none exists yet)
+func Load() (*List, error) {
+	data, err := os.ReadFile(config.GetWatchForPath())
+	if os.IsNotExist(err) {
+		return &List{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watch-for file: %w", err)
+	}
+
+	var l List
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("failed to parse watch-for file: %w", err)
+	}
+	return &l, nil
+}
+
+// Save writes the watch-for list to disk
+func (l *List) Save() error {
+	path := config.GetWatchForPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal watch-for list: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write watch-for file: %w", err)
+	}
+	return nil
+}
+
+// Add records a new entry. Returns false if the title is already being watched for.
+func (l *List) Add(e Entry) bool {
+	for _, existing := range l.Entries {
+		if existing.TMDBID == e.TMDBID && existing.MediaType == e.MediaType {
+			return false
+		}
+	}
+	l.Entries = append(l.Entries, e)
+	return true
+}
+
+// Remove deletes the first entry whose title matches (case-insensitive)
+func (l *List) Remove(title string) (Entry, bool) {
+	for i, e := range l.Entries {
+		if strings.EqualFold(e.Title, strings.TrimSpace(title)) {
+			l.Entries = append(l.Entries[:i], l.Entries[i+1:]...)
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Check looks up providers for every entry that hasn't been notified and is due
+// (or all of them when force is set), and returns notices for newly available titles.
+// Reports whether any entry changed so the caller knows to save.
+func (l *List) Check(client *tmdb.Client, force bool) ([]Notice, bool) {
+	var notices []Notice
+	changed := false
+	now := time.Now()
+
+	for i := range l.Entries {
+		e := &l.Entries[i]
+		if e.NotifiedAt != nil {
+			continue
+		}
+		if !force && now.Sub(e.LastChecked) < CheckInterval {
+			continue
+		}
+
+		providers, link, err := client.RefreshWatchProviders(e.MediaType, e.TMDBID)
+		if err != nil {
+			continue // try again next run
+		}
+		e.LastChecked = now
+		changed = true
+
+		matched := matchProviders(providers, e.Providers)
+		if len(matched) == 0 {
+			continue
+		}
+		notifiedAt := now
+		e.NotifiedAt = &notifiedAt
+		notices = append(notices, Notice{Entry: *e, Providers: matched, Link: link})
+	}
+
+	return notices, changed
+}
+
+// matchProviders returns the names of providers streaming the title (flatrate
+// or free; rent and buy don't count) that satisfy the wanted filter, or
+// preferences.providers when the entry has none. With neither, any streaming
+// provider matches. Wanted names are resolved through tmdb.ProviderID so
+// aliases like "Disney+" and "Disney Plus" (or "HBO Max" and "Max") all match;
+// unknown names compare by name.
+func matchProviders(providers []tmdb.Provider, wanted []string) []string {
+	if len(wanted) == 0 {
+		wanted = config.Get().Preferences.Providers
+	}
+
+	var names []string
+	for _, p := range providers {
+		if p.Type != tmdb.MonetizationFlatrate && p.Type != tmdb.MonetizationFree {
+			continue
+		}
+		if len(wanted) == 0 {
+			names = append(names, p.Name)
+			continue
+		}
+		for _, w := range wanted {
+			id, known := tmdb.ProviderID(w)
+			if (known && p.ID == id) || strings.EqualFold(p.Name, w) {
+				names = append(names, p.Name)
+				break
+			}
+		}
+	}
+	return names
+}